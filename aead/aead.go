@@ -0,0 +1,107 @@
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	Version1 byte = 1
+
+	AES256GCM byte = 1
+	// ChaCha20Poly1305 uses the XChaCha20-Poly1305 construction, the 24 bytes nonce is safe to be generated randomly
+	ChaCha20Poly1305 byte = 2
+)
+
+var (
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+	ErrUnsupported       = errors.New("unsupported version or algorithm")
+)
+
+type Encrypter interface {
+	Encrypt(plaintext []byte, aad []byte) ([]byte, error)
+}
+type Decrypter interface {
+	Decrypt(ciphertext []byte, aad []byte) ([]byte, error)
+}
+
+// Cipher encrypts with the format: version (1 byte) | algorithm (1 byte) | nonce | ciphertext and tag.
+// The version and the algorithm are authenticated as the additional data, together with aad.
+type Cipher struct {
+	Algorithm byte
+	AEAD      cipher.AEAD
+}
+
+func NewCipher(algorithm byte, key []byte) (*Cipher, error) {
+	var a cipher.AEAD
+	switch algorithm {
+	case AES256GCM:
+		if len(key) != 32 {
+			return nil, fmt.Errorf("AES-256-GCM requires a 32 bytes key, got %d", len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		a, err = cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+	case ChaCha20Poly1305:
+		var err error
+		a, err = chacha20poly1305.NewX(key)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnsupported
+	}
+	return &Cipher{Algorithm: algorithm, AEAD: a}, nil
+}
+func NewAESGCM(key []byte) (*Cipher, error) {
+	return NewCipher(AES256GCM, key)
+}
+func NewChaCha20Poly1305(key []byte) (*Cipher, error) {
+	return NewCipher(ChaCha20Poly1305, key)
+}
+
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (c *Cipher) Encrypt(plaintext []byte, aad []byte) ([]byte, error) {
+	nonceSize := c.AEAD.NonceSize()
+	out := make([]byte, 2+nonceSize, 2+nonceSize+len(plaintext)+c.AEAD.Overhead())
+	out[0] = Version1
+	out[1] = c.Algorithm
+	if _, err := rand.Read(out[2:]); err != nil {
+		return nil, err
+	}
+	return c.AEAD.Seal(out, out[2:], plaintext, additionalData(out[:2], aad)), nil
+}
+func (c *Cipher) Decrypt(ciphertext []byte, aad []byte) ([]byte, error) {
+	nonceSize := c.AEAD.NonceSize()
+	if len(ciphertext) < 2+nonceSize+c.AEAD.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+	if ciphertext[0] != Version1 || ciphertext[1] != c.Algorithm {
+		return nil, ErrUnsupported
+	}
+	nonce := ciphertext[2 : 2+nonceSize]
+	return c.AEAD.Open(nil, nonce, ciphertext[2+nonceSize:], additionalData(ciphertext[:2], aad))
+}
+
+func additionalData(header []byte, aad []byte) []byte {
+	ad := make([]byte, 0, len(header)+len(aad))
+	ad = append(ad, header...)
+	return append(ad, aad...)
+}
@@ -0,0 +1,55 @@
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+)
+
+const Deterministic byte = 3
+
+// DeterministicCipher derives the nonce from the HMAC of the plaintext, so the same plaintext always has the same ciphertext.
+// It leaks equality, and should be used only for the fields which must be searched by equality.
+type DeterministicCipher struct {
+	AEAD   cipher.AEAD
+	MacKey []byte
+}
+
+// NewDeterministicCipher requires a 64 bytes key: the first half is the AES-256 key, the second half is the HMAC key.
+func NewDeterministicCipher(key []byte) (*DeterministicCipher, error) {
+	if len(key) != 64 {
+		return nil, fmt.Errorf("deterministic cipher requires a 64 bytes key, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, err
+	}
+	a, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &DeterministicCipher{AEAD: a, MacKey: key[32:]}, nil
+}
+
+func (c *DeterministicCipher) Encrypt(plaintext []byte, aad []byte) ([]byte, error) {
+	header := []byte{Version1, Deterministic}
+	mac := hmac.New(sha256.New, c.MacKey)
+	mac.Write(aad)
+	mac.Write(plaintext)
+	nonce := mac.Sum(nil)[:c.AEAD.NonceSize()]
+	out := append(header, nonce...)
+	return c.AEAD.Seal(out, nonce, plaintext, additionalData(header, aad)), nil
+}
+func (c *DeterministicCipher) Decrypt(ciphertext []byte, aad []byte) ([]byte, error) {
+	nonceSize := c.AEAD.NonceSize()
+	if len(ciphertext) < 2+nonceSize+c.AEAD.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+	if ciphertext[0] != Version1 || ciphertext[1] != Deterministic {
+		return nil, ErrUnsupported
+	}
+	nonce := ciphertext[2 : 2+nonceSize]
+	return c.AEAD.Open(nil, nonce, ciphertext[2+nonceSize:], additionalData(ciphertext[:2], aad))
+}
@@ -0,0 +1,36 @@
+package aead
+
+import "encoding/base64"
+
+type StringCipher struct {
+	Cipher interface {
+		Encrypter
+		Decrypter
+	}
+}
+
+func NewStringCipher(c interface {
+	Encrypter
+	Decrypter
+}) *StringCipher {
+	return &StringCipher{Cipher: c}
+}
+
+func (s *StringCipher) Encrypt(plaintext string) (string, error) {
+	bs, err := s.Cipher.Encrypt([]byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bs), nil
+}
+func (s *StringCipher) Decrypt(ciphertext string) (string, error) {
+	bs, err := base64.RawURLEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	plaintext, err := s.Cipher.Decrypt(bs, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+type UserRepository interface {
+	GetUser(ctx context.Context, username string) (*UserInfo, error)
+	Pass(ctx context.Context, userId string, loginTime time.Time) error
+	// Fail increments the fail count atomically. If maxAttempts is positive and the fail count reaches it, the user is locked until lockedUntil, the fail count is reset, and Fail returns true.
+	Fail(ctx context.Context, userId string, maxAttempts int, lockedUntil time.Time) (bool, error)
+}
+
+type PasswordChecker interface {
+	Check(ctx context.Context, user UserInfo, password string) (bool, error)
+}
+
+type TwoFactorService interface {
+	Send(ctx context.Context, user UserInfo) error
+	Verify(ctx context.Context, user UserInfo, code string) (bool, error)
+}
+
+type Authenticator struct {
+	Config        Config
+	Repository    UserRepository
+	Checker       PasswordChecker
+	TwoFactor     TwoFactorService
+	GenerateToken func(payload interface{}, secret string, expiresIn int64) (string, error)
+	// GenerateSessionId is set when the sessions are registered, the session id is added to the token by the claim Payload.SessionId
+	GenerateSessionId func(ctx context.Context) (string, error)
+}
+
+// NewAuthenticator creates an authenticator. If the repository is nil, the users are not tracked, so there is no lockout and no password expiry.
+func NewAuthenticator(c Config, repository UserRepository, checker PasswordChecker, generateToken func(interface{}, string, int64) (string, error), options ...TwoFactorService) *Authenticator {
+	var twoFactor TwoFactorService
+	if len(options) > 0 {
+		twoFactor = options[0]
+	}
+	return &Authenticator{Config: InitConfig(c), Repository: repository, Checker: checker, TwoFactor: twoFactor, GenerateToken: generateToken}
+}
+
+func (s *Authenticator) Authenticate(ctx context.Context, info AuthInfo) (AuthResult, error) {
+	username := strings.TrimSpace(info.Username)
+	if len(username) == 0 || len(info.Password) == 0 {
+		return AuthResult{Status: StatusFail}, nil
+	}
+	user := &UserInfo{Id: username, Username: username}
+	if s.Repository != nil {
+		u, err := s.Repository.GetUser(ctx, username)
+		if err != nil {
+			return AuthResult{Status: StatusSystemError}, err
+		}
+		if u == nil {
+			// compare the password anyway, so that the response time does not tell whether the user exists
+			if _, err = s.Checker.Check(ctx, UserInfo{Id: username, Username: username}, info.Password); err != nil {
+				return AuthResult{Status: StatusSystemError}, err
+			}
+			return AuthResult{Status: StatusFail}, nil
+		}
+		user = u
+	}
+	now := time.Now()
+	ok, err := s.Checker.Check(ctx, *user, info.Password)
+	if err != nil {
+		return AuthResult{Status: StatusSystemError}, err
+	}
+	if !ok {
+		return s.fail(ctx, user, StatusFail, now)
+	}
+	// the state of the account is only returned after the password is verified
+	if user.Disabled {
+		return AuthResult{Status: StatusDisabled}, nil
+	}
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return AuthResult{Status: StatusLocked}, nil
+	}
+	if s.Config.MaxAge > 0 && user.PasswordModifiedTime != nil {
+		expiredTime := user.PasswordModifiedTime.Add(s.Config.MaxAge)
+		if now.After(expiredTime) {
+			if err = s.pass(ctx, user, now); err != nil {
+				return AuthResult{Status: StatusSystemError}, err
+			}
+			account := &UserAccount{Id: user.Id, Username: user.Username, PasswordExpiredTime: &expiredTime}
+			return AuthResult{Status: StatusPasswordExpired, User: account}, nil
+		}
+	}
+	if s.TwoFactor != nil && user.TwoFactors {
+		if len(info.Passcode) == 0 {
+			if err = s.TwoFactor.Send(ctx, *user); err != nil {
+				return AuthResult{Status: StatusSystemError}, err
+			}
+			return AuthResult{Status: StatusTwoFactorRequired, User: &UserAccount{Id: user.Id, Username: user.Username}}, nil
+		}
+		valid, err := s.TwoFactor.Verify(ctx, *user, info.Passcode)
+		if err != nil {
+			return AuthResult{Status: StatusSystemError}, err
+		}
+		if !valid {
+			return s.fail(ctx, user, StatusWrongPasscode, now)
+		}
+	}
+	if err = s.pass(ctx, user, now); err != nil {
+		return AuthResult{Status: StatusSystemError}, err
+	}
+	account, err := s.issue(ctx, *user, now)
+	if err != nil {
+		return AuthResult{Status: StatusSystemError}, err
+	}
+	return AuthResult{Status: StatusSuccess, User: account}, nil
+}
+
+func (s *Authenticator) fail(ctx context.Context, user *UserInfo, status int, now time.Time) (AuthResult, error) {
+	if s.Repository == nil {
+		return AuthResult{Status: status}, nil
+	}
+	locked, err := s.Repository.Fail(ctx, user.Id, s.Config.MaxAttempts, now.Add(s.Config.LockTime))
+	if err != nil {
+		return AuthResult{Status: StatusSystemError}, err
+	}
+	// a wrong password does not tell that the user is locked, or an unknown user could be told apart from a locked one
+	if locked && status != StatusFail {
+		status = StatusLocked
+	}
+	return AuthResult{Status: status}, nil
+}
+func (s *Authenticator) pass(ctx context.Context, user *UserInfo, now time.Time) error {
+	if s.Repository == nil {
+		return nil
+	}
+	return s.Repository.Pass(ctx, user.Id, now)
+}
+func (s *Authenticator) issue(ctx context.Context, user UserInfo, now time.Time) (*UserAccount, error) {
+	account := &UserAccount{Id: user.Id, Username: user.Username, Email: user.Email, DisplayName: user.DisplayName, Roles: user.Roles}
+	if s.Config.MaxAge > 0 && user.PasswordModifiedTime != nil {
+		t := user.PasswordModifiedTime.Add(s.Config.MaxAge)
+		account.PasswordExpiredTime = &t
+	}
+	if s.GenerateToken == nil {
+		return account, nil
+	}
+	payload := BuildPayload(s.Config.Payload, user)
+	if s.GenerateSessionId != nil {
+		sessionId, err := s.GenerateSessionId(ctx)
+		if err != nil {
+			return nil, err
+		}
+		payload[s.Config.Payload.SessionId] = sessionId
+		account.SessionId = sessionId
+	}
+	token, err := s.GenerateToken(payload, s.Config.Token.Secret, s.Config.Token.Expires)
+	if err != nil {
+		return nil, err
+	}
+	expiredTime := now.Add(time.Duration(s.Config.Token.Expires) * time.Millisecond)
+	account.Token = token
+	account.TokenExpiredTime = &expiredTime
+	return account, nil
+}
+
+func BuildPayload(c PayloadConfig, user UserInfo) map[string]interface{} {
+	payload := make(map[string]interface{})
+	if len(c.Id) > 0 {
+		payload[c.Id] = user.Id
+	}
+	if len(c.Username) > 0 {
+		payload[c.Username] = user.Username
+	}
+	if len(c.Email) > 0 && len(user.Email) > 0 {
+		payload[c.Email] = user.Email
+	}
+	if len(c.Roles) > 0 && len(user.Roles) > 0 {
+		payload[c.Roles] = user.Roles
+	}
+	return payload
+}
+
+func StatusText(status int) string {
+	switch status {
+	case StatusSuccess:
+		return "success"
+	case StatusTwoFactorRequired:
+		return "two factors required"
+	case StatusFail:
+		return "fail"
+	case StatusWrongPasscode:
+		return "wrong passcode"
+	case StatusLocked:
+		return "locked"
+	case StatusPasswordExpired:
+		return "password expired"
+	case StatusDisabled:
+		return "disabled"
+	default:
+		return "system error"
+	}
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+	"sync"
+
+	"github.com/core-go/core/crypto"
+)
+
+// HashChecker compares the password with the hash stored in the user table.
+// If the user has no hash, the password is compared with a dummy hash, so that unknown users take as long as the known ones.
+type HashChecker struct {
+	Comparator crypto.StringComparator
+	once       sync.Once
+	dummy      string
+}
+
+func NewHashChecker(comparator crypto.StringComparator) *HashChecker {
+	return &HashChecker{Comparator: comparator}
+}
+
+func (c *HashChecker) Check(ctx context.Context, user UserInfo, password string) (bool, error) {
+	if len(user.Password) == 0 {
+		c.once.Do(func() {
+			c.dummy, _ = c.Comparator.Hash("dummy password")
+		})
+		if len(c.dummy) > 0 {
+			c.Comparator.Compare(password, c.dummy)
+		}
+		return false, nil
+	}
+	return c.Comparator.Compare(password, user.Password)
+}
@@ -0,0 +1,62 @@
+package auth
+
+import "time"
+
+// Config of Authenticator. MaxAttempts is 5 by default, a negative value disables the lockout.
+type Config struct {
+	Resource    string        `yaml:"resource" mapstructure:"resource" json:"resource,omitempty" gorm:"column:resource" bson:"resource,omitempty" dynamodbav:"resource,omitempty" firestore:"resource,omitempty"`
+	Action      string        `yaml:"action" mapstructure:"action" json:"action,omitempty" gorm:"column:action" bson:"action,omitempty" dynamodbav:"action,omitempty" firestore:"action,omitempty"`
+	MaxAttempts int           `yaml:"max_attempts" mapstructure:"max_attempts" json:"maxAttempts,omitempty" gorm:"column:maxattempts" bson:"maxAttempts,omitempty" dynamodbav:"maxAttempts,omitempty" firestore:"maxAttempts,omitempty"`
+	LockTime    time.Duration `yaml:"lock_time" mapstructure:"lock_time" json:"lockTime,omitempty" gorm:"column:locktime" bson:"lockTime,omitempty" dynamodbav:"lockTime,omitempty" firestore:"lockTime,omitempty"`
+	MaxAge      time.Duration `yaml:"max_age" mapstructure:"max_age" json:"maxAge,omitempty" gorm:"column:maxage" bson:"maxAge,omitempty" dynamodbav:"maxAge,omitempty" firestore:"maxAge,omitempty"`
+	Token       TokenConfig   `yaml:"token" mapstructure:"token" json:"token,omitempty" gorm:"column:token" bson:"token,omitempty" dynamodbav:"token,omitempty" firestore:"token,omitempty"`
+	Payload     PayloadConfig `yaml:"payload" mapstructure:"payload" json:"payload,omitempty" gorm:"column:payload" bson:"payload,omitempty" dynamodbav:"payload,omitempty" firestore:"payload,omitempty"`
+	Cookie      CookieConfig  `yaml:"cookie" mapstructure:"cookie" json:"cookie,omitempty" gorm:"column:cookie" bson:"cookie,omitempty" dynamodbav:"cookie,omitempty" firestore:"cookie,omitempty"`
+}
+
+type TokenConfig struct {
+	Secret  string `yaml:"secret" mapstructure:"secret" json:"-" gorm:"column:secret" bson:"secret,omitempty" dynamodbav:"secret,omitempty" firestore:"secret,omitempty"`
+	Expires int64  `yaml:"expires" mapstructure:"expires" json:"expires,omitempty" gorm:"column:expires" bson:"expires,omitempty" dynamodbav:"expires,omitempty" firestore:"expires,omitempty"`
+}
+
+type PayloadConfig struct {
+	Id        string `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id" bson:"id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	Username  string `yaml:"username" mapstructure:"username" json:"username,omitempty" gorm:"column:username" bson:"username,omitempty" dynamodbav:"username,omitempty" firestore:"username,omitempty"`
+	Email     string `yaml:"email" mapstructure:"email" json:"email,omitempty" gorm:"column:email" bson:"email,omitempty" dynamodbav:"email,omitempty" firestore:"email,omitempty"`
+	Roles     string `yaml:"roles" mapstructure:"roles" json:"roles,omitempty" gorm:"column:roles" bson:"roles,omitempty" dynamodbav:"roles,omitempty" firestore:"roles,omitempty"`
+	SessionId string `yaml:"session_id" mapstructure:"session_id" json:"sessionId,omitempty" gorm:"column:sessionid" bson:"sessionId,omitempty" dynamodbav:"sessionId,omitempty" firestore:"sessionId,omitempty"`
+}
+
+type CookieConfig struct {
+	Name     string `yaml:"name" mapstructure:"name" json:"name,omitempty" gorm:"column:name" bson:"name,omitempty" dynamodbav:"name,omitempty" firestore:"name,omitempty"`
+	Domain   string `yaml:"domain" mapstructure:"domain" json:"domain,omitempty" gorm:"column:domain" bson:"domain,omitempty" dynamodbav:"domain,omitempty" firestore:"domain,omitempty"`
+	SameSite string `yaml:"same_site" mapstructure:"same_site" json:"sameSite,omitempty" gorm:"column:samesite" bson:"sameSite,omitempty" dynamodbav:"sameSite,omitempty" firestore:"sameSite,omitempty"`
+}
+
+func InitConfig(c Config) Config {
+	if len(c.Resource) == 0 {
+		c.Resource = "authentication"
+	}
+	if len(c.Action) == 0 {
+		c.Action = "login"
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = 5
+	}
+	if c.LockTime <= 0 {
+		c.LockTime = 15 * time.Minute
+	}
+	if c.Token.Expires <= 0 {
+		c.Token.Expires = 86400000
+	}
+	if len(c.Payload.Id) == 0 {
+		c.Payload.Id = "userId"
+	}
+	if len(c.Payload.Username) == 0 {
+		c.Payload.Username = "username"
+	}
+	if len(c.Payload.SessionId) == 0 {
+		c.Payload.SessionId = "sid"
+	}
+	return c
+}
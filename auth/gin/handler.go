@@ -0,0 +1,57 @@
+package gin
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/core-go/core/auth"
+	"github.com/core-go/core/clientip"
+)
+
+type AuthenticationHandler struct {
+	Auth     func(ctx context.Context, info auth.AuthInfo) (auth.AuthResult, error)
+	Resource string
+	Action   string
+	Cookie   *http.Cookie
+	Registry auth.SessionRegistry
+	LogError func(context.Context, string, ...map[string]interface{})
+	WriteLog func(ctx context.Context, resource string, action string, success bool, desc string) error
+}
+
+func NewAuthenticationHandler(authenticate func(context.Context, auth.AuthInfo) (auth.AuthResult, error), logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *AuthenticationHandler {
+	resource := "authentication"
+	if len(options) > 0 && len(options[0]) > 0 {
+		resource = options[0]
+	}
+	action := "login"
+	if len(options) > 1 && len(options[1]) > 0 {
+		action = options[1]
+	}
+	return &AuthenticationHandler{Auth: authenticate, Resource: resource, Action: action, LogError: logError, WriteLog: writeLog}
+}
+
+func (h *AuthenticationHandler) Authenticate(ctx *gin.Context) {
+	r := ctx.Request
+	var info auth.AuthInfo
+	if err := ctx.ShouldBindJSON(&info); err != nil {
+		ctx.String(http.StatusBadRequest, "cannot decode authentication info: "+err.Error())
+		return
+	}
+	result, err := h.Auth(r.Context(), info)
+	if err != nil && h.LogError != nil {
+		h.LogError(r.Context(), "cannot authenticate: "+err.Error())
+	}
+	if er2 := auth.RegisterSession(h.Registry, r, &result); er2 != nil && h.LogError != nil {
+		h.LogError(r.Context(), "cannot register session: "+er2.Error())
+	}
+	if h.Cookie != nil && result.Status == auth.StatusSuccess && result.User != nil && len(result.User.Token) > 0 {
+		auth.SetTokenCookie(ctx.Writer, *h.Cookie, result.User.Token, result.User.TokenExpiredTime)
+		result.User.Token = ""
+	}
+	if h.WriteLog != nil {
+		h.WriteLog(r.Context(), h.Resource, h.Action, auth.Succeed(result.Status), auth.BuildDescription(info.Username, clientip.GetClientIp(r), result.Status))
+	}
+	ctx.JSON(auth.GetStatusCode(result.Status), result)
+}
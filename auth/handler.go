@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/core-go/core/clientip"
+)
+
+type AuthenticationHandler struct {
+	Auth     func(ctx context.Context, info AuthInfo) (AuthResult, error)
+	Resource string
+	Action   string
+	Cookie   *http.Cookie
+	Registry SessionRegistry
+	LogError func(context.Context, string, ...map[string]interface{})
+	WriteLog func(ctx context.Context, resource string, action string, success bool, desc string) error
+}
+
+func NewAuthenticationHandler(authenticate func(context.Context, AuthInfo) (AuthResult, error), logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *AuthenticationHandler {
+	resource := "authentication"
+	if len(options) > 0 && len(options[0]) > 0 {
+		resource = options[0]
+	}
+	action := "login"
+	if len(options) > 1 && len(options[1]) > 0 {
+		action = options[1]
+	}
+	return &AuthenticationHandler{Auth: authenticate, Resource: resource, Action: action, LogError: logError, WriteLog: writeLog}
+}
+
+// NewCookie builds the template of the token cookie. If it is set to the handler, the token is issued as a cookie instead of in the body.
+func NewCookie(c CookieConfig) *http.Cookie {
+	if len(c.Name) == 0 {
+		return nil
+	}
+	return &http.Cookie{Name: c.Name, Domain: c.Domain, Path: "/", HttpOnly: true, Secure: true, SameSite: ParseSameSite(c.SameSite)}
+}
+func ParseSameSite(s string) http.SameSite {
+	switch strings.ToLower(s) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	case "default":
+		return http.SameSiteDefaultMode
+	default:
+		return http.SameSiteStrictMode
+	}
+}
+
+func (h *AuthenticationHandler) Authenticate(w http.ResponseWriter, r *http.Request) {
+	var info AuthInfo
+	if err := json.NewDecoder(r.Body).Decode(&info); err != nil {
+		http.Error(w, "cannot decode authentication info: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := h.Auth(r.Context(), info)
+	if err != nil && h.LogError != nil {
+		h.LogError(r.Context(), "cannot authenticate: "+err.Error())
+	}
+	if er2 := RegisterSession(h.Registry, r, &result); er2 != nil && h.LogError != nil {
+		h.LogError(r.Context(), "cannot register session: "+er2.Error())
+	}
+	if h.Cookie != nil && result.Status == StatusSuccess && result.User != nil && len(result.User.Token) > 0 {
+		SetTokenCookie(w, *h.Cookie, result.User.Token, result.User.TokenExpiredTime)
+		result.User.Token = ""
+	}
+	if h.WriteLog != nil {
+		h.WriteLog(r.Context(), h.Resource, h.Action, Succeed(result.Status), BuildDescription(info.Username, clientip.GetClientIp(r), result.Status))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(GetStatusCode(result.Status))
+	json.NewEncoder(w).Encode(result)
+}
+
+func SetTokenCookie(w http.ResponseWriter, cookie http.Cookie, token string, expires *time.Time) {
+	cookie.Value = token
+	if expires != nil {
+		cookie.Expires = *expires
+	}
+	http.SetCookie(w, &cookie)
+}
+func Succeed(status int) bool {
+	return status == StatusSuccess
+}
+func BuildDescription(username string, ip string, status int) string {
+	return "username: " + username + ", ip: " + ip + ", status: " + StatusText(status)
+}
+func GetStatusCode(status int) int {
+	switch status {
+	case StatusSuccess:
+		return http.StatusOK
+	case StatusTwoFactorRequired:
+		return http.StatusAccepted
+	case StatusSystemError:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusUnauthorized
+	}
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+
+	ld "github.com/core-go/core/ldap"
+)
+
+// LdapChecker validates the password by a bind as the user. If UserDN is a template like "uid=%s,ou=people,dc=example,dc=com", the dn is built from the username,
+// otherwise the entry is searched by Config.Filter, using the service account of the config.
+type LdapChecker struct {
+	Config ld.LdapConfig
+	UserDN string
+}
+
+func NewLdapChecker(c ld.LdapConfig, options ...string) *LdapChecker {
+	if len(c.Filter) == 0 {
+		c.Filter = "uid"
+	}
+	var userDN string
+	if len(options) > 0 {
+		userDN = options[0]
+	}
+	return &LdapChecker{Config: c, UserDN: userDN}
+}
+
+func (c *LdapChecker) Check(ctx context.Context, user UserInfo, password string) (bool, error) {
+	if len(password) == 0 {
+		return false, nil
+	}
+	conn, err := ld.NewConn(c.Config)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	var dn string
+	if len(c.UserDN) > 0 {
+		dn = fmt.Sprintf(c.UserDN, ldap.EscapeDN(user.Username))
+	} else {
+		dn, err = c.find(conn, user.Username)
+		if err != nil || len(dn) == 0 {
+			return false, err
+		}
+	}
+	err = conn.Bind(dn, password)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *LdapChecker) find(conn *ldap.Conn, username string) (string, error) {
+	if len(c.Config.Username) > 0 {
+		if err := conn.Bind(c.Config.Username, c.Config.Password); err != nil {
+			return "", err
+		}
+	}
+	entry, err := ld.FindEntry(conn, c.Config, username)
+	if err != nil || entry == nil {
+		return "", err
+	}
+	return entry.DN, nil
+}
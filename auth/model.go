@@ -0,0 +1,52 @@
+package auth
+
+import "time"
+
+const (
+	StatusSuccess           = 0
+	StatusTwoFactorRequired = 1
+	StatusFail              = 2
+	StatusWrongPasscode     = 3
+	StatusLocked            = 4
+	StatusPasswordExpired   = 5
+	StatusDisabled          = 6
+	StatusSystemError       = 7
+)
+
+type AuthInfo struct {
+	Username string `yaml:"username" mapstructure:"username" json:"username,omitempty" gorm:"column:username" bson:"username,omitempty" dynamodbav:"username,omitempty" firestore:"username,omitempty"`
+	Password string `yaml:"password" mapstructure:"password" json:"password,omitempty" gorm:"column:password" bson:"password,omitempty" dynamodbav:"password,omitempty" firestore:"password,omitempty"`
+	Passcode string `yaml:"passcode" mapstructure:"passcode" json:"passcode,omitempty" gorm:"column:passcode" bson:"passcode,omitempty" dynamodbav:"passcode,omitempty" firestore:"passcode,omitempty"`
+}
+
+type UserInfo struct {
+	Id                   string     `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	Username             string     `yaml:"username" mapstructure:"username" json:"username,omitempty" gorm:"column:username" bson:"username,omitempty" dynamodbav:"username,omitempty" firestore:"username,omitempty"`
+	Email                string     `yaml:"email" mapstructure:"email" json:"email,omitempty" gorm:"column:email" bson:"email,omitempty" dynamodbav:"email,omitempty" firestore:"email,omitempty"`
+	DisplayName          string     `yaml:"display_name" mapstructure:"display_name" json:"displayName,omitempty" gorm:"column:displayname" bson:"displayName,omitempty" dynamodbav:"displayName,omitempty" firestore:"displayName,omitempty"`
+	Password             string     `yaml:"password" mapstructure:"password" json:"password,omitempty" gorm:"column:password" bson:"password,omitempty" dynamodbav:"password,omitempty" firestore:"password,omitempty"`
+	Disabled             bool       `yaml:"disabled" mapstructure:"disabled" json:"disabled,omitempty" gorm:"column:disabled" bson:"disabled,omitempty" dynamodbav:"disabled,omitempty" firestore:"disabled,omitempty"`
+	FailCount            int        `yaml:"fail_count" mapstructure:"fail_count" json:"failCount,omitempty" gorm:"column:failcount" bson:"failCount,omitempty" dynamodbav:"failCount,omitempty" firestore:"failCount,omitempty"`
+	LockedUntil          *time.Time `yaml:"locked_until" mapstructure:"locked_until" json:"lockedUntil,omitempty" gorm:"column:lockeduntil" bson:"lockedUntil,omitempty" dynamodbav:"lockedUntil,omitempty" firestore:"lockedUntil,omitempty"`
+	PasswordModifiedTime *time.Time `yaml:"password_modified_time" mapstructure:"password_modified_time" json:"passwordModifiedTime,omitempty" gorm:"column:passwordmodifiedtime" bson:"passwordModifiedTime,omitempty" dynamodbav:"passwordModifiedTime,omitempty" firestore:"passwordModifiedTime,omitempty"`
+	TwoFactors           bool       `yaml:"two_factors" mapstructure:"two_factors" json:"twoFactors,omitempty" gorm:"column:twofactors" bson:"twoFactors,omitempty" dynamodbav:"twoFactors,omitempty" firestore:"twoFactors,omitempty"`
+	Roles                []string   `yaml:"roles" mapstructure:"roles" json:"roles,omitempty" gorm:"column:roles" bson:"roles,omitempty" dynamodbav:"roles,omitempty" firestore:"roles,omitempty"`
+}
+
+type UserAccount struct {
+	Id                  string     `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	Username            string     `yaml:"username" mapstructure:"username" json:"username,omitempty" gorm:"column:username" bson:"username,omitempty" dynamodbav:"username,omitempty" firestore:"username,omitempty"`
+	Email               string     `yaml:"email" mapstructure:"email" json:"email,omitempty" gorm:"column:email" bson:"email,omitempty" dynamodbav:"email,omitempty" firestore:"email,omitempty"`
+	DisplayName         string     `yaml:"display_name" mapstructure:"display_name" json:"displayName,omitempty" gorm:"column:displayname" bson:"displayName,omitempty" dynamodbav:"displayName,omitempty" firestore:"displayName,omitempty"`
+	Token               string     `yaml:"token" mapstructure:"token" json:"token,omitempty" gorm:"column:token" bson:"token,omitempty" dynamodbav:"token,omitempty" firestore:"token,omitempty"`
+	TokenExpiredTime    *time.Time `yaml:"token_expired_time" mapstructure:"token_expired_time" json:"tokenExpiredTime,omitempty" gorm:"column:tokenexpiredtime" bson:"tokenExpiredTime,omitempty" dynamodbav:"tokenExpiredTime,omitempty" firestore:"tokenExpiredTime,omitempty"`
+	PasswordExpiredTime *time.Time `yaml:"password_expired_time" mapstructure:"password_expired_time" json:"passwordExpiredTime,omitempty" gorm:"column:passwordexpiredtime" bson:"passwordExpiredTime,omitempty" dynamodbav:"passwordExpiredTime,omitempty" firestore:"passwordExpiredTime,omitempty"`
+	Roles               []string   `yaml:"roles" mapstructure:"roles" json:"roles,omitempty" gorm:"column:roles" bson:"roles,omitempty" dynamodbav:"roles,omitempty" firestore:"roles,omitempty"`
+	SessionId           string     `yaml:"-" mapstructure:"-" json:"-" gorm:"-" bson:"-" dynamodbav:"-" firestore:"-"`
+}
+
+type AuthResult struct {
+	Status  int          `yaml:"status" mapstructure:"status" json:"status" gorm:"column:status" bson:"status" dynamodbav:"status" firestore:"status"`
+	User    *UserAccount `yaml:"user" mapstructure:"user" json:"user,omitempty" gorm:"column:user" bson:"user,omitempty" dynamodbav:"user,omitempty" firestore:"user,omitempty"`
+	Message string       `yaml:"message" mapstructure:"message" json:"message,omitempty" gorm:"column:message" bson:"message,omitempty" dynamodbav:"message,omitempty" firestore:"message,omitempty"`
+}
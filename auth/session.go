@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// SessionRegistry records the session of a successful login, so the session checkers accept its token. It is implemented by security.SessionRegistry.
+type SessionRegistry interface {
+	Register(ctx context.Context, userId string, sessionId string, r *http.Request) error
+}
+
+// GenerateSessionId can be used as GenerateSessionId of Authenticator.
+func GenerateSessionId(ctx context.Context) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// RegisterSession registers the session of a successful login. If the session is not registered, the result is changed to a system error, because the token would be rejected.
+func RegisterSession(registry SessionRegistry, r *http.Request, result *AuthResult) error {
+	if registry == nil || result.Status != StatusSuccess || result.User == nil || len(result.User.SessionId) == 0 {
+		return nil
+	}
+	if err := registry.Register(r.Context(), result.User.Id, result.User.SessionId, r); err != nil {
+		*result = AuthResult{Status: StatusSystemError}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/core-go/core/auth"
+)
+
+const (
+	driverPostgres   = "postgres"
+	driverMysql      = "mysql"
+	driverMssql      = "mssql"
+	driverOracle     = "oracle"
+	driverSqlite3    = "sqlite3"
+	driverNotSupport = "no support"
+)
+
+type UserRepository struct {
+	DB                   *sql.DB
+	Table                string
+	RoleQuery            string
+	Id                   string
+	Username             string
+	Email                string
+	DisplayName          string
+	Password             string
+	Disabled             string
+	FailCount            string
+	LockedUntil          string
+	PasswordModifiedTime string
+	TwoFactors           string
+	LastLogin            string
+	Driver               string
+}
+
+// NewUserRepository creates the repository of the login flow. The role query is optional, and must return the role ids of a user id.
+func NewUserRepository(db *sql.DB, table string, roleQuery string, options ...string) *UserRepository {
+	columns := []string{"id", "username", "email", "display_name", "password", "disabled", "fail_count", "locked_until", "password_modified_time", "two_factors", "last_login"}
+	for i := 0; i < len(options) && i < len(columns); i++ {
+		if len(options[i]) > 0 {
+			columns[i] = options[i]
+		}
+	}
+	driver := getDriver(db)
+	if len(roleQuery) > 0 {
+		roleQuery = replaceQueryArgs(driver, roleQuery)
+	}
+	return &UserRepository{DB: db, Table: table, RoleQuery: roleQuery, Id: columns[0], Username: columns[1], Email: columns[2], DisplayName: columns[3], Password: columns[4], Disabled: columns[5],
+		FailCount: columns[6], LockedUntil: columns[7], PasswordModifiedTime: columns[8], TwoFactors: columns[9], LastLogin: columns[10], Driver: driver}
+}
+
+func (r *UserRepository) GetUser(ctx context.Context, username string) (*auth.UserInfo, error) {
+	columns := strings.Join([]string{r.Id, r.Username, r.Email, r.DisplayName, r.Password, r.Disabled, r.FailCount, r.LockedUntil, r.PasswordModifiedTime, r.TwoFactors}, ",")
+	query := replaceQueryArgs(r.Driver, fmt.Sprintf("select %s from %s where %s = ?", columns, r.Table, r.Username))
+	rows, err := r.DB.QueryContext(ctx, query, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	var user auth.UserInfo
+	var email, displayName, password sql.NullString
+	var disabled, twoFactors sql.NullBool
+	var failCount sql.NullInt64
+	var lockedUntil, passwordModifiedTime sql.NullTime
+	if err = rows.Scan(&user.Id, &user.Username, &email, &displayName, &password, &disabled, &failCount, &lockedUntil, &passwordModifiedTime, &twoFactors); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	user.Email = email.String
+	user.DisplayName = displayName.String
+	user.Password = password.String
+	user.Disabled = disabled.Bool
+	user.FailCount = int(failCount.Int64)
+	user.LockedUntil = fromNullTime(lockedUntil)
+	user.PasswordModifiedTime = fromNullTime(passwordModifiedTime)
+	user.TwoFactors = twoFactors.Bool
+	if len(r.RoleQuery) > 0 {
+		roles, err := r.getRoles(ctx, user.Id)
+		if err != nil {
+			return nil, err
+		}
+		user.Roles = roles
+	}
+	return &user, nil
+}
+func (r *UserRepository) getRoles(ctx context.Context, userId string) ([]string, error) {
+	rows, err := r.DB.QueryContext(ctx, r.RoleQuery, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	roles := make([]string, 0)
+	for rows.Next() {
+		var role string
+		if err = rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *UserRepository) Pass(ctx context.Context, userId string, loginTime time.Time) error {
+	query := replaceQueryArgs(r.Driver, fmt.Sprintf("update %s set %s = 0, %s = null, %s = ? where %s = ?", r.Table, r.FailCount, r.LockedUntil, r.LastLogin, r.Id))
+	_, err := r.DB.ExecContext(ctx, query, loginTime, userId)
+	return err
+}
+
+// Fail increments the fail count in the database, so the concurrent failed logins are all counted, and only one of them locks the user.
+func (r *UserRepository) Fail(ctx context.Context, userId string, maxAttempts int, lockedUntil time.Time) (bool, error) {
+	query := replaceQueryArgs(r.Driver, fmt.Sprintf("update %s set %s = coalesce(%s, 0) + 1 where %s = ?", r.Table, r.FailCount, r.FailCount, r.Id))
+	if _, err := r.DB.ExecContext(ctx, query, userId); err != nil || maxAttempts <= 0 {
+		return false, err
+	}
+	query = replaceQueryArgs(r.Driver, fmt.Sprintf("update %s set %s = 0, %s = ? where %s = ? and %s >= ?", r.Table, r.FailCount, r.LockedUntil, r.Id, r.FailCount))
+	res, err := r.DB.ExecContext(ctx, query, lockedUntil, userId, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func fromNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
+
+func replaceQueryArgs(driver string, query string) string {
+	if driver == driverOracle || driver == driverPostgres || driver == driverMssql {
+		var x string
+		if driver == driverOracle {
+			x = ":val"
+		} else if driver == driverPostgres {
+			x = "$"
+		} else if driver == driverMssql {
+			x = "@p"
+		}
+		i := 1
+		k := strings.Index(query, "?")
+		if k >= 0 {
+			for {
+				query = strings.Replace(query, "?", x+fmt.Sprintf("%v", i), 1)
+				i = i + 1
+				k := strings.Index(query, "?")
+				if k < 0 {
+					return query
+				}
+			}
+		}
+	}
+	return query
+}
+
+func getDriver(db *sql.DB) string {
+	if db == nil {
+		return driverNotSupport
+	}
+	driver := reflect.TypeOf(db.Driver()).String()
+	switch driver {
+	case "*pq.Driver":
+		return driverPostgres
+	case "*godror.drv":
+		return driverOracle
+	case "*mysql.MySQLDriver":
+		return driverMysql
+	case "*mssql.Driver":
+		return driverMssql
+	case "*sqlite3.SQLiteDriver":
+		return driverSqlite3
+	default:
+		return driverNotSupport
+	}
+}
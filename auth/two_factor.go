@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/core-go/core/passcode"
+)
+
+// OtpTwoFactor verifies the codes of an authenticator app. There is nothing to send.
+type OtpTwoFactor struct {
+	Service *passcode.OtpService
+}
+
+func NewOtpTwoFactor(service *passcode.OtpService) *OtpTwoFactor {
+	return &OtpTwoFactor{Service: service}
+}
+
+func (s *OtpTwoFactor) Send(ctx context.Context, user UserInfo) error {
+	return nil
+}
+func (s *OtpTwoFactor) Verify(ctx context.Context, user UserInfo, code string) (bool, error) {
+	ok, err := s.Service.Verify(ctx, user.Id, code)
+	if err != nil || ok {
+		return ok, err
+	}
+	return s.Service.Recover(ctx, user.Id, code)
+}
+
+// PasscodeTwoFactor sends a one time passcode to the email of the user.
+type PasscodeTwoFactor struct {
+	Service *passcode.VerificationService
+}
+
+func NewPasscodeTwoFactor(service *passcode.VerificationService) *PasscodeTwoFactor {
+	return &PasscodeTwoFactor{Service: service}
+}
+
+func (s *PasscodeTwoFactor) Send(ctx context.Context, user UserInfo) error {
+	return s.Service.Send(ctx, user.Id, user.Email, user)
+}
+func (s *PasscodeTwoFactor) Verify(ctx context.Context, user UserInfo, code string) (bool, error) {
+	ok, err := s.Service.Verify(ctx, user.Id, code)
+	if errors.Is(err, passcode.ErrLocked) {
+		return false, nil
+	}
+	return ok, err
+}
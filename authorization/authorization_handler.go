@@ -2,8 +2,9 @@ package authorization
 
 import (
 	"context"
-	"net"
 	"net/http"
+
+	"github.com/core-go/core/clientip"
 )
 
 type Handler struct {
@@ -71,9 +72,5 @@ func (c *Handler) HandleAuthorization(next http.Handler) http.Handler {
 }
 
 func GetRemoteIp(r *http.Request) string {
-	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		remoteIP = r.RemoteAddr
-	}
-	return remoteIP
+	return clientip.GetClientIp(r)
 }
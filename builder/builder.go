@@ -15,8 +15,12 @@ type TrackingConfig struct {
 	UpdatedBy     string `yaml:"updated_by" mapstructure:"updated_by" json:"updatedBy,omitempty" gorm:"column:updatedby" bson:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty" firestore:"updatedBy,omitempty"`
 	UpdatedAt     string `yaml:"updated_at" mapstructure:"updated_at" json:"updatedAt,omitempty" gorm:"column:updatedat" bson:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty" firestore:"updatedAt,omitempty"`
 }
+
+// Builder sets the tracking fields on create and update. If Encrypt is set, such as FieldEncrypter.Encrypt of the encrypt package, the model is encrypted in place after the tracking fields are set.
+// Do not set Encrypt when the repository is an EncryptedRepository, which encrypts a copy of the model.
 type Builder[T any] struct {
 	GenerateId     func(ctx context.Context, model *T) (int, error)
+	Encrypt        func(obj interface{}) error
 	Authorization  string
 	Key            string
 	modelType      reflect.Type
@@ -185,6 +189,9 @@ func (c *Builder[T]) Create(ctx context.Context, obj *T) error {
 			}
 		}
 	}
+	if c.Encrypt != nil && v.Kind() == reflect.Struct {
+		return c.Encrypt(obj)
+	}
 	return nil
 }
 
@@ -234,7 +241,9 @@ func (c *Builder[T]) Update(ctx context.Context, obj *T) error {
 			}
 		}
 	}
-
+	if c.Encrypt != nil && v.Kind() == reflect.Struct {
+		return c.Encrypt(obj)
+	}
 	return nil
 }
 
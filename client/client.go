@@ -14,6 +14,9 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/core-go/core/mask"
+	"github.com/core-go/core/trace"
 )
 
 type ClientConfig struct {
@@ -36,6 +39,7 @@ type Config struct {
 	CertFile string         `yaml:"cert_file" mapstructure:"cert_file" json:"certFile,omitempty" gorm:"column:certfile" bson:"certFile,omitempty" dynamodbav:"certFile,omitempty" firestore:"certFile,omitempty"`
 	KeyFile  string         `yaml:"key_file" mapstructure:"key_file" json:"keyFile,omitempty" gorm:"column:keyfile" bson:"keyFile,omitempty" dynamodbav:"keyFile,omitempty" firestore:"keyFile,omitempty"`
 	PEMFile  bool           `yaml:"pem_file" mapstructure:"pem_file" json:"pemFile,omitempty" gorm:"column:pemFile" bson:"pemFile,omitempty" dynamodbav:"pemFile,omitempty" firestore:"pemFile,omitempty"`
+	Trace    bool           `yaml:"trace" mapstructure:"trace" json:"trace,omitempty" gorm:"column:trace" bson:"trace,omitempty" dynamodbav:"trace,omitempty" firestore:"trace,omitempty"`
 	Url      string         `yaml:"url" mapstructure:"url" json:"url,omitempty" gorm:"column:url" bson:"url,omitempty" dynamodbav:"url,omitempty" firestore:"url,omitempty"`
 	Username *string        `yaml:"username" mapstructure:"username" json:"username,omitempty" gorm:"column:username" bson:"username,omitempty" dynamodbav:"username,omitempty" firestore:"username,omitempty"`
 	Password *string        `yaml:"password" mapstructure:"password" json:"password,omitempty" gorm:"column:password" bson:"password,omitempty" dynamodbav:"password,omitempty" firestore:"password,omitempty"`
@@ -46,16 +50,19 @@ type Conf struct {
 	CertFile string         `yaml:"cert_file" mapstructure:"cert_file" json:"certFile,omitempty" gorm:"column:certfile" bson:"certFile,omitempty" dynamodbav:"certFile,omitempty" firestore:"certFile,omitempty"`
 	KeyFile  string         `yaml:"key_file" mapstructure:"key_file" json:"keyFile,omitempty" gorm:"column:keyfile" bson:"keyFile,omitempty" dynamodbav:"keyFile,omitempty" firestore:"keyFile,omitempty"`
 	PEMFile  bool           `yaml:"pem_file" mapstructure:"pem_file" json:"pemFile,omitempty" gorm:"column:pemFile" bson:"pemFile,omitempty" dynamodbav:"pemFile,omitempty" firestore:"pemFile,omitempty"`
+	Trace    bool           `yaml:"trace" mapstructure:"trace" json:"trace,omitempty" gorm:"column:trace" bson:"trace,omitempty" dynamodbav:"trace,omitempty" firestore:"trace,omitempty"`
 }
 type LogConfig struct {
-	Separate       bool   `yaml:"separate" mapstructure:"separate" json:"separate,omitempty" gorm:"column:separate" bson:"separate,omitempty" dynamodbav:"separate,omitempty" firestore:"separate,omitempty"`
-	Log            bool   `yaml:"log" mapstructure:"log" json:"log,omitempty" gorm:"column:log" bson:"log,omitempty" dynamodbav:"log,omitempty" firestore:"log,omitempty"`
-	Duration       string `yaml:"duration" mapstructure:"duration" json:"duration,omitempty" gorm:"column:duration" bson:"duration,omitempty" dynamodbav:"duration,omitempty" firestore:"duration,omitempty"`
-	Size           string `yaml:"size" mapstructure:"size" json:"size,omitempty" gorm:"column:size" bson:"size,omitempty" dynamodbav:"size,omitempty" firestore:"size,omitempty"`
-	ResponseStatus string `yaml:"status" mapstructure:"status" json:"status,omitempty" gorm:"column:status" bson:"status,omitempty" dynamodbav:"status,omitempty" firestore:"status,omitempty"`
-	Request        string `yaml:"request" mapstructure:"request" json:"request,omitempty" gorm:"column:request" bson:"request,omitempty" dynamodbav:"request,omitempty" firestore:"request,omitempty"`
-	Response       string `yaml:"response" mapstructure:"response" json:"response,omitempty" gorm:"column:response" bson:"response,omitempty" dynamodbav:"response,omitempty" firestore:"response,omitempty"`
-	Error          string `yaml:"error" mapstructure:"error" json:"error,omitempty" gorm:"column:error" bson:"error,omitempty" dynamodbav:"error,omitempty" firestore:"error,omitempty"`
+	Separate       bool         `yaml:"separate" mapstructure:"separate" json:"separate,omitempty" gorm:"column:separate" bson:"separate,omitempty" dynamodbav:"separate,omitempty" firestore:"separate,omitempty"`
+	Log            bool         `yaml:"log" mapstructure:"log" json:"log,omitempty" gorm:"column:log" bson:"log,omitempty" dynamodbav:"log,omitempty" firestore:"log,omitempty"`
+	Duration       string       `yaml:"duration" mapstructure:"duration" json:"duration,omitempty" gorm:"column:duration" bson:"duration,omitempty" dynamodbav:"duration,omitempty" firestore:"duration,omitempty"`
+	Size           string       `yaml:"size" mapstructure:"size" json:"size,omitempty" gorm:"column:size" bson:"size,omitempty" dynamodbav:"size,omitempty" firestore:"size,omitempty"`
+	ResponseStatus string       `yaml:"status" mapstructure:"status" json:"status,omitempty" gorm:"column:status" bson:"status,omitempty" dynamodbav:"status,omitempty" firestore:"status,omitempty"`
+	Request        string       `yaml:"request" mapstructure:"request" json:"request,omitempty" gorm:"column:request" bson:"request,omitempty" dynamodbav:"request,omitempty" firestore:"request,omitempty"`
+	Response       string       `yaml:"response" mapstructure:"response" json:"response,omitempty" gorm:"column:response" bson:"response,omitempty" dynamodbav:"response,omitempty" firestore:"response,omitempty"`
+	Error          string       `yaml:"error" mapstructure:"error" json:"error,omitempty" gorm:"column:error" bson:"error,omitempty" dynamodbav:"error,omitempty" firestore:"error,omitempty"`
+	Mask           *mask.Config `yaml:"mask" mapstructure:"mask" json:"mask,omitempty" gorm:"column:mask" bson:"mask,omitempty" dynamodbav:"mask,omitempty" firestore:"mask,omitempty"`
+	masker         *mask.Masker
 }
 type Params struct {
 	Client   *http.Client
@@ -80,7 +87,16 @@ var sClient *http.Client
 func SetClient(c *http.Client) {
 	sClient = c
 }
+
+// InitializeLog panics if the mask config is invalid. InitializeLogWithError returns the error instead.
 func InitializeLog(c *LogConfig) *LogConfig {
+	l, err := InitializeLogWithError(c)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+func InitializeLogWithError(c *LogConfig) (*LogConfig, error) {
 	var c2 LogConfig
 	if c == nil {
 		c2.Log = true
@@ -88,7 +104,7 @@ func InitializeLog(c *LogConfig) *LogConfig {
 		c2.Size = "size"
 		c2.Duration = "duration"
 		c2.Error = "error"
-		return &c2
+		return &c2, nil
 	}
 	c2.Log = c.Log
 	c2.Separate = c.Separate
@@ -106,7 +122,15 @@ func InitializeLog(c *LogConfig) *LogConfig {
 	}
 	c2.Request = c.Request
 	c2.Response = c.Response
-	return &c2
+	c2.Mask = c.Mask
+	if c.Mask != nil {
+		m, err := mask.NewMasker(*c.Mask)
+		if err != nil {
+			return nil, err
+		}
+		c2.masker = m
+	}
+	return &c2, nil
 }
 func InitializeParams(config ClientConfig, opts ...func(context.Context, string, map[string]interface{})) (*Params, error) {
 	c, header, conf, err := InitializeClient(config)
@@ -146,13 +170,17 @@ func InitializeClient(config ClientConfig) (*http.Client, map[string]string, *Lo
 		CertFile: e.CertFile,
 		KeyFile:  e.KeyFile,
 		PEMFile:  e.PEMFile,
+		Trace:    e.Trace,
 	}
 	c, err := NewClient(conf)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	header := CreateHeaderFromConfig(config.Endpoint)
-	l := InitializeLog(config.Log)
+	l, err := InitializeLogWithError(config.Log)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	return c, header, l, nil
 }
 func InitClient(config ClientConf) (*http.Client, map[string]string, *LogConfig, error) {
@@ -161,10 +189,23 @@ func InitClient(config ClientConf) (*http.Client, map[string]string, *LogConfig,
 		return nil, nil, nil, err
 	}
 	header := CreateHeaderFromConf(config.Endpoint)
-	l := InitializeLog(config.Log)
+	l, err := InitializeLogWithError(config.Log)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	return c, header, l, nil
 }
+
+// NewClient creates the http client. If Trace is true, the requests are sent in client spans, and traceparent is sent to the server.
 func NewClient(c Conf) (*http.Client, error) {
+	client, err := newClient(c)
+	if err != nil || !c.Trace {
+		return client, err
+	}
+	client.Transport = trace.NewTransport(client.Transport)
+	return client, nil
+}
+func newClient(c Conf) (*http.Client, error) {
 	if len(c.CertFile) > 0 && len(c.KeyFile) > 0 {
 		return NewTLSClient(c.CertFile, c.KeyFile, c.Timeout)
 	} else {
@@ -481,6 +522,9 @@ func DoAndBuildDecoder(ctx context.Context, client *http.Client, method string,
 	if len(options) > 1 {
 		logInfo = options[1]
 	}
+	if conf != nil && conf.masker != nil {
+		logError, logInfo = maskLog(conf, logError), maskLog(conf, logInfo)
+	}
 	start := time.Now()
 	res, er1 := DoJSON(ctx, client, method, url, body, headers)
 	end := time.Now()
@@ -657,6 +701,9 @@ func DoAndLog(ctx context.Context, client *http.Client, method string, url strin
 	if len(options) > 1 {
 		logInfo = options[1]
 	}
+	if conf != nil && conf.masker != nil {
+		logError, logInfo = maskLog(conf, logError), maskLog(conf, logInfo)
+	}
 	start := time.Now()
 	res, er1 := DoJSON(ctx, client, method, url, body, headers)
 	end := time.Now()
@@ -832,6 +879,9 @@ func DoAndLogCommon(ctx context.Context, client *http.Client, method string, url
 	if len(options) > 1 {
 		logInfo = options[1]
 	}
+	if conf != nil && conf.masker != nil {
+		logError, logInfo = maskLog(conf, logError), maskLog(conf, logInfo)
+	}
 	start := time.Now()
 	res, er1 := DoRequest(ctx, client, method, url, body, headers)
 	end := time.Now()
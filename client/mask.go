@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"strings"
+)
+
+// maskLog masks the url, the request, the response and the error by LogConfig.Mask before logging.
+func maskLog(conf *LogConfig, log func(context.Context, string, map[string]interface{})) func(context.Context, string, map[string]interface{}) {
+	if log == nil {
+		return nil
+	}
+	return func(ctx context.Context, msg string, fields map[string]interface{}) {
+		for _, k := range []string{conf.Request, conf.Response, conf.Error} {
+			if len(k) == 0 {
+				continue
+			}
+			if s, ok := fields[k].(string); ok {
+				fields[k] = conf.masker.MaskBody(s)
+			}
+		}
+		if i := strings.Index(msg, " "); i >= 0 {
+			msg = msg[:i+1] + conf.masker.MaskURI(msg[i+1:])
+		}
+		log(ctx, msg, fields)
+	}
+}
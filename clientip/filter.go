@@ -0,0 +1,100 @@
+package clientip
+
+import (
+	"net"
+	"strings"
+
+	"github.com/core-go/core/paths"
+)
+
+type FilterConfig struct {
+	Allow  []string            `yaml:"allow" mapstructure:"allow" json:"allow,omitempty" gorm:"column:allow" bson:"allow,omitempty" dynamodbav:"allow,omitempty" firestore:"allow,omitempty"`
+	Deny   []string            `yaml:"deny" mapstructure:"deny" json:"deny,omitempty" gorm:"column:deny" bson:"deny,omitempty" dynamodbav:"deny,omitempty" firestore:"deny,omitempty"`
+	Routes []RouteFilterConfig `yaml:"routes" mapstructure:"routes" json:"routes,omitempty" gorm:"column:routes" bson:"routes,omitempty" dynamodbav:"routes,omitempty" firestore:"routes,omitempty"`
+}
+
+type RouteFilterConfig struct {
+	Method string   `yaml:"method" mapstructure:"method" json:"method,omitempty" gorm:"column:method" bson:"method,omitempty" dynamodbav:"method,omitempty" firestore:"method,omitempty"`
+	Path   string   `yaml:"path" mapstructure:"path" json:"path,omitempty" gorm:"column:path" bson:"path,omitempty" dynamodbav:"path,omitempty" firestore:"path,omitempty"`
+	Allow  []string `yaml:"allow" mapstructure:"allow" json:"allow,omitempty" gorm:"column:allow" bson:"allow,omitempty" dynamodbav:"allow,omitempty" firestore:"allow,omitempty"`
+	Deny   []string `yaml:"deny" mapstructure:"deny" json:"deny,omitempty" gorm:"column:deny" bson:"deny,omitempty" dynamodbav:"deny,omitempty" firestore:"deny,omitempty"`
+}
+
+type List struct {
+	Allow []*net.IPNet
+	Deny  []*net.IPNet
+}
+
+type RouteList struct {
+	Method string
+	Path   string
+	List
+}
+
+// Filter checks the client ip against the allow and deny lists. The first matching route overrides the global lists.
+type Filter struct {
+	List
+	Routes []RouteList
+}
+
+func NewFilter(c FilterConfig) (*Filter, error) {
+	list, err := NewList(c.Allow, c.Deny)
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]RouteList, 0, len(c.Routes))
+	for _, rc := range c.Routes {
+		l, err := NewList(rc.Allow, rc.Deny)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, RouteList{Method: rc.Method, Path: rc.Path, List: l})
+	}
+	return &Filter{List: list, Routes: routes}, nil
+}
+
+func NewList(allow []string, deny []string) (List, error) {
+	a, err := ParseCIDRs(allow)
+	if err != nil {
+		return List{}, err
+	}
+	d, err := ParseCIDRs(deny)
+	if err != nil {
+		return List{}, err
+	}
+	return List{Allow: a, Deny: d}, nil
+}
+
+// Allowed returns false if the ip is denied, or if there is an allow list and the ip is not in it.
+func (l List) Allowed(ip string) bool {
+	if len(l.Allow) == 0 && len(l.Deny) == 0 {
+		return true
+	}
+	netIp := net.ParseIP(ip)
+	if netIp == nil {
+		return false
+	}
+	if Contains(l.Deny, netIp) {
+		return false
+	}
+	if len(l.Allow) > 0 {
+		return Contains(l.Allow, netIp)
+	}
+	return true
+}
+
+func (f *Filter) Resolve(method string, path string) List {
+	for _, route := range f.Routes {
+		if len(route.Method) > 0 && !strings.EqualFold(route.Method, method) {
+			continue
+		}
+		if paths.MatchPath(route.Path, path) {
+			return route.List
+		}
+	}
+	return f.List
+}
+
+func (f *Filter) Allowed(method string, path string, ip string) bool {
+	return f.Resolve(method, path).Allowed(ip)
+}
@@ -0,0 +1,187 @@
+package clientip
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	HeaderForwarded     = "Forwarded"
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderXRealIp       = "X-Real-Ip"
+)
+
+type Config struct {
+	TrustedProxies []string `yaml:"trusted_proxies" mapstructure:"trusted_proxies" json:"trustedProxies,omitempty" gorm:"column:trustedproxies" bson:"trustedProxies,omitempty" dynamodbav:"trustedProxies,omitempty" firestore:"trustedProxies,omitempty"`
+	Header         string   `yaml:"header" mapstructure:"header" json:"header,omitempty" gorm:"column:header" bson:"header,omitempty" dynamodbav:"header,omitempty" firestore:"header,omitempty"`
+}
+
+// Resolver returns the ip of the client. The forwarding header is only honored if the request comes from a trusted proxy.
+// Only one header is read, the one which the trusted proxies set or append to, because a header which the proxies pass through is set by the client.
+type Resolver struct {
+	Trusted []*net.IPNet
+	Header  string
+}
+
+var defaultResolver atomic.Value
+
+func init() {
+	defaultResolver.Store(&Resolver{Header: HeaderXForwardedFor})
+}
+
+// NewResolver creates a resolver. The default header is X-Forwarded-For.
+func NewResolver(c Config) (*Resolver, error) {
+	trusted, err := ParseCIDRs(c.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	header := c.Header
+	if len(header) == 0 {
+		header = HeaderXForwardedFor
+	}
+	return &Resolver{Trusted: trusted, Header: header}, nil
+}
+
+// SetTrustedProxies replaces the default resolver, which is used by the checkers, handlers and middlewares.
+func SetTrustedProxies(c Config) error {
+	resolver, err := NewResolver(c)
+	if err != nil {
+		return err
+	}
+	defaultResolver.Store(resolver)
+	return nil
+}
+
+func GetResolver() *Resolver {
+	return defaultResolver.Load().(*Resolver)
+}
+
+func GetClientIp(r *http.Request) string {
+	return GetResolver().ClientIp(r)
+}
+
+func (s *Resolver) IsTrusted(ip net.IP) bool {
+	return Contains(s.Trusted, ip)
+}
+
+// ClientIp walks the forwarded chain from right to left, skipping the trusted proxies, and returns the first untrusted address.
+func (s *Resolver) ClientIp(r *http.Request) string {
+	remoteIp := GetRemoteIp(r)
+	if len(s.Trusted) == 0 {
+		return remoteIp
+	}
+	ip := net.ParseIP(remoteIp)
+	if ip == nil || !s.IsTrusted(ip) {
+		return remoteIp
+	}
+	chain := getChain(r, s.Header)
+	client := remoteIp
+	for i := len(chain) - 1; i >= 0; i-- {
+		hop := net.ParseIP(chain[i])
+		if hop == nil {
+			return client
+		}
+		client = hop.String()
+		if !s.IsTrusted(hop) {
+			return client
+		}
+	}
+	return client
+}
+
+func GetRemoteIp(r *http.Request) string {
+	remoteIp, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteIp = r.RemoteAddr
+	}
+	return remoteIp
+}
+
+func getChain(r *http.Request, header string) []string {
+	values := r.Header.Values(header)
+	if len(values) == 0 {
+		return nil
+	}
+	if strings.EqualFold(header, HeaderForwarded) {
+		return ParseForwarded(values)
+	}
+	chain := make([]string, 0)
+	for _, v := range values {
+		for _, s := range strings.Split(v, ",") {
+			s = strings.TrimSpace(s)
+			if len(s) > 0 {
+				chain = append(chain, stripPort(s))
+			}
+		}
+	}
+	return chain
+}
+
+// ParseForwarded returns the "for" addresses of the RFC 7239 Forwarded header, in order. Obfuscated or unknown nodes are kept as they are, so that they stop the walk.
+func ParseForwarded(values []string) []string {
+	chain := make([]string, 0)
+	for _, v := range values {
+		for _, element := range strings.Split(v, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+				if len(kv) != 2 || !strings.EqualFold(kv[0], "for") {
+					continue
+				}
+				chain = append(chain, stripPort(strings.Trim(kv[1], "\"")))
+			}
+		}
+	}
+	return chain
+}
+
+func stripPort(s string) string {
+	if strings.HasPrefix(s, "[") {
+		if i := strings.Index(s, "]"); i > 0 {
+			return s[1:i]
+		}
+		return s
+	}
+	if strings.Count(s, ":") == 1 {
+		return s[:strings.Index(s, ":")]
+	}
+	return s
+}
+
+// ParseCIDRs accepts CIDRs and single addresses.
+func ParseCIDRs(list []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(list))
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
+		}
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: s}
+			}
+			if ip4 := ip.To4(); ip4 != nil {
+				s = s + "/32"
+			} else {
+				s = s + "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func Contains(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+
+	"golang.org/x/crypto/argon2"
+)
+
+type Argon2Comparator struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+	SaltLen int
+}
+
+// NewArgon2Comparator creates an Argon2id comparator, the default parameters are t=3, m=64MB, p=4.
+func NewArgon2Comparator(options ...uint32) *Argon2Comparator {
+	c := &Argon2Comparator{Time: 3, Memory: 64 * 1024, Threads: 4, KeyLen: 32, SaltLen: 16}
+	if len(options) > 0 && options[0] > 0 {
+		c.Time = options[0]
+	}
+	if len(options) > 1 && options[1] > 0 {
+		c.Memory = options[1]
+	}
+	if len(options) > 2 && options[2] > 0 {
+		c.Threads = uint8(options[2])
+	}
+	return c
+}
+
+func (c *Argon2Comparator) Hash(plaintext []byte) ([]byte, error) {
+	salt := make([]byte, c.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey(plaintext, salt, c.Time, c.Memory, c.Threads, c.KeyLen)
+	p := PHC{Id: "argon2id", Version: argon2.Version, Params: map[string]int{"m": int(c.Memory), "t": int(c.Time), "p": int(c.Threads)}, Salt: salt, Hash: key}
+	return []byte(p.String()), nil
+}
+func (c *Argon2Comparator) Compare(plaintext []byte, hashed []byte) (bool, error) {
+	p, err := ParsePHC(string(hashed))
+	if err != nil {
+		return false, err
+	}
+	if p.Id != "argon2id" || p.Params["m"] <= 0 || p.Params["t"] <= 0 || p.Params["p"] <= 0 {
+		return false, ErrInvalidHash
+	}
+	key := argon2.IDKey(plaintext, p.Salt, uint32(p.Params["t"]), uint32(p.Params["m"]), uint8(p.Params["p"]), uint32(len(p.Hash)))
+	return subtle.ConstantTimeCompare(key, p.Hash) == 1, nil
+}
+
+// NeedsRehash returns true if the hash is not Argon2id or is weaker than the current parameters.
+func (c *Argon2Comparator) NeedsRehash(hashed []byte) bool {
+	p, err := ParsePHC(string(hashed))
+	if err != nil || p.Id != "argon2id" || p.Version != argon2.Version {
+		return true
+	}
+	return p.Params["m"] < int(c.Memory) || p.Params["t"] < int(c.Time) || p.Params["p"] < int(c.Threads) || len(p.Hash) < int(c.KeyLen)
+}
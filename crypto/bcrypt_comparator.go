@@ -25,3 +25,7 @@ func (b *BCryptComparator) Hash(plaintext []byte) ([]byte, error) {
 	}
 	return bytes, nil
 }
+func (b *BCryptComparator) NeedsRehash(hashed []byte) bool {
+	cost, err := bcrypt.Cost(hashed)
+	return err != nil || cost < b.Cost
+}
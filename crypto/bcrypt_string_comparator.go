@@ -34,3 +34,7 @@ func (b *BCryptStringComparator) Compare(plaintext string, hashed string) (bool,
 func (b *BCryptStringComparator) Hash(plaintext string) (string, error) {
 	return HashWithCost(plaintext, b.Cost)
 }
+func (b *BCryptStringComparator) NeedsRehash(hashed string) bool {
+	cost, err := bcrypt.Cost([]byte(hashed))
+	return err != nil || cost < b.Cost
+}
@@ -0,0 +1,32 @@
+package crypto
+
+type HashComparator interface {
+	Comparator
+	NeedsRehash(hashed []byte) bool
+}
+
+// HashStringComparator adapts a Comparator to StringComparator.
+type HashStringComparator struct {
+	Comparator HashComparator
+}
+
+func NewArgon2StringComparator(options ...uint32) *HashStringComparator {
+	return &HashStringComparator{Comparator: NewArgon2Comparator(options...)}
+}
+func NewScryptStringComparator(options ...int) *HashStringComparator {
+	return &HashStringComparator{Comparator: NewScryptComparator(options...)}
+}
+
+func (c *HashStringComparator) Compare(plaintext string, hashed string) (bool, error) {
+	return c.Comparator.Compare([]byte(plaintext), []byte(hashed))
+}
+func (c *HashStringComparator) Hash(plaintext string) (string, error) {
+	bytes, err := c.Comparator.Hash([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+func (c *HashStringComparator) NeedsRehash(hashed string) bool {
+	return c.Comparator.NeedsRehash([]byte(hashed))
+}
@@ -0,0 +1,101 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidHash = errors.New("invalid hash format")
+
+// PHC is the PHC string format: $<id>[$v=<version>][$<param>=<value>(,<param>=<value>)*][$<salt>[$<hash>]]
+type PHC struct {
+	Id      string
+	Version int
+	Params  map[string]int
+	Salt    []byte
+	Hash    []byte
+}
+
+func (p PHC) String() string {
+	var b strings.Builder
+	b.WriteString("$" + p.Id)
+	if p.Version > 0 {
+		b.WriteString("$v=" + strconv.Itoa(p.Version))
+	}
+	if len(p.Params) > 0 {
+		b.WriteString("$")
+		for i, k := range phcKeys(p.Id, p.Params) {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(k + "=" + strconv.Itoa(p.Params[k]))
+		}
+	}
+	b.WriteString("$" + base64.RawStdEncoding.EncodeToString(p.Salt))
+	b.WriteString("$" + base64.RawStdEncoding.EncodeToString(p.Hash))
+	return b.String()
+}
+
+func ParsePHC(s string) (*PHC, error) {
+	parts := strings.Split(s, "$")
+	if len(parts) < 4 || len(parts[0]) > 0 || len(parts[1]) == 0 {
+		return nil, ErrInvalidHash
+	}
+	p := &PHC{Id: parts[1], Params: make(map[string]int)}
+	i := 2
+	if strings.HasPrefix(parts[i], "v=") {
+		v, err := strconv.Atoi(parts[i][2:])
+		if err != nil {
+			return nil, ErrInvalidHash
+		}
+		p.Version = v
+		i++
+	}
+	if len(parts)-i == 3 {
+		for _, param := range strings.Split(parts[i], ",") {
+			kv := strings.SplitN(param, "=", 2)
+			if len(kv) != 2 {
+				return nil, ErrInvalidHash
+			}
+			v, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return nil, ErrInvalidHash
+			}
+			p.Params[kv[0]] = v
+		}
+		i++
+	}
+	if len(parts)-i != 2 {
+		return nil, ErrInvalidHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[i])
+	if err != nil {
+		return nil, ErrInvalidHash
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[i+1])
+	if err != nil {
+		return nil, ErrInvalidHash
+	}
+	p.Salt = salt
+	p.Hash = hash
+	return p, nil
+}
+
+func phcKeys(id string, params map[string]int) []string {
+	var order []string
+	switch id {
+	case "argon2id", "argon2i":
+		order = []string{"m", "t", "p"}
+	case "scrypt":
+		order = []string{"ln", "r", "p"}
+	}
+	keys := make([]string, 0, len(params))
+	for _, k := range order {
+		if _, ok := params[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
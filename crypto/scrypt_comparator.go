@@ -0,0 +1,68 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+type ScryptComparator struct {
+	LogN    int
+	R       int
+	P       int
+	KeyLen  int
+	SaltLen int
+}
+
+// NewScryptComparator creates a scrypt comparator, the default parameters are N=2^15, r=8, p=1.
+func NewScryptComparator(options ...int) *ScryptComparator {
+	c := &ScryptComparator{LogN: 15, R: 8, P: 1, KeyLen: 32, SaltLen: 16}
+	if len(options) > 0 && options[0] > 0 {
+		c.LogN = options[0]
+	}
+	if len(options) > 1 && options[1] > 0 {
+		c.R = options[1]
+	}
+	if len(options) > 2 && options[2] > 0 {
+		c.P = options[2]
+	}
+	return c
+}
+
+func (c *ScryptComparator) Hash(plaintext []byte) ([]byte, error) {
+	salt := make([]byte, c.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(plaintext, salt, 1<<c.LogN, c.R, c.P, c.KeyLen)
+	if err != nil {
+		return nil, err
+	}
+	p := PHC{Id: "scrypt", Params: map[string]int{"ln": c.LogN, "r": c.R, "p": c.P}, Salt: salt, Hash: key}
+	return []byte(p.String()), nil
+}
+func (c *ScryptComparator) Compare(plaintext []byte, hashed []byte) (bool, error) {
+	p, err := ParsePHC(string(hashed))
+	if err != nil {
+		return false, err
+	}
+	ln := p.Params["ln"]
+	if p.Id != "scrypt" || ln <= 0 || ln > 30 || p.Params["r"] <= 0 || p.Params["p"] <= 0 {
+		return false, ErrInvalidHash
+	}
+	key, err := scrypt.Key(plaintext, p.Salt, 1<<ln, p.Params["r"], p.Params["p"], len(p.Hash))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(key, p.Hash) == 1, nil
+}
+
+// NeedsRehash returns true if the hash is not scrypt or is weaker than the current parameters.
+func (c *ScryptComparator) NeedsRehash(hashed []byte) bool {
+	p, err := ParsePHC(string(hashed))
+	if err != nil || p.Id != "scrypt" {
+		return true
+	}
+	return p.Params["ln"] < c.LogN || p.Params["r"] < c.R || p.Params["p"] < c.P || len(p.Hash) < c.KeyLen
+}
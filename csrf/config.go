@@ -0,0 +1,39 @@
+package csrf
+
+import "time"
+
+type Config struct {
+	Cookie           string        `yaml:"cookie" mapstructure:"cookie" json:"cookie,omitempty" gorm:"column:cookie" bson:"cookie,omitempty" dynamodbav:"cookie,omitempty" firestore:"cookie,omitempty"`
+	Header           string        `yaml:"header" mapstructure:"header" json:"header,omitempty" gorm:"column:header" bson:"header,omitempty" dynamodbav:"header,omitempty" firestore:"header,omitempty"`
+	Field            string        `yaml:"field" mapstructure:"field" json:"field,omitempty" gorm:"column:field" bson:"field,omitempty" dynamodbav:"field,omitempty" firestore:"field,omitempty"`
+	Domain           string        `yaml:"domain" mapstructure:"domain" json:"domain,omitempty" gorm:"column:domain" bson:"domain,omitempty" dynamodbav:"domain,omitempty" firestore:"domain,omitempty"`
+	Path             string        `yaml:"path" mapstructure:"path" json:"path,omitempty" gorm:"column:path" bson:"path,omitempty" dynamodbav:"path,omitempty" firestore:"path,omitempty"`
+	Expires          time.Duration `yaml:"expires" mapstructure:"expires" json:"expires,omitempty" gorm:"column:expires" bson:"expires,omitempty" dynamodbav:"expires,omitempty" firestore:"expires,omitempty"`
+	Insecure         bool          `yaml:"insecure" mapstructure:"insecure" json:"insecure,omitempty" gorm:"column:insecure" bson:"insecure,omitempty" dynamodbav:"insecure,omitempty" firestore:"insecure,omitempty"`
+	Origins          string        `yaml:"origins" mapstructure:"origins" json:"origins,omitempty" gorm:"column:origins" bson:"origins,omitempty" dynamodbav:"origins,omitempty" firestore:"origins,omitempty"`
+	Skips            string        `yaml:"skips" mapstructure:"skips" json:"skips,omitempty" gorm:"column:skips" bson:"skips,omitempty" dynamodbav:"skips,omitempty" firestore:"skips,omitempty"`
+	SessionCookie    string        `yaml:"session_cookie" mapstructure:"session_cookie" json:"sessionCookie,omitempty" gorm:"column:sessioncookie" bson:"sessionCookie,omitempty" dynamodbav:"sessionCookie,omitempty" firestore:"sessionCookie,omitempty"`
+	SkipContentTypes string        `yaml:"skip_content_types" mapstructure:"skip_content_types" json:"skipContentTypes,omitempty" gorm:"column:skipcontenttypes" bson:"skipContentTypes,omitempty" dynamodbav:"skipContentTypes,omitempty" firestore:"skipContentTypes,omitempty"`
+}
+
+func InitConfig(c Config) Config {
+	if len(c.Cookie) == 0 {
+		c.Cookie = "csrf_token"
+	}
+	if len(c.Header) == 0 {
+		c.Header = "X-CSRF-Token"
+	}
+	if len(c.Field) == 0 {
+		c.Field = "_csrf"
+	}
+	if len(c.SessionCookie) == 0 {
+		c.SessionCookie = "id"
+	}
+	if len(c.Path) == 0 {
+		c.Path = "/"
+	}
+	if c.Expires <= 0 {
+		c.Expires = 12 * time.Hour
+	}
+	return c
+}
@@ -0,0 +1,200 @@
+package csrf
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingToken  = errors.New("csrf token is missing")
+	ErrInvalidToken  = errors.New("csrf token is invalid")
+	ErrInvalidOrigin = errors.New("origin is not allowed")
+)
+
+// Protector signs the token by HMAC of the random value and the session id, so a token is valid only for the session it was issued for.
+type Protector struct {
+	Config   Config
+	Secret   []byte
+	Origins  []string
+	Skips    []string
+	Types    []string
+	Generate func() (string, error)
+	// SessionId returns the session id of the request, the default reads the cookie Config.SessionCookie
+	SessionId func(r *http.Request) string
+}
+
+func NewProtector(c Config, secret string, opts ...func() (string, error)) *Protector {
+	c = InitConfig(c)
+	generate := RandomToken
+	if len(opts) > 0 && opts[0] != nil {
+		generate = opts[0]
+	}
+	p := &Protector{Config: c, Secret: []byte(secret), Origins: split(c.Origins), Skips: split(c.Skips), Types: split(c.SkipContentTypes), Generate: generate}
+	p.SessionId = p.GetSessionId
+	return p
+}
+
+func (p *Protector) GetSessionId(r *http.Request) string {
+	if c, err := r.Cookie(p.Config.SessionCookie); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+func (p *Protector) sign(v string, sessionId string) string {
+	h := hmac.New(sha256.New, p.Secret)
+	h.Write([]byte(v))
+	h.Write([]byte{0})
+	h.Write([]byte(sessionId))
+	return v + "." + hex.EncodeToString(h.Sum(nil))
+}
+
+// Check returns true if the token is signed for the session id.
+func (p *Protector) Check(token string, sessionId string) bool {
+	i := strings.LastIndex(token, ".")
+	if i <= 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(p.sign(token[:i], sessionId))) == 1
+}
+
+func RandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func IsSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions || method == http.MethodTrace
+}
+
+// Token returns the signed token from the cookie, or issues a new one if it is missing, invalid or signed for another session.
+func (p *Protector) Token(w http.ResponseWriter, r *http.Request) (string, error) {
+	sessionId := p.SessionId(r)
+	if c, err := r.Cookie(p.Config.Cookie); err == nil && len(c.Value) > 0 && p.Check(c.Value, sessionId) {
+		return c.Value, nil
+	}
+	v, err := p.Generate()
+	if err != nil {
+		return "", err
+	}
+	token := p.sign(v, sessionId)
+	p.SetCookie(w, token)
+	return token, nil
+}
+
+func (p *Protector) SetCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     p.Config.Cookie,
+		Domain:   p.Config.Domain,
+		Value:    token,
+		Path:     p.Config.Path,
+		Expires:  time.Now().Add(p.Config.Expires),
+		SameSite: http.SameSiteStrictMode,
+		Secure:   !p.Config.Insecure,
+	})
+}
+
+func (p *Protector) Skip(r *http.Request) bool {
+	if IsSafeMethod(r.Method) {
+		return true
+	}
+	for _, s := range p.Skips {
+		if s == r.URL.Path || (strings.HasSuffix(s, "*") && strings.HasPrefix(r.URL.Path, s[:len(s)-1])) {
+			return true
+		}
+	}
+	if len(p.Types) > 0 {
+		contentType := r.Header.Get("Content-Type")
+		if i := strings.Index(contentType, ";"); i >= 0 {
+			contentType = contentType[:i]
+		}
+		contentType = strings.TrimSpace(contentType)
+		for _, t := range p.Types {
+			if strings.EqualFold(t, contentType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Verify checks the Origin/Referer, compares the submitted token with the signed token in the cookie, and checks that the token is signed for the session of the request.
+func (p *Protector) Verify(r *http.Request) error {
+	if err := p.CheckOrigin(r); err != nil {
+		return err
+	}
+	c, err := r.Cookie(p.Config.Cookie)
+	if err != nil || len(c.Value) == 0 {
+		return ErrMissingToken
+	}
+	submitted := r.Header.Get(p.Config.Header)
+	if len(submitted) == 0 && len(p.Config.Field) > 0 {
+		submitted = r.PostFormValue(p.Config.Field)
+	}
+	if len(submitted) == 0 {
+		return ErrMissingToken
+	}
+	if subtle.ConstantTimeCompare([]byte(submitted), []byte(c.Value)) != 1 {
+		return ErrInvalidToken
+	}
+	if !p.Check(c.Value, p.SessionId(r)) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func (p *Protector) CheckOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 || origin == "null" {
+		referer := r.Header.Get("Referer")
+		if len(referer) == 0 {
+			if origin == "null" {
+				return ErrInvalidOrigin
+			}
+			return nil
+		}
+		u, err := url.Parse(referer)
+		if err != nil {
+			return ErrInvalidOrigin
+		}
+		origin = u.Scheme + "://" + u.Host
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return ErrInvalidOrigin
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return nil
+	}
+	for _, o := range p.Origins {
+		if strings.EqualFold(o, origin) {
+			return nil
+		}
+	}
+	return ErrInvalidOrigin
+}
+
+func split(s string) []string {
+	vs := make([]string, 0)
+	if len(s) == 0 {
+		return vs
+	}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if len(v) > 0 {
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
@@ -0,0 +1,39 @@
+package csrf
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/core-go/core/csrf"
+)
+
+type Handler struct {
+	*csrf.Protector
+}
+
+func NewHandler(c csrf.Config, secret string, opts ...func() (string, error)) *Handler {
+	return &Handler{csrf.NewProtector(c, secret, opts...)}
+}
+
+func (h *Handler) Protect(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r := c.Request()
+		if h.Skip(r) {
+			return next(c)
+		}
+		if err := h.Verify(r); err != nil {
+			return c.String(http.StatusForbidden, err.Error())
+		}
+		return next(c)
+	}
+}
+
+func (h *Handler) GetToken(c echo.Context) error {
+	token, err := h.Token(c.Response(), c.Request())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, map[string]string{"token": token})
+}
@@ -0,0 +1,41 @@
+package csrf
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/core-go/core/csrf"
+)
+
+type Handler struct {
+	*csrf.Protector
+}
+
+func NewHandler(c csrf.Config, secret string, opts ...func() (string, error)) *Handler {
+	return &Handler{csrf.NewProtector(c, secret, opts...)}
+}
+
+func (h *Handler) Protect() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.Skip(c.Request) {
+			c.Next()
+			return
+		}
+		if err := h.Verify(c.Request); err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, err.Error())
+			return
+		}
+		c.Next()
+	}
+}
+
+func (h *Handler) GetToken(c *gin.Context) {
+	token, err := h.Token(c.Writer, c.Request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, map[string]string{"token": token})
+}
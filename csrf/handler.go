@@ -0,0 +1,40 @@
+package csrf
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type Handler struct {
+	*Protector
+}
+
+func NewHandler(c Config, secret string, opts ...func() (string, error)) *Handler {
+	return &Handler{NewProtector(c, secret, opts...)}
+}
+
+func (h *Handler) Protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.Skip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err := h.Verify(r); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *Handler) GetToken(w http.ResponseWriter, r *http.Request) {
+	token, err := h.Token(w, r)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"token": token})
+}
@@ -0,0 +1,131 @@
+package dek
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/core-go/core/aead"
+)
+
+const envelopeVersion = "v1"
+
+// Envelope is the per record data key wrapped by a master key, and the data encrypted by this data key.
+// The text format is v1.<key id>.<wrapped data key>.<encrypted data>, encoded by base64 url.
+type Envelope struct {
+	KeyId string
+	Key   []byte
+	Data  []byte
+}
+
+func (e Envelope) String() string {
+	return envelopeVersion + "." + e.KeyId + "." + base64.RawURLEncoding.EncodeToString(e.Key) + "." + base64.RawURLEncoding.EncodeToString(e.Data)
+}
+func ParseEnvelope(s string) (*Envelope, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 4 || parts[0] != envelopeVersion || len(parts[1]) == 0 {
+		return nil, aead.ErrInvalidCiphertext
+	}
+	key, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, aead.ErrInvalidCiphertext
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, aead.ErrInvalidCiphertext
+	}
+	return &Envelope{KeyId: parts[1], Key: key, Data: data}, nil
+}
+
+type EnvelopeEncrypter struct {
+	KeyRing   *KeyRing
+	Algorithm byte
+}
+
+func NewEnvelopeEncrypter(keyRing *KeyRing, options ...byte) *EnvelopeEncrypter {
+	algorithm := aead.AES256GCM
+	if len(options) > 0 && options[0] > 0 {
+		algorithm = options[0]
+	}
+	return &EnvelopeEncrypter{KeyRing: keyRing, Algorithm: algorithm}
+}
+
+// Seal generates a new data key, encrypts the plaintext by it, and wraps the data key by the current master key.
+func (e *EnvelopeEncrypter) Seal(plaintext []byte, aad []byte) (*Envelope, error) {
+	dataKey, err := aead.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	c, err := aead.NewCipher(e.Algorithm, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.Encrypt(plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+	id, wrapped, err := e.KeyRing.Wrap(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{KeyId: id, Key: wrapped, Data: data}, nil
+}
+func (e *EnvelopeEncrypter) Open(envelope *Envelope, aad []byte) ([]byte, error) {
+	dataKey, err := e.KeyRing.Unwrap(envelope.KeyId, envelope.Key)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Data) < 2 {
+		return nil, aead.ErrInvalidCiphertext
+	}
+	c, err := aead.NewCipher(envelope.Data[1], dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return c.Decrypt(envelope.Data, aad)
+}
+
+// Rewrap unwraps the data key by its master key and wraps it again by the current master key. The data is not re-encrypted.
+func (e *EnvelopeEncrypter) Rewrap(envelope *Envelope) (*Envelope, bool, error) {
+	if envelope.KeyId == e.KeyRing.CurrentId() {
+		return envelope, false, nil
+	}
+	dataKey, err := e.KeyRing.Unwrap(envelope.KeyId, envelope.Key)
+	if err != nil {
+		return nil, false, err
+	}
+	id, wrapped, err := e.KeyRing.Wrap(dataKey)
+	if err != nil {
+		return nil, false, err
+	}
+	return &Envelope{KeyId: id, Key: wrapped, Data: envelope.Data}, true, nil
+}
+
+func (e *EnvelopeEncrypter) Encrypt(plaintext string) (string, error) {
+	envelope, err := e.Seal([]byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+	return envelope.String(), nil
+}
+func (e *EnvelopeEncrypter) Decrypt(ciphertext string) (string, error) {
+	envelope, err := ParseEnvelope(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := e.Open(envelope, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+func (e *EnvelopeEncrypter) RewrapString(ciphertext string) (string, bool, error) {
+	envelope, err := ParseEnvelope(ciphertext)
+	if err != nil {
+		return "", false, err
+	}
+	newEnvelope, changed, err := e.Rewrap(envelope)
+	if err != nil {
+		return "", false, err
+	}
+	return newEnvelope.String(), changed, nil
+}
@@ -0,0 +1,110 @@
+package dek
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/core-go/core/aead"
+)
+
+var ErrKeyNotFound = errors.New("master key not found")
+
+type MasterKey struct {
+	Id        string `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"-"`
+	Key       string `yaml:"key" mapstructure:"key" json:"key,omitempty" gorm:"column:key" bson:"key,omitempty" dynamodbav:"key,omitempty" firestore:"key,omitempty"`
+	Algorithm string `yaml:"algorithm" mapstructure:"algorithm" json:"algorithm,omitempty" gorm:"column:algorithm" bson:"algorithm,omitempty" dynamodbav:"algorithm,omitempty" firestore:"algorithm,omitempty"`
+}
+type KeyRingConfig struct {
+	Current string      `yaml:"current" mapstructure:"current" json:"current,omitempty" gorm:"column:current" bson:"current,omitempty" dynamodbav:"current,omitempty" firestore:"current,omitempty"`
+	Keys    []MasterKey `yaml:"keys" mapstructure:"keys" json:"keys,omitempty" gorm:"column:keys" bson:"keys,omitempty" dynamodbav:"keys,omitempty" firestore:"keys,omitempty"`
+}
+
+// KeyRing keeps the master keys by key id. New data keys are wrapped by the current key, old keys are kept to unwrap the existing data keys.
+type KeyRing struct {
+	mu      sync.RWMutex
+	Current string
+	Keys    map[string]*aead.Cipher
+}
+
+func NewKeyRing(c KeyRingConfig) (*KeyRing, error) {
+	r := &KeyRing{Keys: make(map[string]*aead.Cipher)}
+	for _, k := range c.Keys {
+		key, err := base64.StdEncoding.DecodeString(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key '%s': %w", k.Id, err)
+		}
+		if err = r.Add(k.Id, key, GetAlgorithm(k.Algorithm)); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.SetCurrent(c.Current); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+func GetAlgorithm(algorithm string) byte {
+	if strings.EqualFold(strings.ReplaceAll(algorithm, "-", ""), "chacha20poly1305") {
+		return aead.ChaCha20Poly1305
+	}
+	return aead.AES256GCM
+}
+
+func (r *KeyRing) Add(id string, key []byte, algorithm byte) error {
+	if len(id) == 0 || strings.Contains(id, ".") {
+		return fmt.Errorf("invalid master key id '%s'", id)
+	}
+	c, err := aead.NewCipher(algorithm, key)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Keys[id] = c
+	return nil
+}
+func (r *KeyRing) SetCurrent(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.Keys[id]; !ok {
+		return ErrKeyNotFound
+	}
+	r.Current = id
+	return nil
+}
+func (r *KeyRing) CurrentId() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.Current
+}
+func (r *KeyRing) Remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id != r.Current {
+		delete(r.Keys, id)
+	}
+}
+
+// Wrap encrypts the data key by the current master key, the key id is authenticated as the additional data.
+func (r *KeyRing) Wrap(dataKey []byte) (string, []byte, error) {
+	r.mu.RLock()
+	id := r.Current
+	c, ok := r.Keys[id]
+	r.mu.RUnlock()
+	if !ok {
+		return "", nil, ErrKeyNotFound
+	}
+	wrapped, err := c.Encrypt(dataKey, []byte(id))
+	return id, wrapped, err
+}
+func (r *KeyRing) Unwrap(id string, wrapped []byte) ([]byte, error) {
+	r.mu.RLock()
+	c, ok := r.Keys[id]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return c.Decrypt(wrapped, []byte(id))
+}
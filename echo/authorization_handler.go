@@ -2,8 +2,8 @@ package echo
 
 import (
 	"context"
+	"github.com/core-go/core/clientip"
 	"github.com/labstack/echo/v4"
-	"net"
 	"net/http"
 )
 
@@ -79,9 +79,5 @@ func (c *AuthorizationHandler) HandleAuthorization() echo.MiddlewareFunc {
 	}
 }
 func GetRemoteIp(r *http.Request) string {
-	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		remoteIP = r.RemoteAddr
-	}
-	return remoteIP
+	return clientip.GetClientIp(r)
 }
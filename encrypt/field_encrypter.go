@@ -0,0 +1,256 @@
+package encrypt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type Cipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// Field is parsed from the tag `encrypt:"<key>[,deterministic][,blind=<field name>]"`.
+type Field struct {
+	Index         int
+	Key           string
+	Deterministic bool
+	Blind         int
+}
+
+// FieldEncrypter encrypts the fields tagged with "encrypt" on write, and decrypts them on read.
+// Randomized fields cannot be searched; use deterministic encryption, or a blind index stored in another field, for equality search.
+type FieldEncrypter struct {
+	Ciphers       map[string]Cipher
+	Deterministic map[string]Cipher
+	IndexKey      []byte
+	fields        sync.Map
+}
+
+func NewFieldEncrypter(ciphers map[string]Cipher, deterministic map[string]Cipher, indexKey []byte) *FieldEncrypter {
+	return &FieldEncrypter{Ciphers: ciphers, Deterministic: deterministic, IndexKey: indexKey}
+}
+
+func GetFields(modelType reflect.Type) []Field {
+	fields := make([]Field, 0)
+	for i := 0; i < modelType.NumField(); i++ {
+		tag, ok := modelType.Field(i).Tag.Lookup("encrypt")
+		if !ok || len(tag) == 0 || tag == "-" {
+			continue
+		}
+		options := strings.Split(tag, ",")
+		f := Field{Index: i, Key: options[0], Blind: -1}
+		for _, option := range options[1:] {
+			option = strings.TrimSpace(option)
+			if option == "deterministic" {
+				f.Deterministic = true
+			} else if strings.HasPrefix(option, "blind=") {
+				if blind, ok := modelType.FieldByName(option[6:]); ok && len(blind.Index) == 1 {
+					f.Blind = blind.Index[0]
+				}
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+func (e *FieldEncrypter) getFields(modelType reflect.Type) []Field {
+	if v, ok := e.fields.Load(modelType); ok {
+		return v.([]Field)
+	}
+	fields := GetFields(modelType)
+	e.fields.Store(modelType, fields)
+	return fields
+}
+
+func (e *FieldEncrypter) cipher(f Field) (Cipher, error) {
+	var c Cipher
+	var ok bool
+	if f.Deterministic {
+		c, ok = e.Deterministic[f.Key]
+	} else {
+		c, ok = e.Ciphers[f.Key]
+	}
+	if !ok || c == nil {
+		return nil, fmt.Errorf("no cipher for encryption key '%s'", f.Key)
+	}
+	return c, nil
+}
+
+// BlindIndex returns the HMAC-SHA256 of the normalized value, to be stored and searched instead of the plaintext.
+func (e *FieldEncrypter) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, e.IndexKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Encrypt encrypts the tagged fields of a struct, and sets the blind indexes.
+func (e *FieldEncrypter) Encrypt(obj interface{}) error {
+	return e.each(obj, func(v reflect.Value) error {
+		for _, f := range e.getFields(v.Type()) {
+			s, ok := getString(v.Field(f.Index))
+			if !ok || len(s) == 0 {
+				continue
+			}
+			if f.Blind >= 0 {
+				setString(v.Field(f.Blind), e.BlindIndex(s))
+			}
+			c, err := e.cipher(f)
+			if err != nil {
+				return err
+			}
+			encrypted, err := c.Encrypt(s)
+			if err != nil {
+				return err
+			}
+			setString(v.Field(f.Index), encrypted)
+		}
+		return nil
+	})
+}
+
+// Decrypt decrypts the tagged fields of a struct, or of each struct of a slice, such as the search results.
+func (e *FieldEncrypter) Decrypt(obj interface{}) error {
+	return e.each(obj, func(v reflect.Value) error {
+		for _, f := range e.getFields(v.Type()) {
+			s, ok := getString(v.Field(f.Index))
+			if !ok || len(s) == 0 {
+				continue
+			}
+			c, err := e.cipher(f)
+			if err != nil {
+				return err
+			}
+			decrypted, err := c.Decrypt(s)
+			if err != nil {
+				return err
+			}
+			setString(v.Field(f.Index), decrypted)
+		}
+		return nil
+	})
+}
+
+// EncryptFilter converts the search values of the filter: deterministic fields are encrypted, blind indexed fields are moved to their index fields.
+func (e *FieldEncrypter) EncryptFilter(filter interface{}) error {
+	return e.each(filter, func(v reflect.Value) error {
+		for _, f := range e.getFields(v.Type()) {
+			field := v.Field(f.Index)
+			s, ok := getString(field)
+			if !ok || len(s) == 0 {
+				continue
+			}
+			if f.Blind >= 0 {
+				setString(v.Field(f.Blind), e.BlindIndex(s))
+				field.Set(reflect.Zero(field.Type()))
+			} else if f.Deterministic {
+				c, err := e.cipher(f)
+				if err != nil {
+					return err
+				}
+				encrypted, err := c.Encrypt(s)
+				if err != nil {
+					return err
+				}
+				setString(field, encrypted)
+			}
+		}
+		return nil
+	})
+}
+
+// EncryptMap returns a copy of the patch map, with the values of the encrypted fields encrypted, and the blind indexes set. The keys are the json names of the fields.
+func (e *FieldEncrypter) EncryptMap(modelType reflect.Type, m map[string]interface{}) (map[string]interface{}, error) {
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	for _, f := range e.getFields(modelType) {
+		name := getJsonName(modelType.Field(f.Index))
+		s, ok := res[name].(string)
+		if !ok || len(s) == 0 {
+			continue
+		}
+		if f.Blind >= 0 {
+			res[getJsonName(modelType.Field(f.Blind))] = e.BlindIndex(s)
+		}
+		c, err := e.cipher(f)
+		if err != nil {
+			return nil, err
+		}
+		encrypted, err := c.Encrypt(s)
+		if err != nil {
+			return nil, err
+		}
+		res[name] = encrypted
+	}
+	return res, nil
+}
+
+func (e *FieldEncrypter) each(obj interface{}, f func(reflect.Value) error) error {
+	if obj == nil {
+		return nil
+	}
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if !v.CanSet() {
+			return nil
+		}
+		return f(v)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i)
+			if item.Kind() != reflect.Ptr && item.CanAddr() {
+				item = item.Addr()
+			}
+			if err := e.each(item.Interface(), f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+func getString(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String {
+		return "", false
+	}
+	return v.String(), true
+}
+func setString(v reflect.Value, s string) {
+	if v.Kind() == reflect.Ptr {
+		if v.Type().Elem().Kind() == reflect.String {
+			v.Set(reflect.ValueOf(&s))
+		}
+	} else if v.Kind() == reflect.String {
+		v.SetString(s)
+	}
+}
+func getJsonName(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		if name := strings.Split(tag, ",")[0]; len(name) > 0 && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
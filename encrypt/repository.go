@@ -0,0 +1,114 @@
+package encrypt
+
+import (
+	"context"
+	"reflect"
+)
+
+type Repository[T any, K any] interface {
+	Load(ctx context.Context, id K) (*T, error)
+	Create(ctx context.Context, model *T) (int64, error)
+	Update(ctx context.Context, model *T) (int64, error)
+	Patch(ctx context.Context, model map[string]interface{}) (int64, error)
+	Delete(ctx context.Context, id K) (int64, error)
+}
+
+type SearchRepository[T any, K any, F any] interface {
+	Repository[T, K]
+	Search(ctx context.Context, filter F, limit int64, offset int64) ([]T, int64, error)
+}
+
+// EncryptedRepository encrypts a copy of the model before writing, so the validated model and the response keep the plaintext, and decrypts the loaded models.
+type EncryptedRepository[T any, K any] struct {
+	Repository Repository[T, K]
+	Encrypter  *FieldEncrypter
+	modelType  reflect.Type
+}
+
+func NewEncryptedRepository[T any, K any](repository Repository[T, K], encrypter *FieldEncrypter) *EncryptedRepository[T, K] {
+	var t T
+	return &EncryptedRepository[T, K]{Repository: repository, Encrypter: encrypter, modelType: reflect.TypeOf(t)}
+}
+func (r *EncryptedRepository[T, K]) Load(ctx context.Context, id K) (*T, error) {
+	model, err := r.Repository.Load(ctx, id)
+	if err != nil || model == nil {
+		return model, err
+	}
+	if err = r.Encrypter.Decrypt(model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+func (r *EncryptedRepository[T, K]) Create(ctx context.Context, model *T) (int64, error) {
+	return r.save(ctx, model, r.Repository.Create)
+}
+func (r *EncryptedRepository[T, K]) Update(ctx context.Context, model *T) (int64, error) {
+	return r.save(ctx, model, r.Repository.Update)
+}
+func (r *EncryptedRepository[T, K]) Patch(ctx context.Context, model map[string]interface{}) (int64, error) {
+	encrypted, err := r.Encrypter.EncryptMap(r.modelType, model)
+	if err != nil {
+		return -1, err
+	}
+	return r.Repository.Patch(ctx, encrypted)
+}
+func (r *EncryptedRepository[T, K]) Delete(ctx context.Context, id K) (int64, error) {
+	return r.Repository.Delete(ctx, id)
+}
+
+// save writes an encrypted copy of the model, then copies back the values set by the repository, except the encrypted fields.
+func (r *EncryptedRepository[T, K]) save(ctx context.Context, model *T, write func(context.Context, *T) (int64, error)) (int64, error) {
+	if model == nil {
+		return write(ctx, model)
+	}
+	encrypted := *model
+	if err := r.Encrypter.Encrypt(&encrypted); err != nil {
+		return -1, err
+	}
+	res, err := write(ctx, &encrypted)
+	if err != nil {
+		return res, err
+	}
+	v := reflect.ValueOf(&encrypted).Elem()
+	if v.Kind() == reflect.Struct {
+		plain := reflect.ValueOf(model).Elem()
+		for _, f := range r.Encrypter.getFields(v.Type()) {
+			v.Field(f.Index).Set(plain.Field(f.Index))
+		}
+	}
+	*model = encrypted
+	return res, err
+}
+
+// EncryptedSearchRepository encrypts the search values of the filter, and decrypts the search results.
+// Do not set the Encrypter of the search handlers, when the search service uses this repository.
+type EncryptedSearchRepository[T any, K any, F any] struct {
+	*EncryptedRepository[T, K]
+	SearchRepository SearchRepository[T, K, F]
+}
+
+func NewEncryptedSearchRepository[T any, K any, F any](repository SearchRepository[T, K, F], encrypter *FieldEncrypter) *EncryptedSearchRepository[T, K, F] {
+	return &EncryptedSearchRepository[T, K, F]{EncryptedRepository: NewEncryptedRepository[T, K](repository, encrypter), SearchRepository: repository}
+}
+func (r *EncryptedSearchRepository[T, K, F]) Search(ctx context.Context, filter F, limit int64, offset int64) ([]T, int64, error) {
+	// the filter is copied, so the filter of the caller keeps the plaintext
+	v := reflect.ValueOf(filter)
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		c := reflect.New(v.Elem().Type())
+		c.Elem().Set(v.Elem())
+		if err := r.Encrypter.EncryptFilter(c.Interface()); err != nil {
+			return nil, -1, err
+		}
+		filter = c.Interface().(F)
+	} else if err := r.Encrypter.EncryptFilter(&filter); err != nil {
+		return nil, -1, err
+	}
+	models, total, err := r.SearchRepository.Search(ctx, filter, limit, offset)
+	if err != nil {
+		return models, total, err
+	}
+	if err = r.Encrypter.Decrypt(models); err != nil {
+		return nil, -1, err
+	}
+	return models, total, nil
+}
@@ -75,6 +75,7 @@ type Exporter[T any] struct {
 		driver.Valuer
 		sql.Scanner
 	}
+	Decrypt func(interface{}) error
 }
 
 func (s *Exporter[T]) Export(ctx context.Context) (int64, error) {
@@ -98,6 +99,11 @@ func (s *Exporter[T]) ScanAndWrite(ctx context.Context, rows *sql.Rows) (int64,
 			return i, err
 		}
 		SwapValuesToBool(&obj, &swapValues)
+		if s.Decrypt != nil {
+			if err := s.Decrypt(&obj); err != nil {
+				return i, err
+			}
+		}
 		err1 := s.TransformAndWrite(ctx, s.Write, &obj)
 		if err1 != nil {
 			return i, err1
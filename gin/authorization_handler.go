@@ -2,8 +2,8 @@ package gin
 
 import (
 	"context"
+	"github.com/core-go/core/clientip"
 	"github.com/gin-gonic/gin"
-	"net"
 	"net/http"
 )
 
@@ -76,9 +76,5 @@ func (c *AuthorizationHandler) HandleAuthorization() gin.HandlerFunc {
 	}
 }
 func GetRemoteIp(r *http.Request) string {
-	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		remoteIP = r.RemoteAddr
-	}
-	return remoteIP
+	return clientip.GetClientIp(r)
 }
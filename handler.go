@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+
+	"github.com/core-go/core/search"
 )
 
 const (
@@ -492,6 +494,8 @@ type Handler[T any, K any] struct {
 	WriteLog  func(context.Context, string, string, bool, string) error
 	IdMap     bool
 	Builder   Builder[T]
+
+	RowLevelSecurity *search.RowLevelSecurity
 }
 
 func Newhandler[T any, K any](
@@ -542,6 +546,9 @@ func (h *Handler[T, K]) Load(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	model, er2 := h.Service.Load(r.Context(), id)
+	if er2 == nil && model != nil && h.RowLevelSecurity != nil && !h.RowLevelSecurity.Check(r.Context(), model) {
+		model = nil
+	}
 	action := ""
 	if h.Action.Load != nil {
 		action = *h.Action.Load
@@ -555,6 +562,12 @@ func (h *Handler[T, K]) Create(w http.ResponseWriter, r *http.Request) {
 	}
 	model, er1 := Decode[T](w, r, createFn)
 	if er1 == nil {
+		if h.RowLevelSecurity != nil {
+			if er0 := h.RowLevelSecurity.Apply(r.Context(), &model); er0 != nil {
+				http.Error(w, er0.Error(), http.StatusForbidden)
+				return
+			}
+		}
 		if h.Validate != nil {
 			errors, er2 := h.Validate(r.Context(), &model)
 			if !HasError(w, r, errors, er2, h.LogError, model, h.WriteLog, h.Resource, h.Action.Create) {
@@ -574,6 +587,20 @@ func (h *Handler[T, K]) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	model, er1 := DecodeAndCheckId[T](w, r, h.Keys, h.Indexes, updateFn)
 	if er1 == nil {
+		if h.RowLevelSecurity != nil {
+			id, _, er0 := BuildId[K](r, h.ModelType, h.Keys, h.Indexes, h.IdMap)
+			if er0 != nil {
+				http.Error(w, er0.Error(), http.StatusBadRequest)
+				return
+			}
+			if !h.checkRowLevelSecurity(w, r, id, h.Action.Update) {
+				return
+			}
+			if er0 = h.RowLevelSecurity.Apply(r.Context(), &model); er0 != nil {
+				http.Error(w, er0.Error(), http.StatusForbidden)
+				return
+			}
+		}
 		if h.Validate != nil {
 			errors, er2 := h.Validate(r.Context(), &model)
 			if !HasError(w, r, errors, er2, h.LogError, model, h.WriteLog, h.Resource, h.Action.Update) {
@@ -593,6 +620,20 @@ func (h *Handler[T, K]) Patch(w http.ResponseWriter, r *http.Request) {
 	}
 	r, model, jsonObj, er1 := BuildMapAndCheckId[T](w, r, h.Keys, h.Indexes, updateFn)
 	if er1 == nil {
+		if h.RowLevelSecurity != nil {
+			id, _, er0 := BuildId[K](r, h.ModelType, h.Keys, h.Indexes, h.IdMap)
+			if er0 != nil {
+				http.Error(w, er0.Error(), http.StatusBadRequest)
+				return
+			}
+			if !h.checkRowLevelSecurity(w, r, id, h.Action.Patch) {
+				return
+			}
+			if er0 = h.RowLevelSecurity.ApplyMap(r.Context(), &model, jsonObj); er0 != nil {
+				http.Error(w, er0.Error(), http.StatusForbidden)
+				return
+			}
+		}
 		if h.Validate != nil {
 			errors, er2 := h.Validate(r.Context(), &model)
 			if !HasError(w, r, errors, er2, h.LogError, jsonObj, h.WriteLog, h.Resource, h.Action.Patch) {
@@ -615,6 +656,30 @@ func (h *Handler[T, K]) Delete(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Id type is not valid (Id type must be K)", http.StatusBadRequest)
 		return
 	}
+	if h.RowLevelSecurity != nil && !h.checkRowLevelSecurity(w, r, id, h.Action.Delete) {
+		return
+	}
 	res, err := h.Service.Delete(r.Context(), id)
 	AfterDeletedWithLog(w, r, res, err, h.LogError, h.WriteLog, h.Resource, h.Action.Delete)
 }
+func (h *Handler[T, K]) checkRowLevelSecurity(w http.ResponseWriter, r *http.Request, id K, action string) bool {
+	model, err := h.Service.Load(r.Context(), id)
+	if err != nil {
+		if h.LogError != nil {
+			h.LogError(r.Context(), err.Error())
+		}
+		if h.WriteLog != nil {
+			h.WriteLog(r.Context(), h.Resource, action, false, err.Error())
+		}
+		JSON(w, http.StatusInternalServerError, InternalServerError)
+		return false
+	}
+	if model == nil || !h.RowLevelSecurity.Check(r.Context(), model) {
+		if h.WriteLog != nil {
+			h.WriteLog(r.Context(), h.Resource, action, false, "Data Not Found "+r.URL.Path)
+		}
+		JSON(w, http.StatusNotFound, 0)
+		return false
+	}
+	return true
+}
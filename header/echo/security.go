@@ -0,0 +1,42 @@
+package header
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	h "github.com/core-go/core/header"
+)
+
+type SecurityConfig = h.SecurityConfig
+
+type SecurityHandler struct {
+	*h.SecurityHandler
+}
+
+func NewSecurityHandler(c SecurityConfig, opts ...func(context.Context, string, ...map[string]interface{})) *SecurityHandler {
+	return &SecurityHandler{SecurityHandler: h.NewSecurityHandler(c, opts...)}
+}
+
+func (s *SecurityHandler) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r := c.Request()
+		nonce := s.SetSecurityHeaders(c.Response().Header(), r)
+		c.Set(s.Config.Nonce, nonce)
+		c.SetRequest(r.WithContext(context.WithValue(r.Context(), s.Config.Nonce, nonce)))
+		return next(c)
+	}
+}
+func (s *SecurityHandler) Report(c echo.Context) error {
+	reports, err := h.ReadReports(c.Request())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	if s.Log != nil {
+		for _, report := range reports {
+			s.Log(c.Request().Context(), "csp violation", report)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,42 @@
+package header
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	h "github.com/core-go/core/header"
+)
+
+type SecurityConfig = h.SecurityConfig
+
+type SecurityHandler struct {
+	*h.SecurityHandler
+}
+
+func NewSecurityHandler(c SecurityConfig, opts ...func(context.Context, string, ...map[string]interface{})) *SecurityHandler {
+	return &SecurityHandler{SecurityHandler: h.NewSecurityHandler(c, opts...)}
+}
+
+func (s *SecurityHandler) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		nonce := s.SetSecurityHeaders(c.Writer.Header(), c.Request)
+		c.Set(s.Config.Nonce, nonce)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), s.Config.Nonce, nonce))
+		c.Next()
+	}
+}
+func (s *SecurityHandler) Report(c *gin.Context) {
+	reports, err := h.ReadReports(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	if s.Log != nil {
+		for _, report := range reports {
+			s.Log(c.Request.Context(), "csp violation", report)
+		}
+	}
+	c.Status(http.StatusNoContent)
+}
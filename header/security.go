@@ -0,0 +1,209 @@
+package header
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultCsp = "default-src 'self'; script-src 'self' 'nonce-{nonce}'; style-src 'self' 'nonce-{nonce}'; object-src 'none'; base-uri 'self'; frame-ancestors 'none'"
+	Nonce      = "nonce"
+)
+
+type SecurityConfig struct {
+	HstsMaxAge                int64  `yaml:"hsts_max_age" mapstructure:"hsts_max_age" json:"hstsMaxAge,omitempty" gorm:"column:hstsmaxage" bson:"hstsMaxAge,omitempty" dynamodbav:"hstsMaxAge,omitempty" firestore:"hstsMaxAge"`
+	HstsIncludeSubdomains     bool   `yaml:"hsts_include_subdomains" mapstructure:"hsts_include_subdomains" json:"hstsIncludeSubdomains,omitempty" gorm:"column:hstsincludesubdomains" bson:"hstsIncludeSubdomains,omitempty" dynamodbav:"hstsIncludeSubdomains,omitempty" firestore:"hstsIncludeSubdomains"`
+	HstsPreload               bool   `yaml:"hsts_preload" mapstructure:"hsts_preload" json:"hstsPreload,omitempty" gorm:"column:hstspreload" bson:"hstsPreload,omitempty" dynamodbav:"hstsPreload,omitempty" firestore:"hstsPreload"`
+	ContentTypeOptions        string `yaml:"content_type_options" mapstructure:"content_type_options" json:"contentTypeOptions,omitempty" gorm:"column:contenttypeoptions" bson:"contentTypeOptions,omitempty" dynamodbav:"contentTypeOptions,omitempty" firestore:"contentTypeOptions"`
+	FrameOptions              string `yaml:"frame_options" mapstructure:"frame_options" json:"frameOptions,omitempty" gorm:"column:frameoptions" bson:"frameOptions,omitempty" dynamodbav:"frameOptions,omitempty" firestore:"frameOptions"`
+	ReferrerPolicy            string `yaml:"referrer_policy" mapstructure:"referrer_policy" json:"referrerPolicy,omitempty" gorm:"column:referrerpolicy" bson:"referrerPolicy,omitempty" dynamodbav:"referrerPolicy,omitempty" firestore:"referrerPolicy"`
+	PermissionsPolicy         string `yaml:"permissions_policy" mapstructure:"permissions_policy" json:"permissionsPolicy,omitempty" gorm:"column:permissionspolicy" bson:"permissionsPolicy,omitempty" dynamodbav:"permissionsPolicy,omitempty" firestore:"permissionsPolicy"`
+	CrossOriginOpenerPolicy   string `yaml:"cross_origin_opener_policy" mapstructure:"cross_origin_opener_policy" json:"crossOriginOpenerPolicy,omitempty" gorm:"column:crossoriginopenerpolicy" bson:"crossOriginOpenerPolicy,omitempty" dynamodbav:"crossOriginOpenerPolicy,omitempty" firestore:"crossOriginOpenerPolicy"`
+	CrossOriginEmbedderPolicy string `yaml:"cross_origin_embedder_policy" mapstructure:"cross_origin_embedder_policy" json:"crossOriginEmbedderPolicy,omitempty" gorm:"column:crossoriginembedderpolicy" bson:"crossOriginEmbedderPolicy,omitempty" dynamodbav:"crossOriginEmbedderPolicy,omitempty" firestore:"crossOriginEmbedderPolicy"`
+	CrossOriginResourcePolicy string `yaml:"cross_origin_resource_policy" mapstructure:"cross_origin_resource_policy" json:"crossOriginResourcePolicy,omitempty" gorm:"column:crossoriginresourcepolicy" bson:"crossOriginResourcePolicy,omitempty" dynamodbav:"crossOriginResourcePolicy,omitempty" firestore:"crossOriginResourcePolicy"`
+	Csp                       string `yaml:"csp" mapstructure:"csp" json:"csp,omitempty" gorm:"column:csp" bson:"csp,omitempty" dynamodbav:"csp,omitempty" firestore:"csp"`
+	ReportOnly                bool   `yaml:"report_only" mapstructure:"report_only" json:"reportOnly,omitempty" gorm:"column:reportonly" bson:"reportOnly,omitempty" dynamodbav:"reportOnly,omitempty" firestore:"reportOnly"`
+	ReportUri                 string `yaml:"report_uri" mapstructure:"report_uri" json:"reportUri,omitempty" gorm:"column:reporturi" bson:"reportUri,omitempty" dynamodbav:"reportUri,omitempty" firestore:"reportUri"`
+	Nonce                     string `yaml:"nonce" mapstructure:"nonce" json:"nonce,omitempty" gorm:"column:nonce" bson:"nonce,omitempty" dynamodbav:"nonce,omitempty" firestore:"nonce"`
+}
+
+// InitSecurityConfig sets the default values. Set a value to "-" to disable a header.
+func InitSecurityConfig(c SecurityConfig) SecurityConfig {
+	if c.HstsMaxAge == 0 {
+		c.HstsMaxAge = 31536000
+	}
+	if len(c.ContentTypeOptions) == 0 {
+		c.ContentTypeOptions = "nosniff"
+	}
+	if len(c.FrameOptions) == 0 {
+		c.FrameOptions = "DENY"
+	}
+	if len(c.ReferrerPolicy) == 0 {
+		c.ReferrerPolicy = "strict-origin-when-cross-origin"
+	}
+	if len(c.CrossOriginOpenerPolicy) == 0 {
+		c.CrossOriginOpenerPolicy = "same-origin"
+	}
+	if len(c.Csp) == 0 {
+		c.Csp = DefaultCsp
+	}
+	if len(c.Nonce) == 0 {
+		c.Nonce = Nonce
+	}
+	return c
+}
+
+type SecurityHandler struct {
+	Config SecurityConfig
+	Log    func(context.Context, string, ...map[string]interface{})
+	hsts   string
+	static map[string]string
+}
+
+func NewSecurityHandler(c SecurityConfig, opts ...func(context.Context, string, ...map[string]interface{})) *SecurityHandler {
+	var log func(context.Context, string, ...map[string]interface{})
+	if len(opts) > 0 {
+		log = opts[0]
+	}
+	c = InitSecurityConfig(c)
+	return &SecurityHandler{Config: c, Log: log, hsts: BuildHsts(c), static: BuildSecurityHeaders(c)}
+}
+
+func BuildHsts(c SecurityConfig) string {
+	if c.HstsMaxAge < 0 {
+		return ""
+	}
+	hsts := "max-age=" + strconv.FormatInt(c.HstsMaxAge, 10)
+	if c.HstsIncludeSubdomains {
+		hsts = hsts + "; includeSubDomains"
+	}
+	if c.HstsPreload {
+		hsts = hsts + "; preload"
+	}
+	return hsts
+}
+func BuildSecurityHeaders(c SecurityConfig) map[string]string {
+	headers := make(map[string]string)
+	set := func(key string, value string) {
+		if len(value) > 0 && value != "-" {
+			headers[key] = value
+		}
+	}
+	set("X-Content-Type-Options", c.ContentTypeOptions)
+	set("X-Frame-Options", c.FrameOptions)
+	set("Referrer-Policy", c.ReferrerPolicy)
+	set("Permissions-Policy", c.PermissionsPolicy)
+	set("Cross-Origin-Opener-Policy", c.CrossOriginOpenerPolicy)
+	set("Cross-Origin-Embedder-Policy", c.CrossOriginEmbedderPolicy)
+	set("Cross-Origin-Resource-Policy", c.CrossOriginResourcePolicy)
+	if len(c.ReportUri) > 0 {
+		headers["Reporting-Endpoints"] = `csp-endpoint="` + c.ReportUri + `"`
+	}
+	return headers
+}
+
+func GenerateNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// BuildCsp replaces {nonce} in the policy, and appends the report directives.
+func BuildCsp(c SecurityConfig, nonce string) string {
+	if c.Csp == "-" {
+		return ""
+	}
+	csp := strings.ReplaceAll(c.Csp, "{nonce}", nonce)
+	if len(c.ReportUri) > 0 {
+		csp = strings.TrimRight(strings.TrimSpace(csp), ";") + "; report-uri " + c.ReportUri + "; report-to csp-endpoint"
+	}
+	return csp
+}
+func CspHeader(c SecurityConfig) string {
+	if c.ReportOnly {
+		return "Content-Security-Policy-Report-Only"
+	}
+	return "Content-Security-Policy"
+}
+func IsHttps(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// SetSecurityHeaders sets the security headers, and returns the nonce of this request.
+func (h *SecurityHandler) SetSecurityHeaders(header http.Header, r *http.Request) string {
+	for k, v := range h.static {
+		header.Set(k, v)
+	}
+	if len(h.hsts) > 0 && IsHttps(r) {
+		header.Set("Strict-Transport-Security", h.hsts)
+	}
+	nonce := GenerateNonce()
+	if csp := BuildCsp(h.Config, nonce); len(csp) > 0 {
+		header.Set(CspHeader(h.Config), csp)
+	}
+	return nonce
+}
+func (h *SecurityHandler) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce := h.SetSecurityHeaders(w.Header(), r)
+		ctx := context.WithValue(r.Context(), h.Config.Nonce, nonce)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Report collects the violation reports, with the format of report-uri (application/csp-report) or of the Reporting API (application/reports+json).
+func (h *SecurityHandler) Report(w http.ResponseWriter, r *http.Request) {
+	reports, err := ReadReports(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.Log != nil {
+		for _, report := range reports {
+			h.Log(r.Context(), "csp violation", report)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func ReadReports(r *http.Request) ([]map[string]interface{}, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64*1024))
+	if err != nil {
+		return nil, err
+	}
+	trimmed := strings.TrimSpace(string(body))
+	if strings.HasPrefix(trimmed, "[") {
+		var reports []map[string]interface{}
+		if err = json.Unmarshal(body, &reports); err != nil {
+			return nil, err
+		}
+		return reports, nil
+	}
+	var report map[string]interface{}
+	if err = json.Unmarshal(body, &report); err != nil {
+		return nil, err
+	}
+	if v, ok := report["csp-report"].(map[string]interface{}); ok {
+		report = v
+	}
+	return []map[string]interface{}{report}, nil
+}
+
+func GetNonce(ctx context.Context, options ...string) string {
+	key := Nonce
+	if len(options) > 0 && len(options[0]) > 0 {
+		key = options[0]
+	}
+	if v, ok := ctx.Value(key).(string); ok {
+		return v
+	}
+	return ""
+}
@@ -1,43 +1,204 @@
 package health
 
-import "context"
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
 
 const (
-	StatusUp   = "UP"
-	StatusDown = "DOWN"
+	StatusUp       = "UP"
+	StatusDown     = "DOWN"
+	StatusDegraded = "DEGRADED"
+
+	LevelCritical = "critical"
+	LevelOptional = "optional"
+	LevelIgnored  = "ignored"
 )
 
+var ErrTimeout = errors.New("health check timeout")
+
+type Config struct {
+	Timeout   time.Duration            `yaml:"timeout" mapstructure:"timeout" json:"timeout,omitempty" gorm:"column:timeout" bson:"timeout,omitempty" dynamodbav:"timeout,omitempty" firestore:"timeout,omitempty"`
+	Timeouts  map[string]time.Duration `yaml:"timeouts" mapstructure:"timeouts" json:"timeouts,omitempty" gorm:"column:timeouts" bson:"timeouts,omitempty" dynamodbav:"timeouts,omitempty" firestore:"timeouts,omitempty"`
+	CacheTime time.Duration            `yaml:"cache_time" mapstructure:"cache_time" json:"cacheTime,omitempty" gorm:"column:cachetime" bson:"cacheTime,omitempty" dynamodbav:"cacheTime,omitempty" firestore:"cacheTime,omitempty"`
+	Levels    map[string]string        `yaml:"levels" mapstructure:"levels" json:"levels,omitempty" gorm:"column:levels" bson:"levels,omitempty" dynamodbav:"levels,omitempty" firestore:"levels,omitempty"`
+}
+
 func Check(ctx context.Context, services []Checker) Health {
-	health := Health{}
-	health.Status = StatusUp
-	healths := make(map[string]Health)
-	for _, service := range services {
-		sub := Health{}
-		c := context.Background()
-		d0, err := service.Check(c)
-		if err == nil {
-			sub.Status = StatusUp
-			if d0 != nil && len(d0) > 0 {
-				sub.Data = d0
+	return NewRunner(Config{}, services...).Check(ctx)
+}
+
+type result struct {
+	health  Health
+	expires time.Time
+	done    chan struct{}
+}
+
+// Runner runs the checkers concurrently. Each checker has its own deadline, derived from the context of the request,
+// and its result is cached for CacheTime, so that many probes at the same time call the dependency only once.
+// When the result is cached, the shared check keeps only its own deadline, so a canceled request does not fail the other requests.
+type Runner struct {
+	Config   Config
+	Checkers []Checker
+	mu       sync.Mutex
+	results  map[string]*result
+}
+
+func NewRunner(c Config, checkers ...Checker) *Runner {
+	if c.Timeout <= 0 {
+		c.Timeout = 5 * time.Second
+	}
+	return &Runner{Config: c, Checkers: checkers, results: make(map[string]*result)}
+}
+
+func (r *Runner) Check(ctx context.Context) Health {
+	health := Health{Status: StatusUp}
+	if len(r.Checkers) == 0 {
+		return health
+	}
+	healths := make(map[string]Health, len(r.Checkers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, service := range r.Checkers {
+		wg.Add(1)
+		go func(service Checker) {
+			defer wg.Done()
+			sub := r.get(ctx, service)
+			mu.Lock()
+			healths[service.Name()] = sub
+			mu.Unlock()
+		}(service)
+	}
+	wg.Wait()
+	for name, sub := range healths {
+		if sub.Status != StatusDown {
+			continue
+		}
+		switch r.Level(name) {
+		case LevelIgnored:
+		case LevelOptional:
+			if health.Status == StatusUp {
+				health.Status = StatusDegraded
 			}
-		} else {
-			sub.Status = StatusDown
+		default:
 			health.Status = StatusDown
-			if d0 != nil {
-				data := service.Build(c, d0, err)
-				if data != nil && len(data) > 0 {
-					sub.Data = data
-				}
-			} else {
-				data := make(map[string]interface{}, 0)
-				data["error"] = err.Error()
-				sub.Data = data
-			}
 		}
-		healths[service.Name()] = sub
-	}
-	if len(healths) > 0 {
-		health.Details = healths
 	}
+	health.Details = healths
 	return health
 }
+
+// Level returns the level of the checker: a critical checker which is DOWN makes the service DOWN, an optional one makes it DEGRADED, and an ignored one is only reported.
+func (r *Runner) Level(name string) string {
+	if level, ok := r.Config.Levels[name]; ok && len(level) > 0 {
+		return level
+	}
+	return LevelCritical
+}
+func (r *Runner) Timeout(name string) time.Duration {
+	if t, ok := r.Config.Timeouts[name]; ok && t > 0 {
+		return t
+	}
+	return r.Config.Timeout
+}
+
+func (r *Runner) get(ctx context.Context, service Checker) Health {
+	if r.Config.CacheTime <= 0 {
+		return CheckOne(ctx, service, r.Timeout(service.Name()))
+	}
+	name := service.Name()
+	r.mu.Lock()
+	res, ok := r.results[name]
+	if !ok || isExpired(res) {
+		res = &result{done: make(chan struct{})}
+		r.results[name] = res
+		// the shared check is not canceled by the request which starts it, only by the timeout of the checker
+		go func(res *result) {
+			res.health = CheckOne(detach(ctx), service, r.Timeout(name))
+			res.expires = time.Now().Add(r.Config.CacheTime)
+			close(res.done)
+		}(res)
+	}
+	r.mu.Unlock()
+	select {
+	case <-res.done:
+		return res.health
+	case <-ctx.Done():
+		return Health{Status: StatusDown, Data: map[string]interface{}{"error": ctx.Err().Error()}}
+	}
+}
+func isExpired(res *result) bool {
+	select {
+	case <-res.done:
+		return !time.Now().Before(res.expires)
+	default:
+		return false
+	}
+}
+
+// detached keeps the values of the context, without its deadline and cancellation.
+type detached struct {
+	context.Context
+}
+
+func detach(ctx context.Context) context.Context {
+	return detached{ctx}
+}
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+func (detached) Err() error {
+	return nil
+}
+
+// CheckOne runs the checker with a deadline. If the checker does not return in time, it is reported as DOWN. The latency is in milliseconds.
+func CheckOne(ctx context.Context, service Checker, timeout time.Duration) Health {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	type output struct {
+		data map[string]interface{}
+		err  error
+	}
+	ch := make(chan output, 1)
+	start := time.Now()
+	go func() {
+		data, err := service.Check(ctx)
+		ch <- output{data: data, err: err}
+	}()
+	var d0 map[string]interface{}
+	var err error
+	select {
+	case o := <-ch:
+		d0, err = o.data, o.err
+	case <-ctx.Done():
+		err = ErrTimeout
+		if ctx.Err() == context.Canceled {
+			err = ctx.Err()
+		}
+	}
+	latency := time.Since(start)
+	sub := Health{Status: StatusUp}
+	if err != nil {
+		sub.Status = StatusDown
+		if d0 != nil {
+			d0 = service.Build(ctx, d0, err)
+		}
+		if d0 == nil {
+			d0 = map[string]interface{}{"error": err.Error()}
+		}
+	}
+	sub.Data = make(map[string]interface{}, len(d0)+1)
+	for k, v := range d0 {
+		sub.Data[k] = v
+	}
+	sub.Data["latency"] = latency.Milliseconds()
+	return sub
+}
@@ -8,15 +8,24 @@ import (
 
 type Handler struct {
 	Checkers []health.Checker
+	Runner   *health.Runner
 }
 
 func NewHandler(checkers ...health.Checker) *Handler {
-	return &Handler{checkers}
+	return NewHandlerWithConfig(health.Config{}, checkers...)
+}
+func NewHandlerWithConfig(c health.Config, checkers ...health.Checker) *Handler {
+	return &Handler{Checkers: checkers, Runner: health.NewRunner(c, checkers...)}
 }
 
 func (c *Handler) Check(ctx echo.Context) error {
-	result := health.Check(ctx.Request().Context(), c.Checkers)
-	if result.Status == health.StatusUp {
+	var result health.Health
+	if c.Runner != nil {
+		result = c.Runner.Check(ctx.Request().Context())
+	} else {
+		result = health.Check(ctx.Request().Context(), c.Checkers)
+	}
+	if result.Status != health.StatusDown {
 		return ctx.JSON(http.StatusOK, result)
 	} else {
 		return ctx.JSON(http.StatusInternalServerError, result)
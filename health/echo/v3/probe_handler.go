@@ -0,0 +1,27 @@
+package echo
+
+import (
+	"github.com/core-go/core/health"
+	"github.com/labstack/echo"
+)
+
+type ProbeHandler struct {
+	Probes *health.Probes
+}
+
+func NewProbeHandler(probes *health.Probes) *ProbeHandler {
+	return &ProbeHandler{Probes: probes}
+}
+
+func (h *ProbeHandler) Live(ctx echo.Context) error {
+	result := h.Probes.Live(ctx.Request().Context())
+	return ctx.JSON(health.GetProbeStatusCode(result.Status), result)
+}
+func (h *ProbeHandler) Ready(ctx echo.Context) error {
+	result := h.Probes.Ready(ctx.Request().Context())
+	return ctx.JSON(health.GetProbeStatusCode(result.Status), result)
+}
+func (h *ProbeHandler) Startup(ctx echo.Context) error {
+	result := h.Probes.Startup(ctx.Request().Context())
+	return ctx.JSON(health.GetProbeStatusCode(result.Status), result)
+}
@@ -0,0 +1,27 @@
+package gin
+
+import (
+	"github.com/core-go/core/health"
+	"github.com/gin-gonic/gin"
+)
+
+type ProbeHandler struct {
+	Probes *health.Probes
+}
+
+func NewProbeHandler(probes *health.Probes) *ProbeHandler {
+	return &ProbeHandler{Probes: probes}
+}
+
+func (h *ProbeHandler) Live(ctx *gin.Context) {
+	result := h.Probes.Live(ctx.Request.Context())
+	ctx.JSON(health.GetProbeStatusCode(result.Status), result)
+}
+func (h *ProbeHandler) Ready(ctx *gin.Context) {
+	result := h.Probes.Ready(ctx.Request.Context())
+	ctx.JSON(health.GetProbeStatusCode(result.Status), result)
+}
+func (h *ProbeHandler) Startup(ctx *gin.Context) {
+	result := h.Probes.Startup(ctx.Request.Context())
+	ctx.JSON(health.GetProbeStatusCode(result.Status), result)
+}
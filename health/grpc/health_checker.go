@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type Config struct {
+	Target             string        `yaml:"target" mapstructure:"target" json:"target,omitempty" gorm:"column:target" bson:"target,omitempty" dynamodbav:"target,omitempty" firestore:"target,omitempty"`
+	Service            string        `yaml:"service" mapstructure:"service" json:"service,omitempty" gorm:"column:service" bson:"service,omitempty" dynamodbav:"service,omitempty" firestore:"service,omitempty"`
+	TLS                bool          `yaml:"tls" mapstructure:"tls" json:"tls,omitempty" gorm:"column:tls" bson:"tls,omitempty" dynamodbav:"tls,omitempty" firestore:"tls,omitempty"`
+	InsecureSkipVerify bool          `yaml:"insecure_skip_verify" mapstructure:"insecure_skip_verify" json:"insecureSkipVerify,omitempty" gorm:"column:insecureskipverify" bson:"insecureSkipVerify,omitempty" dynamodbav:"insecureSkipVerify,omitempty" firestore:"insecureSkipVerify,omitempty"`
+	Timeout            time.Duration `yaml:"timeout" mapstructure:"timeout" json:"timeout,omitempty" gorm:"column:timeout" bson:"timeout,omitempty" dynamodbav:"timeout,omitempty" firestore:"timeout,omitempty"`
+}
+
+// HealthChecker calls the standard gRPC health protocol (grpc.health.v1.Health/Check). An empty service checks the server as a whole.
+type HealthChecker struct {
+	name   string
+	Config Config
+}
+
+func NewHealthChecker(c Config, options ...string) *HealthChecker {
+	var name string
+	if len(options) >= 1 && len(options[0]) > 0 {
+		name = options[0]
+	} else {
+		name = "grpc"
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 4 * time.Second
+	}
+	return &HealthChecker{name: name, Config: c}
+}
+
+func (s *HealthChecker) Name() string {
+	return s.name
+}
+
+func (s *HealthChecker) Check(ctx context.Context) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	ctx, cancel := context.WithTimeout(ctx, s.Config.Timeout)
+	defer cancel()
+	creds := insecure.NewCredentials()
+	if s.Config.TLS {
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: s.Config.InsecureSkipVerify})
+	}
+	conn, err := grpc.NewClient(s.Config.Target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return res, err
+	}
+	defer conn.Close()
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: s.Config.Service})
+	if err != nil {
+		return res, err
+	}
+	status := resp.GetStatus()
+	res["status"] = status.String()
+	if status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return res, errors.New("status is " + status.String())
+	}
+	return res, nil
+}
+
+func (s *HealthChecker) Build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	if err == nil {
+		return data
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["error"] = err.Error()
+	return data
+}
@@ -1,22 +1,29 @@
 package health
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 )
 
 type Handler struct {
 	Checkers []Checker
+	Runner   *Runner
 }
 
 func NewHandler(checkers ...Checker) *Handler {
-	return &Handler{Checkers: checkers}
+	return NewHandlerWithConfig(Config{}, checkers...)
+}
+func NewHandlerWithConfig(c Config, checkers ...Checker) *Handler {
+	return &Handler{Checkers: checkers, Runner: NewRunner(c, checkers...)}
 }
 
 func (c *Handler) Check(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	h := Check(ctx, c.Checkers)
+	var h Health
+	if c.Runner != nil {
+		h = c.Runner.Check(r.Context())
+	} else {
+		h = Check(r.Context(), c.Checkers)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	if h.Status == StatusDown {
 		w.WriteHeader(http.StatusInternalServerError)
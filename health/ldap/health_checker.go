@@ -0,0 +1,72 @@
+package ldap
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	ld "github.com/core-go/core/ldap"
+)
+
+type HealthChecker struct {
+	name    string
+	Config  ld.LdapConfig
+	timeout time.Duration
+}
+
+// NewHealthChecker binds by the username and password of the config, or anonymously if the username is empty, then reads the base DN.
+func NewHealthChecker(c ld.LdapConfig, options ...string) *HealthChecker {
+	var name string
+	if len(options) >= 1 && len(options[0]) > 0 {
+		name = options[0]
+	} else {
+		name = "ldap"
+	}
+	timeout := 4 * time.Second
+	if c.Timeout > 0 {
+		timeout = time.Duration(c.Timeout) * time.Millisecond
+	}
+	return &HealthChecker{name: name, Config: c, timeout: timeout}
+}
+
+func (s *HealthChecker) Name() string {
+	return s.name
+}
+
+func (s *HealthChecker) Check(ctx context.Context) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	conn, err := ld.NewConn(s.Config)
+	if err != nil {
+		return res, err
+	}
+	defer conn.Close()
+	timeout := s.timeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+	conn.SetTimeout(timeout)
+	if len(s.Config.Username) > 0 {
+		if err = conn.Bind(s.Config.Username, s.Config.Password); err != nil {
+			return res, err
+		}
+	}
+	request := ldap.NewSearchRequest(s.Config.BaseDN, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, int(timeout.Seconds()), false, "(objectClass=*)", []string{"dn"}, nil)
+	sr, err := conn.Search(request)
+	if err != nil {
+		return res, err
+	}
+	res["entries"] = len(sr.Entries)
+	return res, nil
+}
+
+func (s *HealthChecker) Build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	if err == nil {
+		return data
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["error"] = err.Error()
+	return data
+}
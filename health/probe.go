@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+)
+
+// Probes serves the liveness, readiness and startup probes. Liveness does not check the dependencies, so that the pod is not restarted when a dependency is down.
+type Probes struct {
+	Runner       *Runner
+	started      int32
+	shuttingDown int32
+}
+
+func NewProbes(runner *Runner) *Probes {
+	return &Probes{Runner: runner}
+}
+
+func (p *Probes) SetStarted() {
+	atomic.StoreInt32(&p.started, 1)
+}
+func (p *Probes) Started() bool {
+	return atomic.LoadInt32(&p.started) == 1
+}
+
+// Shutdown makes the readiness probe fail, so that no new traffic is routed to the pod during the graceful shutdown.
+// Call it before http.Server.Shutdown, and wait for the drain delay (the period of the readiness probe multiplied by its failure threshold) before shutting down the server,
+// because the server does not accept the probe requests after its Shutdown is called.
+func (p *Probes) Shutdown() {
+	atomic.StoreInt32(&p.shuttingDown, 1)
+}
+func (p *Probes) ShuttingDown() bool {
+	return atomic.LoadInt32(&p.shuttingDown) == 1
+}
+
+func (p *Probes) Live(ctx context.Context) Health {
+	return Health{Status: StatusUp}
+}
+func (p *Probes) Ready(ctx context.Context) Health {
+	if p.ShuttingDown() {
+		return Health{Status: StatusDown, Data: map[string]interface{}{"reason": "shutting down"}}
+	}
+	return p.Runner.Check(ctx)
+}
+
+// Startup checks the dependencies until the critical ones are up once. After that, it always returns UP.
+func (p *Probes) Startup(ctx context.Context) Health {
+	if p.Started() {
+		return Health{Status: StatusUp}
+	}
+	h := p.Runner.Check(ctx)
+	if h.Status != StatusDown {
+		p.SetStarted()
+	}
+	return h
+}
+
+func GetProbeStatusCode(status string) int {
+	if status == StatusDown {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+type ProbeHandler struct {
+	Probes *Probes
+}
+
+func NewProbeHandler(probes *Probes) *ProbeHandler {
+	return &ProbeHandler{Probes: probes}
+}
+
+func (h *ProbeHandler) Live(w http.ResponseWriter, r *http.Request) {
+	respond(w, h.Probes.Live(r.Context()))
+}
+func (h *ProbeHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	respond(w, h.Probes.Ready(r.Context()))
+}
+func (h *ProbeHandler) Startup(w http.ResponseWriter, r *http.Request) {
+	respond(w, h.Probes.Startup(r.Context()))
+}
+func respond(w http.ResponseWriter, h Health) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(GetProbeStatusCode(h.Status))
+	json.NewEncoder(w).Encode(h)
+}
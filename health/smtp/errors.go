@@ -0,0 +1,5 @@
+package smtp
+
+import "errors"
+
+var errStartTLS = errors.New("server does not support STARTTLS")
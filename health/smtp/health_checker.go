@@ -0,0 +1,106 @@
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type Config struct {
+	Host               string        `yaml:"host" mapstructure:"host" json:"host,omitempty" gorm:"column:host" bson:"host,omitempty" dynamodbav:"host,omitempty" firestore:"host,omitempty"`
+	Port               int           `yaml:"port" mapstructure:"port" json:"port,omitempty" gorm:"column:port" bson:"port,omitempty" dynamodbav:"port,omitempty" firestore:"port,omitempty"`
+	SSL                bool          `yaml:"ssl" mapstructure:"ssl" json:"ssl,omitempty" gorm:"column:ssl" bson:"ssl,omitempty" dynamodbav:"ssl,omitempty" firestore:"ssl,omitempty"`
+	StartTLS           bool          `yaml:"start_tls" mapstructure:"start_tls" json:"startTLS,omitempty" gorm:"column:starttls" bson:"startTLS,omitempty" dynamodbav:"startTLS,omitempty" firestore:"startTLS,omitempty"`
+	InsecureSkipVerify bool          `yaml:"insecure_skip_verify" mapstructure:"insecure_skip_verify" json:"insecureSkipVerify,omitempty" gorm:"column:insecureskipverify" bson:"insecureSkipVerify,omitempty" dynamodbav:"insecureSkipVerify,omitempty" firestore:"insecureSkipVerify,omitempty"`
+	LocalName          string        `yaml:"local_name" mapstructure:"local_name" json:"localName,omitempty" gorm:"column:localname" bson:"localName,omitempty" dynamodbav:"localName,omitempty" firestore:"localName,omitempty"`
+	Timeout            time.Duration `yaml:"timeout" mapstructure:"timeout" json:"timeout,omitempty" gorm:"column:timeout" bson:"timeout,omitempty" dynamodbav:"timeout,omitempty" firestore:"timeout,omitempty"`
+}
+
+type HealthChecker struct {
+	name   string
+	Config Config
+}
+
+func NewHealthChecker(c Config, options ...string) *HealthChecker {
+	var name string
+	if len(options) >= 1 && len(options[0]) > 0 {
+		name = options[0]
+	} else {
+		name = "smtp"
+	}
+	if c.Port <= 0 {
+		if c.SSL {
+			c.Port = 465
+		} else {
+			c.Port = 25
+		}
+	}
+	if len(c.LocalName) == 0 {
+		c.LocalName = "localhost"
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 4 * time.Second
+	}
+	return &HealthChecker{name: name, Config: c}
+}
+
+func (s *HealthChecker) Name() string {
+	return s.name
+}
+
+// Check connects to the server, sends EHLO, upgrades the connection by STARTTLS if it is configured, then sends NOOP and QUIT.
+func (s *HealthChecker) Check(ctx context.Context) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	ctx, cancel := context.WithTimeout(ctx, s.Config.Timeout)
+	defer cancel()
+	address := net.JoinHostPort(s.Config.Host, strconv.Itoa(s.Config.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return res, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	tlsConfig := &tls.Config{ServerName: s.Config.Host, InsecureSkipVerify: s.Config.InsecureSkipVerify}
+	if s.Config.SSL {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, s.Config.Host)
+	if err != nil {
+		return res, err
+	}
+	defer client.Close()
+	if err = client.Hello(s.Config.LocalName); err != nil {
+		return res, err
+	}
+	if s.Config.StartTLS && !s.Config.SSL {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			res["startTLS"] = false
+			return res, errStartTLS
+		}
+		if err = client.StartTLS(tlsConfig); err != nil {
+			return res, err
+		}
+		res["startTLS"] = true
+	}
+	if err = client.Noop(); err != nil {
+		return res, err
+	}
+	return res, client.Quit()
+}
+
+func (s *HealthChecker) Build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	if err == nil {
+		return data
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["error"] = err.Error()
+	return data
+}
@@ -0,0 +1,14 @@
+package system
+
+import "context"
+
+func build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	if err == nil {
+		return data
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["error"] = err.Error()
+	return data
+}
@@ -0,0 +1,112 @@
+package system
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/core-go/core/client"
+	"github.com/core-go/core/server"
+)
+
+type CertChecker struct {
+	name   string
+	Config CertConfig
+}
+
+func NewCertChecker(c CertConfig, options ...string) *CertChecker {
+	name := "cert"
+	if len(options) > 0 && len(options[0]) > 0 {
+		name = options[0]
+	}
+	if c.MinValidity <= 0 {
+		c.MinValidity = 7 * 24 * time.Hour
+	}
+	return &CertChecker{name: name, Config: c}
+}
+func NewServerCertChecker(c server.ServerConfig, minValidity time.Duration, options ...string) *CertChecker {
+	files := make([]string, 0)
+	if len(c.Cert) > 0 {
+		files = append(files, c.Cert)
+	}
+	return NewCertChecker(CertConfig{Files: files, MinValidity: minValidity}, options...)
+}
+func NewClientCertChecker(c client.Conf, minValidity time.Duration, options ...string) *CertChecker {
+	files := make([]string, 0)
+	if len(c.CertFile) > 0 {
+		files = append(files, c.CertFile)
+	}
+	name := "client_cert"
+	if len(options) > 0 && len(options[0]) > 0 {
+		name = options[0]
+	}
+	return NewCertChecker(CertConfig{Files: files, MinValidity: minValidity}, name)
+}
+
+func (s *CertChecker) Name() string {
+	return s.name
+}
+
+// Check reports the earliest expiry of the certificates in each file. It fails if a certificate expires within MinValidity.
+func (s *CertChecker) Check(ctx context.Context) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	now := time.Now()
+	var failed error
+	for _, file := range s.Config.Files {
+		notAfter, subject, err := ReadCertExpiry(file)
+		if err != nil {
+			return res, err
+		}
+		remaining := notAfter.Sub(now)
+		res[file] = map[string]interface{}{"subject": subject, "notAfter": notAfter, "days": int(remaining.Hours() / 24)}
+		if failed != nil {
+			continue
+		}
+		if remaining <= 0 {
+			failed = fmt.Errorf("certificate %s of %s is expired", subject, file)
+		} else if remaining < s.Config.MinValidity {
+			failed = fmt.Errorf("certificate %s of %s expires at %s", subject, file, notAfter.Format(time.RFC3339))
+		}
+	}
+	return res, failed
+}
+
+func (s *CertChecker) Build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	return build(ctx, data, err)
+}
+
+// ReadCertExpiry returns the earliest expiry of the PEM certificates in the file, and the subject of that certificate.
+func ReadCertExpiry(file string) (time.Time, string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	var notAfter time.Time
+	var subject string
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, "", err
+		}
+		if notAfter.IsZero() || cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+			subject = cert.Subject.CommonName
+		}
+	}
+	if notAfter.IsZero() {
+		return notAfter, "", errors.New("no certificate in " + file)
+	}
+	return notAfter, subject, nil
+}
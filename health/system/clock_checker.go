@@ -0,0 +1,67 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type ClockChecker struct {
+	name   string
+	Config ClockConfig
+}
+
+// NewClockChecker compares the local clock with the Date header of the url, so that a clock skew, which breaks token expiry and one time passcodes, is detected.
+func NewClockChecker(c ClockConfig, options ...string) *ClockChecker {
+	name := "clock"
+	if len(options) > 0 && len(options[0]) > 0 {
+		name = options[0]
+	}
+	if c.MaxSkew <= 0 {
+		c.MaxSkew = 5 * time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 4 * time.Second
+	}
+	return &ClockChecker{name: name, Config: c}
+}
+
+func (s *ClockChecker) Name() string {
+	return s.name
+}
+
+func (s *ClockChecker) Check(ctx context.Context) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	ctx, cancel := context.WithTimeout(ctx, s.Config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.Config.Url, nil)
+	if err != nil {
+		return res, err
+	}
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return res, err
+	}
+	resp.Body.Close()
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return res, fmt.Errorf("invalid Date header: %w", err)
+	}
+	local := start.Add(time.Since(start) / 2)
+	skew := local.Sub(date)
+	if skew < 0 {
+		skew = -skew
+	}
+	res["skew"] = skew.Milliseconds()
+	// the Date header has a precision of one second
+	if skew > s.Config.MaxSkew+time.Second {
+		return res, fmt.Errorf("clock skew is %s, more than %s", skew, s.Config.MaxSkew)
+	}
+	return res, nil
+}
+
+func (s *ClockChecker) Build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	return build(ctx, data, err)
+}
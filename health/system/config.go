@@ -0,0 +1,25 @@
+package system
+
+import "time"
+
+type DiskConfig struct {
+	Paths          []string `yaml:"paths" mapstructure:"paths" json:"paths,omitempty" gorm:"column:paths" bson:"paths,omitempty" dynamodbav:"paths,omitempty" firestore:"paths,omitempty"`
+	MinFree        uint64   `yaml:"min_free" mapstructure:"min_free" json:"minFree,omitempty" gorm:"column:minfree" bson:"minFree,omitempty" dynamodbav:"minFree,omitempty" firestore:"minFree,omitempty"`
+	MinFreePercent float64  `yaml:"min_free_percent" mapstructure:"min_free_percent" json:"minFreePercent,omitempty" gorm:"column:minfreepercent" bson:"minFreePercent,omitempty" dynamodbav:"minFreePercent,omitempty" firestore:"minFreePercent,omitempty"`
+}
+
+type MemoryConfig struct {
+	MaxHeap uint64 `yaml:"max_heap" mapstructure:"max_heap" json:"maxHeap,omitempty" gorm:"column:maxheap" bson:"maxHeap,omitempty" dynamodbav:"maxHeap,omitempty" firestore:"maxHeap,omitempty"`
+	MaxRss  uint64 `yaml:"max_rss" mapstructure:"max_rss" json:"maxRss,omitempty" gorm:"column:maxrss" bson:"maxRss,omitempty" dynamodbav:"maxRss,omitempty" firestore:"maxRss,omitempty"`
+}
+
+type CertConfig struct {
+	Files       []string      `yaml:"files" mapstructure:"files" json:"files,omitempty" gorm:"column:files" bson:"files,omitempty" dynamodbav:"files,omitempty" firestore:"files,omitempty"`
+	MinValidity time.Duration `yaml:"min_validity" mapstructure:"min_validity" json:"minValidity,omitempty" gorm:"column:minvalidity" bson:"minValidity,omitempty" dynamodbav:"minValidity,omitempty" firestore:"minValidity,omitempty"`
+}
+
+type ClockConfig struct {
+	Url     string        `yaml:"url" mapstructure:"url" json:"url,omitempty" gorm:"column:url" bson:"url,omitempty" dynamodbav:"url,omitempty" firestore:"url,omitempty"`
+	MaxSkew time.Duration `yaml:"max_skew" mapstructure:"max_skew" json:"maxSkew,omitempty" gorm:"column:maxskew" bson:"maxSkew,omitempty" dynamodbav:"maxSkew,omitempty" firestore:"maxSkew,omitempty"`
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout" json:"timeout,omitempty" gorm:"column:timeout" bson:"timeout,omitempty" dynamodbav:"timeout,omitempty" firestore:"timeout,omitempty"`
+}
@@ -0,0 +1,54 @@
+package system
+
+import (
+	"context"
+	"fmt"
+)
+
+type DiskChecker struct {
+	name   string
+	Config DiskConfig
+}
+
+func NewDiskChecker(c DiskConfig, options ...string) *DiskChecker {
+	name := "disk"
+	if len(options) > 0 && len(options[0]) > 0 {
+		name = options[0]
+	}
+	if len(c.Paths) == 0 {
+		c.Paths = []string{"."}
+	}
+	return &DiskChecker{name: name, Config: c}
+}
+
+func (s *DiskChecker) Name() string {
+	return s.name
+}
+
+// Check reports the free space of each path. It fails if a path has less than MinFree bytes or MinFreePercent percent free.
+func (s *DiskChecker) Check(ctx context.Context) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	var failed error
+	for _, path := range s.Config.Paths {
+		total, free, err := diskUsage(path)
+		if err != nil {
+			return res, err
+		}
+		var percent float64
+		if total > 0 {
+			percent = float64(free) * 100 / float64(total)
+		}
+		res[path] = map[string]interface{}{"total": total, "free": free, "freePercent": percent}
+		if failed == nil && s.Config.MinFree > 0 && free < s.Config.MinFree {
+			failed = fmt.Errorf("free space of %s is %d bytes, less than %d bytes", path, free, s.Config.MinFree)
+		}
+		if failed == nil && s.Config.MinFreePercent > 0 && percent < s.Config.MinFreePercent {
+			failed = fmt.Errorf("free space of %s is %.2f%%, less than %.2f%%", path, percent, s.Config.MinFreePercent)
+		}
+	}
+	return res, failed
+}
+
+func (s *DiskChecker) Build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	return build(ctx, data, err)
+}
@@ -0,0 +1,47 @@
+package system
+
+import (
+	"context"
+	"fmt"
+)
+
+type FileDescriptorChecker struct {
+	name       string
+	MaxPercent float64
+}
+
+// NewFileDescriptorChecker fails if the open file descriptors are more than maxPercent percent of the soft limit.
+func NewFileDescriptorChecker(maxPercent float64, options ...string) *FileDescriptorChecker {
+	name := "fd"
+	if len(options) > 0 && len(options[0]) > 0 {
+		name = options[0]
+	}
+	return &FileDescriptorChecker{name: name, MaxPercent: maxPercent}
+}
+
+func (s *FileDescriptorChecker) Name() string {
+	return s.name
+}
+
+func (s *FileDescriptorChecker) Check(ctx context.Context) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	open, limit, err := openFiles()
+	if err != nil {
+		return res, err
+	}
+	res["open"] = open
+	if limit == 0 {
+		return res, nil
+	}
+	percent := float64(open) * 100 / float64(limit)
+	res["limit"] = limit
+	res["percent"] = percent
+	if s.MaxPercent > 0 && percent > s.MaxPercent {
+		return res, fmt.Errorf("%d of %d file descriptors are open, more than %.2f%%", open, limit, s.MaxPercent)
+	}
+	return res, nil
+}
+
+func (s *FileDescriptorChecker) Build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	return build(ctx, data, err)
+}
@@ -0,0 +1,37 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+type GoroutineChecker struct {
+	name string
+	Max  int
+}
+
+func NewGoroutineChecker(max int, options ...string) *GoroutineChecker {
+	name := "goroutines"
+	if len(options) > 0 && len(options[0]) > 0 {
+		name = options[0]
+	}
+	return &GoroutineChecker{name: name, Max: max}
+}
+
+func (s *GoroutineChecker) Name() string {
+	return s.name
+}
+
+func (s *GoroutineChecker) Check(ctx context.Context) (map[string]interface{}, error) {
+	count := runtime.NumGoroutine()
+	res := map[string]interface{}{"count": count}
+	if s.Max > 0 && count > s.Max {
+		return res, fmt.Errorf("there are %d goroutines, more than %d", count, s.Max)
+	}
+	return res, nil
+}
+
+func (s *GoroutineChecker) Build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	return build(ctx, data, err)
+}
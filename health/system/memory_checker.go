@@ -0,0 +1,67 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+type MemoryChecker struct {
+	name   string
+	Config MemoryConfig
+}
+
+func NewMemoryChecker(c MemoryConfig, options ...string) *MemoryChecker {
+	name := "memory"
+	if len(options) > 0 && len(options[0]) > 0 {
+		name = options[0]
+	}
+	return &MemoryChecker{name: name, Config: c}
+}
+
+func (s *MemoryChecker) Name() string {
+	return s.name
+}
+
+func (s *MemoryChecker) Check(ctx context.Context) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	res["heap"] = m.HeapAlloc
+	res["sys"] = m.Sys
+	rss, ok := getRss()
+	if ok {
+		res["rss"] = rss
+	}
+	if s.Config.MaxHeap > 0 && m.HeapAlloc > s.Config.MaxHeap {
+		return res, fmt.Errorf("heap is %d bytes, more than %d bytes", m.HeapAlloc, s.Config.MaxHeap)
+	}
+	if ok && s.Config.MaxRss > 0 && rss > s.Config.MaxRss {
+		return res, fmt.Errorf("rss is %d bytes, more than %d bytes", rss, s.Config.MaxRss)
+	}
+	return res, nil
+}
+
+func (s *MemoryChecker) Build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	return build(ctx, data, err)
+}
+
+// getRss reads the resident set size from /proc, so it is only available on Linux.
+func getRss() (uint64, bool) {
+	b, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) < 2 {
+		return 0, false
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return pages * uint64(os.Getpagesize()), true
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !freebsd
+
+package system
+
+import "errors"
+
+var errNotSupported = errors.New("not supported on this platform")
+
+func diskUsage(path string) (uint64, uint64, error) {
+	return 0, 0, errNotSupported
+}
+
+func openFiles() (int, uint64, error) {
+	return 0, 0, errNotSupported
+}
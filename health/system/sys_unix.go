@@ -0,0 +1,31 @@
+//go:build linux || darwin || freebsd
+
+package system
+
+import (
+	"os"
+	"syscall"
+)
+
+func diskUsage(path string) (uint64, uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+	return uint64(st.Blocks) * uint64(st.Bsize), uint64(st.Bavail) * uint64(st.Bsize), nil
+}
+
+func openFiles() (int, uint64, error) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		entries, err = os.ReadDir("/dev/fd")
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	var limit syscall.Rlimit
+	if err = syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return len(entries), 0, nil
+	}
+	return len(entries), uint64(limit.Cur), nil
+}
@@ -0,0 +1,70 @@
+package tcp
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+type Config struct {
+	Address            string        `yaml:"address" mapstructure:"address" json:"address,omitempty" gorm:"column:address" bson:"address,omitempty" dynamodbav:"address,omitempty" firestore:"address,omitempty"`
+	TLS                bool          `yaml:"tls" mapstructure:"tls" json:"tls,omitempty" gorm:"column:tls" bson:"tls,omitempty" dynamodbav:"tls,omitempty" firestore:"tls,omitempty"`
+	InsecureSkipVerify bool          `yaml:"insecure_skip_verify" mapstructure:"insecure_skip_verify" json:"insecureSkipVerify,omitempty" gorm:"column:insecureskipverify" bson:"insecureSkipVerify,omitempty" dynamodbav:"insecureSkipVerify,omitempty" firestore:"insecureSkipVerify,omitempty"`
+	Timeout            time.Duration `yaml:"timeout" mapstructure:"timeout" json:"timeout,omitempty" gorm:"column:timeout" bson:"timeout,omitempty" dynamodbav:"timeout,omitempty" firestore:"timeout,omitempty"`
+}
+
+type HealthChecker struct {
+	name   string
+	Config Config
+}
+
+func NewHealthChecker(c Config, options ...string) *HealthChecker {
+	var name string
+	if len(options) >= 1 && len(options[0]) > 0 {
+		name = options[0]
+	} else {
+		name = "tcp"
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 4 * time.Second
+	}
+	return &HealthChecker{name: name, Config: c}
+}
+
+func (s *HealthChecker) Name() string {
+	return s.name
+}
+
+// Check dials the address. If TLS is set, the handshake must succeed too.
+func (s *HealthChecker) Check(ctx context.Context) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	ctx, cancel := context.WithTimeout(ctx, s.Config.Timeout)
+	defer cancel()
+	var conn net.Conn
+	var err error
+	if s.Config.TLS {
+		host, _, _ := net.SplitHostPort(s.Config.Address)
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: host, InsecureSkipVerify: s.Config.InsecureSkipVerify}}
+		conn, err = dialer.DialContext(ctx, "tcp", s.Config.Address)
+	} else {
+		dialer := &net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", s.Config.Address)
+	}
+	if err != nil {
+		return res, err
+	}
+	res["remote"] = conn.RemoteAddr().String()
+	return res, conn.Close()
+}
+
+func (s *HealthChecker) Build(ctx context.Context, data map[string]interface{}, err error) map[string]interface{} {
+	if err == nil {
+		return data
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["error"] = err.Error()
+	return data
+}
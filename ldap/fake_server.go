@@ -0,0 +1,379 @@
+package ldap
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+const MatchingRuleInChain = "1.2.840.113556.1.4.1941"
+
+type FakeEntry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// FakeServer is an in-process LDAP server for tests. It supports simple bind, search with the common filters (including the matching rule in chain for nested groups) and unbind.
+type FakeServer struct {
+	Entries  []FakeEntry
+	Listener net.Listener
+	mu       sync.RWMutex
+	wg       sync.WaitGroup
+	connMu   sync.Mutex
+	conns    map[net.Conn]bool
+	closed   bool
+}
+
+func NewFakeServer(entries ...FakeEntry) *FakeServer {
+	return &FakeServer{Entries: entries}
+}
+
+// Start listens on a random local port and returns the url of the server, which can be used as LdapConfig.Server.
+func (s *FakeServer) Start() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	s.Listener = listener
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			if !s.track(conn) {
+				conn.Close()
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer s.untrack(conn)
+				s.serve(conn)
+			}()
+		}
+	}()
+	return "ldap://" + listener.Addr().String(), nil
+}
+
+func (s *FakeServer) Close() error {
+	if s.Listener == nil {
+		return nil
+	}
+	err := s.Listener.Close()
+	s.connMu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.connMu.Unlock()
+	s.wg.Wait()
+	return err
+}
+func (s *FakeServer) track(conn net.Conn) bool {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	if s.closed {
+		return false
+	}
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]bool)
+	}
+	s.conns[conn] = true
+	return true
+}
+func (s *FakeServer) untrack(conn net.Conn) {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	delete(s.conns, conn)
+}
+
+func (s *FakeServer) Add(entries ...FakeEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Entries = append(s.Entries, entries...)
+}
+
+func (s *FakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		if len(packet.Children) < 2 {
+			return
+		}
+		messageId, _ := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		var responses []*ber.Packet
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			responses = []*ber.Packet{s.bind(op)}
+		case ldap.ApplicationSearchRequest:
+			responses = s.search(op)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			responses = []*ber.Packet{newResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultUnwillingToPerform, "operation is not supported")}
+		}
+		for _, response := range responses {
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageId, "MessageID"))
+			envelope.AppendChild(response)
+			if _, err = conn.Write(envelope.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *FakeServer) bind(op *ber.Packet) *ber.Packet {
+	if len(op.Children) < 3 {
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultProtocolError, "invalid bind request")
+	}
+	dn := packetString(op.Children[1])
+	password := packetString(op.Children[2])
+	if len(dn) == 0 && len(password) == 0 {
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, "")
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, e := range s.Entries {
+		if strings.EqualFold(e.DN, dn) && len(e.Password) > 0 && e.Password == password {
+			return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, "")
+		}
+	}
+	return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials, "invalid credentials")
+}
+
+func (s *FakeServer) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) < 8 {
+		return []*ber.Packet{newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, "invalid search request")}
+	}
+	base := strings.ToLower(packetString(op.Children[0]))
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+	attributes := make([]string, 0)
+	for _, a := range op.Children[7].Children {
+		attributes = append(attributes, packetString(a))
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	responses := make([]*ber.Packet, 0)
+	for i := range s.Entries {
+		e := &s.Entries[i]
+		if !inScope(strings.ToLower(e.DN), base, scope) {
+			continue
+		}
+		ok, err := s.match(e, filter)
+		if err != nil {
+			return []*ber.Packet{newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultUnwillingToPerform, err.Error())}
+		}
+		if !ok {
+			continue
+		}
+		if sizeLimit > 0 && int64(len(responses)) >= sizeLimit {
+			return append(responses, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSizeLimitExceeded, ""))
+		}
+		responses = append(responses, newEntry(e, attributes))
+	}
+	return append(responses, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""))
+}
+
+func (s *FakeServer) match(e *FakeEntry, f *ber.Packet) (bool, error) {
+	switch f.Tag {
+	case ldap.FilterAnd:
+		for _, c := range f.Children {
+			ok, err := s.match(e, c)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case ldap.FilterOr:
+		for _, c := range f.Children {
+			ok, err := s.match(e, c)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case ldap.FilterNot:
+		if len(f.Children) != 1 {
+			return false, errors.New("invalid not filter")
+		}
+		ok, err := s.match(e, f.Children[0])
+		return !ok, err
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch, ldap.FilterGreaterOrEqual, ldap.FilterLessOrEqual:
+		if len(f.Children) != 2 {
+			return false, errors.New("invalid filter")
+		}
+		value := strings.ToLower(packetString(f.Children[1]))
+		for _, v := range e.values(packetString(f.Children[0])) {
+			v = strings.ToLower(v)
+			if (f.Tag == ldap.FilterGreaterOrEqual && v >= value) || (f.Tag == ldap.FilterLessOrEqual && v <= value) || v == value {
+				return true, nil
+			}
+		}
+		return false, nil
+	case ldap.FilterPresent:
+		return len(e.values(packetString(f))) > 0, nil
+	case ldap.FilterSubstrings:
+		if len(f.Children) != 2 {
+			return false, errors.New("invalid substrings filter")
+		}
+		for _, v := range e.values(packetString(f.Children[0])) {
+			if matchSubstrings(strings.ToLower(v), f.Children[1].Children) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case ldap.FilterExtensibleMatch:
+		var rule, attribute, value string
+		for _, c := range f.Children {
+			switch c.Tag {
+			case 1:
+				rule = packetString(c)
+			case 2:
+				attribute = packetString(c)
+			case 3:
+				value = packetString(c)
+			}
+		}
+		if rule != MatchingRuleInChain {
+			return false, errors.New("matching rule is not supported: " + rule)
+		}
+		return s.inChain(e, attribute, value, make(map[string]bool)), nil
+	}
+	return false, errors.New("filter is not supported")
+}
+
+// inChain returns true if the attribute of the entry contains the value, directly or through the entries which are referenced by the attribute.
+func (s *FakeServer) inChain(e *FakeEntry, attribute string, value string, visited map[string]bool) bool {
+	key := strings.ToLower(e.DN)
+	if visited[key] {
+		return false
+	}
+	visited[key] = true
+	for _, v := range e.values(attribute) {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+		if ref := s.find(v); ref != nil && s.inChain(ref, attribute, value, visited) {
+			return true
+		}
+	}
+	return false
+}
+func (s *FakeServer) find(dn string) *FakeEntry {
+	for i := range s.Entries {
+		if strings.EqualFold(s.Entries[i].DN, dn) {
+			return &s.Entries[i]
+		}
+	}
+	return nil
+}
+
+func (e *FakeEntry) values(attribute string) []string {
+	if strings.EqualFold(attribute, "dn") || strings.EqualFold(attribute, "distinguishedName") {
+		return []string{e.DN}
+	}
+	for k, v := range e.Attributes {
+		if strings.EqualFold(k, attribute) {
+			return v
+		}
+	}
+	return nil
+}
+
+func inScope(dn string, base string, scope int64) bool {
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return dn == base
+	case ldap.ScopeSingleLevel:
+		i := strings.Index(dn, ",")
+		return i >= 0 && dn[i+1:] == base
+	default:
+		return len(base) == 0 || dn == base || strings.HasSuffix(dn, ","+base)
+	}
+}
+func matchSubstrings(v string, parts []*ber.Packet) bool {
+	for _, p := range parts {
+		s := strings.ToLower(packetString(p))
+		switch p.Tag {
+		case 0:
+			if !strings.HasPrefix(v, s) {
+				return false
+			}
+			v = v[len(s):]
+		case 1:
+			i := strings.Index(v, s)
+			if i < 0 {
+				return false
+			}
+			v = v[i+len(s):]
+		case 2:
+			if !strings.HasSuffix(v, s) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func newResult(tag ber.Tag, code uint16, message string) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "diagnosticMessage"))
+	return p
+}
+func newEntry(e *FakeEntry, attributes []string) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.DN, "objectName"))
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for name, values := range e.Attributes {
+		if !requested(name, attributes) {
+			continue
+		}
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, v := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+		}
+		attribute.AppendChild(set)
+		list.AppendChild(attribute)
+	}
+	p.AppendChild(list)
+	return p
+}
+func requested(name string, attributes []string) bool {
+	if len(attributes) == 0 {
+		return true
+	}
+	for _, a := range attributes {
+		if a == "*" || strings.EqualFold(a, name) {
+			return true
+		}
+	}
+	return false
+}
+func packetString(p *ber.Packet) string {
+	if s, ok := p.Value.(string); ok {
+		return s
+	}
+	if p.Data != nil {
+		return p.Data.String()
+	}
+	return ""
+}
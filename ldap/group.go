@@ -0,0 +1,282 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	NestedNone     = ""
+	NestedMemberOf = "memberof"
+	NestedChain    = "chain"
+)
+
+type GroupConfig struct {
+	BaseDN   string         `yaml:"base_dn" mapstructure:"base_dn" json:"baseDN,omitempty" gorm:"column:basedn" bson:"baseDN,omitempty" dynamodbav:"baseDN,omitempty" firestore:"baseDN,omitempty"`
+	Nested   string         `yaml:"nested" mapstructure:"nested" json:"nested,omitempty" gorm:"column:nested" bson:"nested,omitempty" dynamodbav:"nested,omitempty" firestore:"nested,omitempty"`
+	MemberOf string         `yaml:"member_of" mapstructure:"member_of" json:"memberOf,omitempty" gorm:"column:memberof" bson:"memberOf,omitempty" dynamodbav:"memberOf,omitempty" firestore:"memberOf,omitempty"`
+	Member   string         `yaml:"member" mapstructure:"member" json:"member,omitempty" gorm:"column:member" bson:"member,omitempty" dynamodbav:"member,omitempty" firestore:"member,omitempty"`
+	MaxDepth int            `yaml:"max_depth" mapstructure:"max_depth" json:"maxDepth,omitempty" gorm:"column:maxdepth" bson:"maxDepth,omitempty" dynamodbav:"maxDepth,omitempty" firestore:"maxDepth,omitempty"`
+	Mappings []GroupMapping `yaml:"mappings" mapstructure:"mappings" json:"mappings,omitempty" gorm:"column:mappings" bson:"mappings,omitempty" dynamodbav:"mappings,omitempty" firestore:"mappings,omitempty"`
+}
+
+// GroupMapping maps a group to roles and privileges. The group is a full DN, or only the common name of the group.
+type GroupMapping struct {
+	Group      string   `yaml:"group" mapstructure:"group" json:"group,omitempty" gorm:"column:group" bson:"group,omitempty" dynamodbav:"group,omitempty" firestore:"group,omitempty"`
+	Roles      []string `yaml:"roles" mapstructure:"roles" json:"roles,omitempty" gorm:"column:roles" bson:"roles,omitempty" dynamodbav:"roles,omitempty" firestore:"roles,omitempty"`
+	Privileges []string `yaml:"privileges" mapstructure:"privileges" json:"privileges,omitempty" gorm:"column:privileges" bson:"privileges,omitempty" dynamodbav:"privileges,omitempty" firestore:"privileges,omitempty"`
+}
+
+func InitGroupConfig(c GroupConfig) GroupConfig {
+	if len(c.MemberOf) == 0 {
+		c.MemberOf = "memberOf"
+	}
+	if len(c.Member) == 0 {
+		c.Member = "member"
+	}
+	if c.MaxDepth <= 0 {
+		c.MaxDepth = 10
+	}
+	return c
+}
+
+type GroupLoader struct {
+	Config LdapConfig
+	Group  GroupConfig
+}
+
+func NewGroupLoader(c LdapConfig, g GroupConfig) *GroupLoader {
+	if len(c.Filter) == 0 {
+		c.Filter = "uid"
+	}
+	g = InitGroupConfig(g)
+	if len(g.BaseDN) == 0 {
+		g.BaseDN = c.BaseDN
+	}
+	return &GroupLoader{Config: c, Group: g}
+}
+
+// Load returns the group DNs of the user, which is searched by the filter of the config.
+func (l *GroupLoader) Load(ctx context.Context, id string) ([]string, error) {
+	conn, err := NewConn(l.Config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if len(l.Config.Username) > 0 {
+		if err = conn.Bind(l.Config.Username, l.Config.Password); err != nil {
+			return nil, err
+		}
+	}
+	entry, err := FindEntry(conn, l.Config, id, l.Group.MemberOf)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return l.GetGroups(conn, entry.DN, entry.GetAttributeValues(l.Group.MemberOf), make(map[string][]string))
+}
+
+// GetGroups resolves the groups of a user entry. With NestedMemberOf, the memberOf attributes of the groups are walked on the client side, and cache keeps the parents of the visited groups.
+// With NestedChain, the server resolves the nested groups by the matching rule in chain.
+func (l *GroupLoader) GetGroups(conn *ldap.Conn, dn string, memberOf []string, cache map[string][]string) ([]string, error) {
+	switch l.Group.Nested {
+	case NestedChain:
+		filter := fmt.Sprintf("(%s:%s:=%s)", l.Group.Member, MatchingRuleInChain, ldap.EscapeFilter(dn))
+		request := ldap.NewSearchRequest(l.Group.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, filter, []string{"dn"}, nil)
+		sr, err := conn.Search(request)
+		if err != nil {
+			return nil, err
+		}
+		groups := make([]string, 0, len(sr.Entries))
+		for _, e := range sr.Entries {
+			groups = append(groups, e.DN)
+		}
+		return groups, nil
+	case NestedMemberOf:
+		visited := make(map[string]bool)
+		groups := make([]string, 0)
+		level := memberOf
+		for depth := 0; depth < l.Group.MaxDepth && len(level) > 0; depth++ {
+			next := make([]string, 0)
+			for _, g := range level {
+				key := strings.ToLower(g)
+				if visited[key] {
+					continue
+				}
+				visited[key] = true
+				groups = append(groups, g)
+				parents, ok := cache[key]
+				if !ok {
+					var err error
+					parents, err = l.getMemberOf(conn, g)
+					if err != nil {
+						return nil, err
+					}
+					cache[key] = parents
+				}
+				next = append(next, parents...)
+			}
+			level = next
+		}
+		return groups, nil
+	default:
+		return memberOf, nil
+	}
+}
+func (l *GroupLoader) getMemberOf(conn *ldap.Conn, dn string) ([]string, error) {
+	request := ldap.NewSearchRequest(dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, 0, false, "(objectClass=*)", []string{l.Group.MemberOf}, nil)
+	sr, err := conn.Search(request)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(sr.Entries) == 0 {
+		return nil, nil
+	}
+	return sr.Entries[0].GetAttributeValues(l.Group.MemberOf), nil
+}
+
+// FindEntry searches one entry by the filter attribute of the config. If the domain is set, it is appended to the id, as LdapInfoLoader does.
+func FindEntry(conn *ldap.Conn, c LdapConfig, id string, attributes ...string) (*ldap.Entry, error) {
+	if len(c.Domain) > 0 && strings.Index(id, "@") < 0 {
+		id = id + "@" + c.Domain
+	}
+	if len(attributes) == 0 {
+		attributes = []string{"dn"}
+	}
+	filter := fmt.Sprintf("(&(%s=%s))", c.Filter, ldap.EscapeFilter(id))
+	request := ldap.NewSearchRequest(c.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false, filter, attributes, nil)
+	sr, err := conn.Search(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(sr.Entries) != 1 {
+		return nil, nil
+	}
+	return sr.Entries[0], nil
+}
+
+type GroupMapper struct {
+	Mappings []GroupMapping
+	BaseDN   string
+	names    map[string][]int
+	dns      []groupDN
+	base     *ldap.DN
+}
+type groupDN struct {
+	dn    *ldap.DN
+	index int
+}
+
+// NewGroupMapper creates the mapper. If the base DN is set, a mapping by name matches only the groups under the base DN.
+func NewGroupMapper(mappings []GroupMapping, opts ...string) *GroupMapper {
+	names := make(map[string][]int)
+	dns := make([]groupDN, 0)
+	for i, m := range mappings {
+		group := strings.TrimSpace(m.Group)
+		if dn := parseDN(group); dn != nil {
+			dns = append(dns, groupDN{dn: dn, index: i})
+		} else {
+			key := strings.ToLower(group)
+			names[key] = append(names[key], i)
+		}
+	}
+	var baseDN string
+	if len(opts) > 0 {
+		baseDN = strings.TrimSpace(opts[0])
+	}
+	return &GroupMapper{Mappings: mappings, BaseDN: baseDN, names: names, dns: dns, base: parseDN(baseDN)}
+}
+
+// Map returns the sorted and unique roles and privileges of the groups.
+// A mapping by DN matches the full DN of the group, and a mapping by name matches the common name of the group under the base DN.
+func (m *GroupMapper) Map(groups []string) ([]string, []string) {
+	roles := make(map[string]bool)
+	privileges := make(map[string]bool)
+	add := func(i int) {
+		for _, r := range m.Mappings[i].Roles {
+			roles[r] = true
+		}
+		for _, p := range m.Mappings[i].Privileges {
+			privileges[p] = true
+		}
+	}
+	for _, g := range groups {
+		name := g
+		if dn := parseDN(g); dn != nil {
+			for _, d := range m.dns {
+				if d.dn.EqualFold(dn) {
+					add(d.index)
+				}
+			}
+			if m.base != nil && !m.base.AncestorOfFold(dn) {
+				continue
+			}
+			name = GetCommonName(g)
+		} else if m.base != nil {
+			continue
+		}
+		for _, i := range m.names[strings.ToLower(name)] {
+			add(i)
+		}
+	}
+	return toSortedList(roles), toSortedList(privileges)
+}
+
+// Roles returns all roles of the mappings. The sync job only removes these roles, so the roles which are granted manually are kept.
+func (m *GroupMapper) Roles() []string {
+	roles := make(map[string]bool)
+	for _, mapping := range m.Mappings {
+		for _, r := range mapping.Roles {
+			roles[r] = true
+		}
+	}
+	return toSortedList(roles)
+}
+
+// Privileges returns all privileges of the mappings.
+func (m *GroupMapper) Privileges() []string {
+	privileges := make(map[string]bool)
+	for _, mapping := range m.Mappings {
+		for _, p := range mapping.Privileges {
+			privileges[p] = true
+		}
+	}
+	return toSortedList(privileges)
+}
+
+func parseDN(s string) *ldap.DN {
+	if strings.Index(s, "=") < 0 {
+		return nil
+	}
+	dn, err := ldap.ParseDN(s)
+	if err != nil || len(dn.RDNs) == 0 {
+		return nil
+	}
+	return dn
+}
+func GetCommonName(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return ""
+	}
+	for _, a := range parsed.RDNs[0].Attributes {
+		if strings.EqualFold(a.Type, "cn") {
+			return a.Value
+		}
+	}
+	return ""
+}
+func toSortedList(m map[string]bool) []string {
+	list := make([]string, 0, len(m))
+	for k := range m {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+	return list
+}
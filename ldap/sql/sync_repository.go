@@ -0,0 +1,213 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/core-go/core/ldap"
+)
+
+const (
+	driverPostgres   = "postgres"
+	driverMysql      = "mysql"
+	driverMssql      = "mssql"
+	driverOracle     = "oracle"
+	driverSqlite3    = "sqlite3"
+	driverNotSupport = "no support"
+)
+
+// SyncRepository saves the changes of each user in one transaction. If UserPrivilegeTable is empty, the privileges are not loaded or saved.
+type SyncRepository struct {
+	DB                 *sql.DB
+	UserTable          string
+	UserRoleTable      string
+	UserPrivilegeTable string
+	Id                 string
+	Username           string
+	Email              string
+	DisplayName        string
+	UserId             string
+	RoleId             string
+	PrivilegeId        string
+	Driver             string
+}
+
+func NewSyncRepository(db *sql.DB, userTable string, userRoleTable string, userPrivilegeTable string, options ...string) *SyncRepository {
+	columns := []string{"id", "username", "email", "display_name", "user_id", "role_id", "privilege_id"}
+	for i := 0; i < len(options) && i < len(columns); i++ {
+		if len(options[i]) > 0 {
+			columns[i] = options[i]
+		}
+	}
+	return &SyncRepository{DB: db, UserTable: userTable, UserRoleTable: userRoleTable, UserPrivilegeTable: userPrivilegeTable, Id: columns[0], Username: columns[1], Email: columns[2], DisplayName: columns[3], UserId: columns[4], RoleId: columns[5], PrivilegeId: columns[6], Driver: getDriver(db)}
+}
+
+func (r *SyncRepository) Load(ctx context.Context) ([]ldap.SyncUser, error) {
+	query := fmt.Sprintf("select %s, %s, %s, %s from %s", r.Id, r.Username, r.Email, r.DisplayName, r.UserTable)
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	users := make([]ldap.SyncUser, 0)
+	index := make(map[string]int)
+	for rows.Next() {
+		var u ldap.SyncUser
+		var username, email, displayName sql.NullString
+		if err = rows.Scan(&u.Id, &username, &email, &displayName); err != nil {
+			return nil, err
+		}
+		u.Username = username.String
+		u.Email = email.String
+		u.DisplayName = displayName.String
+		index[u.Id] = len(users)
+		users = append(users, u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	err = r.loadValues(ctx, r.UserRoleTable, r.RoleId, func(i int, v string) {
+		users[i].Roles = append(users[i].Roles, v)
+	}, index)
+	if err != nil || len(r.UserPrivilegeTable) == 0 {
+		return users, err
+	}
+	err = r.loadValues(ctx, r.UserPrivilegeTable, r.PrivilegeId, func(i int, v string) {
+		users[i].Privileges = append(users[i].Privileges, v)
+	}, index)
+	return users, err
+}
+func (r *SyncRepository) loadValues(ctx context.Context, table string, column string, add func(int, string), index map[string]int) error {
+	query := fmt.Sprintf("select %s, %s from %s", r.UserId, column, table)
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userId, value string
+		if err = rows.Scan(&userId, &value); err != nil {
+			return err
+		}
+		if i, ok := index[userId]; ok {
+			add(i, value)
+		}
+	}
+	return rows.Err()
+}
+
+// Save inserts or updates the user, and adds and removes the roles and privileges of the change in one transaction.
+func (r *SyncRepository) Save(ctx context.Context, user ldap.SyncUser, change ldap.SyncChange) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err = r.save(ctx, tx, user, change); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+func (r *SyncRepository) save(ctx context.Context, tx *sql.Tx, user ldap.SyncUser, change ldap.SyncChange) error {
+	var err error
+	if change.Action == ldap.SyncInsert {
+		query := replaceQueryArgs(r.Driver, fmt.Sprintf("insert into %s (%s, %s, %s, %s) values (?, ?, ?, ?)", r.UserTable, r.Id, r.Username, r.Email, r.DisplayName))
+		_, err = tx.ExecContext(ctx, query, user.Id, user.Username, user.Email, user.DisplayName)
+	} else if change.Action == ldap.SyncUpdate && len(change.Fields) > 0 {
+		query := replaceQueryArgs(r.Driver, fmt.Sprintf("update %s set %s = ?, %s = ?, %s = ? where %s = ?", r.UserTable, r.Username, r.Email, r.DisplayName, r.Id))
+		_, err = tx.ExecContext(ctx, query, user.Username, user.Email, user.DisplayName, user.Id)
+	}
+	if err != nil {
+		return err
+	}
+	if err = r.insertValues(ctx, tx, r.UserRoleTable, r.RoleId, user.Id, change.AddedRoles); err != nil {
+		return err
+	}
+	if err = r.deleteValues(ctx, tx, r.UserRoleTable, r.RoleId, user.Id, change.RemovedRoles); err != nil {
+		return err
+	}
+	if len(r.UserPrivilegeTable) == 0 {
+		return nil
+	}
+	if err = r.insertValues(ctx, tx, r.UserPrivilegeTable, r.PrivilegeId, user.Id, change.AddedPrivileges); err != nil {
+		return err
+	}
+	return r.deleteValues(ctx, tx, r.UserPrivilegeTable, r.PrivilegeId, user.Id, change.RemovedPrivileges)
+}
+func (r *SyncRepository) insertValues(ctx context.Context, tx *sql.Tx, table string, column string, userId string, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	query := replaceQueryArgs(r.Driver, fmt.Sprintf("insert into %s (%s, %s) values (?, ?)", table, r.UserId, column))
+	for _, v := range values {
+		if _, err := tx.ExecContext(ctx, query, userId, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (r *SyncRepository) deleteValues(ctx context.Context, tx *sql.Tx, table string, column string, userId string, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	query := replaceQueryArgs(r.Driver, fmt.Sprintf("delete from %s where %s = ? and %s in (%s)", table, r.UserId, column, placeholders))
+	args := make([]interface{}, 0, len(values)+1)
+	args = append(args, userId)
+	for _, v := range values {
+		args = append(args, v)
+	}
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func replaceQueryArgs(driver string, query string) string {
+	if driver == driverOracle || driver == driverPostgres || driver == driverMssql {
+		var x string
+		if driver == driverOracle {
+			x = ":val"
+		} else if driver == driverPostgres {
+			x = "$"
+		} else if driver == driverMssql {
+			x = "@p"
+		}
+		i := 1
+		k := strings.Index(query, "?")
+		if k >= 0 {
+			for {
+				query = strings.Replace(query, "?", x+fmt.Sprintf("%v", i), 1)
+				i = i + 1
+				k := strings.Index(query, "?")
+				if k < 0 {
+					return query
+				}
+			}
+		}
+	}
+	return query
+}
+
+func getDriver(db *sql.DB) string {
+	if db == nil {
+		return driverNotSupport
+	}
+	driver := reflect.TypeOf(db.Driver()).String()
+	switch driver {
+	case "*pq.Driver":
+		return driverPostgres
+	case "*godror.drv":
+		return driverOracle
+	case "*mysql.MySQLDriver":
+		return driverMysql
+	case "*mssql.Driver":
+		return driverMssql
+	case "*sqlite3.SQLiteDriver":
+		return driverSqlite3
+	default:
+		return driverNotSupport
+	}
+}
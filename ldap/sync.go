@@ -0,0 +1,309 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+var (
+	ErrEmptyDirectory  = errors.New("directory returns no users")
+	ErrTooManyRemovals = errors.New("too many users are removed from the directory")
+)
+
+const (
+	SyncInsert = "insert"
+	SyncUpdate = "update"
+	SyncRemove = "remove"
+)
+
+type SyncConfig struct {
+	Filter      string        `yaml:"filter" mapstructure:"filter" json:"filter,omitempty" gorm:"column:filter" bson:"filter,omitempty" dynamodbav:"filter,omitempty" firestore:"filter,omitempty"`
+	Id          string        `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id" bson:"id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	Username    string        `yaml:"username" mapstructure:"username" json:"username,omitempty" gorm:"column:username" bson:"username,omitempty" dynamodbav:"username,omitempty" firestore:"username,omitempty"`
+	Email       string        `yaml:"email" mapstructure:"email" json:"email,omitempty" gorm:"column:email" bson:"email,omitempty" dynamodbav:"email,omitempty" firestore:"email,omitempty"`
+	DisplayName string        `yaml:"display_name" mapstructure:"display_name" json:"displayName,omitempty" gorm:"column:displayname" bson:"displayName,omitempty" dynamodbav:"displayName,omitempty" firestore:"displayName,omitempty"`
+	PageSize    uint32        `yaml:"page_size" mapstructure:"page_size" json:"pageSize,omitempty" gorm:"column:pagesize" bson:"pageSize,omitempty" dynamodbav:"pageSize,omitempty" firestore:"pageSize,omitempty"`
+	Interval    time.Duration `yaml:"interval" mapstructure:"interval" json:"interval,omitempty" gorm:"column:interval" bson:"interval,omitempty" dynamodbav:"interval,omitempty" firestore:"interval,omitempty"`
+	MaxRemovals int           `yaml:"max_removals" mapstructure:"max_removals" json:"maxRemovals,omitempty" gorm:"column:maxremovals" bson:"maxRemovals,omitempty" dynamodbav:"maxRemovals,omitempty" firestore:"maxRemovals,omitempty"`
+}
+
+type SyncUser struct {
+	Id          string   `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	Username    string   `yaml:"username" mapstructure:"username" json:"username,omitempty" gorm:"column:username" bson:"username,omitempty" dynamodbav:"username,omitempty" firestore:"username,omitempty"`
+	Email       string   `yaml:"email" mapstructure:"email" json:"email,omitempty" gorm:"column:email" bson:"email,omitempty" dynamodbav:"email,omitempty" firestore:"email,omitempty"`
+	DisplayName string   `yaml:"display_name" mapstructure:"display_name" json:"displayName,omitempty" gorm:"column:displayname" bson:"displayName,omitempty" dynamodbav:"displayName,omitempty" firestore:"displayName,omitempty"`
+	Roles       []string `yaml:"roles" mapstructure:"roles" json:"roles,omitempty" gorm:"column:roles" bson:"roles,omitempty" dynamodbav:"roles,omitempty" firestore:"roles,omitempty"`
+	Privileges  []string `yaml:"privileges" mapstructure:"privileges" json:"privileges,omitempty" gorm:"column:privileges" bson:"privileges,omitempty" dynamodbav:"privileges,omitempty" firestore:"privileges,omitempty"`
+}
+
+type SyncChange struct {
+	UserId            string   `yaml:"user_id" mapstructure:"user_id" json:"userId,omitempty" gorm:"column:userid" bson:"userId,omitempty" dynamodbav:"userId,omitempty" firestore:"userId,omitempty"`
+	Action            string   `yaml:"action" mapstructure:"action" json:"action,omitempty" gorm:"column:action" bson:"action,omitempty" dynamodbav:"action,omitempty" firestore:"action,omitempty"`
+	Fields            []string `yaml:"fields" mapstructure:"fields" json:"fields,omitempty" gorm:"column:fields" bson:"fields,omitempty" dynamodbav:"fields,omitempty" firestore:"fields,omitempty"`
+	AddedRoles        []string `yaml:"added_roles" mapstructure:"added_roles" json:"addedRoles,omitempty" gorm:"column:addedroles" bson:"addedRoles,omitempty" dynamodbav:"addedRoles,omitempty" firestore:"addedRoles,omitempty"`
+	RemovedRoles      []string `yaml:"removed_roles" mapstructure:"removed_roles" json:"removedRoles,omitempty" gorm:"column:removedroles" bson:"removedRoles,omitempty" dynamodbav:"removedRoles,omitempty" firestore:"removedRoles,omitempty"`
+	AddedPrivileges   []string `yaml:"added_privileges" mapstructure:"added_privileges" json:"addedPrivileges,omitempty" gorm:"column:addedprivileges" bson:"addedPrivileges,omitempty" dynamodbav:"addedPrivileges,omitempty" firestore:"addedPrivileges,omitempty"`
+	RemovedPrivileges []string `yaml:"removed_privileges" mapstructure:"removed_privileges" json:"removedPrivileges,omitempty" gorm:"column:removedprivileges" bson:"removedPrivileges,omitempty" dynamodbav:"removedPrivileges,omitempty" firestore:"removedPrivileges,omitempty"`
+}
+
+type SyncReport struct {
+	DryRun    bool         `yaml:"dry_run" mapstructure:"dry_run" json:"dryRun" gorm:"column:dryrun" bson:"dryRun" dynamodbav:"dryRun" firestore:"dryRun"`
+	StartTime time.Time    `yaml:"start_time" mapstructure:"start_time" json:"startTime,omitempty" gorm:"column:starttime" bson:"startTime,omitempty" dynamodbav:"startTime,omitempty" firestore:"startTime,omitempty"`
+	EndTime   time.Time    `yaml:"end_time" mapstructure:"end_time" json:"endTime,omitempty" gorm:"column:endtime" bson:"endTime,omitempty" dynamodbav:"endTime,omitempty" firestore:"endTime,omitempty"`
+	Total     int          `yaml:"total" mapstructure:"total" json:"total" gorm:"column:total" bson:"total" dynamodbav:"total" firestore:"total"`
+	Unchanged int          `yaml:"unchanged" mapstructure:"unchanged" json:"unchanged" gorm:"column:unchanged" bson:"unchanged" dynamodbav:"unchanged" firestore:"unchanged"`
+	Changes   []SyncChange `yaml:"changes" mapstructure:"changes" json:"changes,omitempty" gorm:"column:changes" bson:"changes,omitempty" dynamodbav:"changes,omitempty" firestore:"changes,omitempty"`
+}
+
+// SyncRepository writes to the user, user role and user privilege tables, which are read by PrivilegesLoader.
+// Save applies all changes of one user at once, so that a failed sync does not leave a user half updated.
+type SyncRepository interface {
+	Load(ctx context.Context) ([]SyncUser, error)
+	Save(ctx context.Context, user SyncUser, change SyncChange) error
+}
+
+type DirectorySync struct {
+	Config     LdapConfig
+	Sync       SyncConfig
+	Groups     *GroupLoader
+	Mapper     *GroupMapper
+	Repository SyncRepository
+	LogError   func(context.Context, string, ...map[string]interface{})
+	LogInfo    func(context.Context, string, ...map[string]interface{})
+}
+
+func NewDirectorySync(c LdapConfig, s SyncConfig, g GroupConfig, repository SyncRepository, logs ...func(context.Context, string, ...map[string]interface{})) *DirectorySync {
+	if len(s.Filter) == 0 {
+		s.Filter = "(objectClass=person)"
+	}
+	if len(s.Id) == 0 {
+		s.Id = "uid"
+	}
+	if len(s.Username) == 0 {
+		s.Username = s.Id
+	}
+	if s.PageSize == 0 {
+		s.PageSize = 500
+	}
+	var logError, logInfo func(context.Context, string, ...map[string]interface{})
+	if len(logs) > 0 {
+		logError = logs[0]
+	}
+	if len(logs) > 1 {
+		logInfo = logs[1]
+	}
+	groups := NewGroupLoader(c, g)
+	return &DirectorySync{Config: c, Sync: s, Groups: groups, Mapper: NewGroupMapper(groups.Group.Mappings, groups.Group.BaseDN), Repository: repository, LogError: logError, LogInfo: logInfo}
+}
+
+// Start runs the sync every interval until the context is done.
+func (s *DirectorySync) Start(ctx context.Context) {
+	if s.Sync.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.Sync.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.Run(ctx, false)
+			if err != nil {
+				if s.LogError != nil {
+					s.LogError(ctx, "cannot sync directory: "+err.Error())
+				}
+			} else if s.LogInfo != nil {
+				s.LogInfo(ctx, "directory is synchronized", map[string]interface{}{"total": report.Total, "changes": len(report.Changes)})
+			}
+		}
+	}
+}
+
+// Run compares the directory with the repository. If dryRun is true, the changes are only reported.
+// Only the roles and privileges of the mappings are removed, and the users which are not in the directory lose them.
+// To protect from a wrong filter or a broken directory, the sync is aborted if the directory returns no users,
+// or if more than MaxRemovals users are not in the directory.
+func (s *DirectorySync) Run(ctx context.Context, dryRun bool) (*SyncReport, error) {
+	report := &SyncReport{DryRun: dryRun, StartTime: time.Now(), Changes: make([]SyncChange, 0)}
+	users, err := s.LoadDirectory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.Repository.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 && len(existing) > 0 {
+		return nil, ErrEmptyDirectory
+	}
+	current := make(map[string]SyncUser)
+	for _, u := range existing {
+		current[u.Id] = u
+	}
+	if s.Sync.MaxRemovals > 0 {
+		found := make(map[string]bool)
+		for _, u := range users {
+			found[u.Id] = true
+		}
+		removals := 0
+		for id := range current {
+			if !found[id] {
+				removals++
+			}
+		}
+		if removals > s.Sync.MaxRemovals {
+			return nil, ErrTooManyRemovals
+		}
+	}
+	managed := toSet(s.Mapper.Roles())
+	managedPrivileges := toSet(s.Mapper.Privileges())
+	report.Total = len(users)
+	for _, u := range users {
+		old, ok := current[u.Id]
+		delete(current, u.Id)
+		change := SyncChange{UserId: u.Id}
+		if !ok {
+			change.Action = SyncInsert
+			change.AddedRoles = u.Roles
+			change.AddedPrivileges = u.Privileges
+		} else {
+			change.Fields = diffFields(old, u)
+			if len(change.Fields) > 0 {
+				change.Action = SyncUpdate
+			}
+			change.AddedRoles, change.RemovedRoles = diffList(old.Roles, u.Roles, managed)
+			change.AddedPrivileges, change.RemovedPrivileges = diffList(old.Privileges, u.Privileges, managedPrivileges)
+		}
+		if len(change.Action) == 0 && !hasChanges(change) {
+			report.Unchanged++
+			continue
+		}
+		if len(change.Action) == 0 {
+			change.Action = SyncUpdate
+		}
+		report.Changes = append(report.Changes, change)
+		if !dryRun {
+			if err = s.Repository.Save(ctx, u, change); err != nil {
+				return report, err
+			}
+		}
+	}
+	ids := make([]string, 0, len(current))
+	for id := range current {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		_, removed := diffList(current[id].Roles, nil, managed)
+		_, removedPrivileges := diffList(current[id].Privileges, nil, managedPrivileges)
+		change := SyncChange{UserId: id, Action: SyncRemove, RemovedRoles: removed, RemovedPrivileges: removedPrivileges}
+		if !hasChanges(change) {
+			continue
+		}
+		report.Changes = append(report.Changes, change)
+		if !dryRun {
+			if err = s.Repository.Save(ctx, current[id], change); err != nil {
+				return report, err
+			}
+		}
+	}
+	report.EndTime = time.Now()
+	return report, nil
+}
+
+// LoadDirectory searches the users by the filter of the sync config, and maps their groups to roles.
+func (s *DirectorySync) LoadDirectory(ctx context.Context) ([]SyncUser, error) {
+	conn, err := NewConn(s.Config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if len(s.Config.Username) > 0 {
+		if err = conn.Bind(s.Config.Username, s.Config.Password); err != nil {
+			return nil, err
+		}
+	}
+	attributes := []string{s.Sync.Id, s.Sync.Username, s.Groups.Group.MemberOf}
+	if len(s.Sync.Email) > 0 {
+		attributes = append(attributes, s.Sync.Email)
+	}
+	if len(s.Sync.DisplayName) > 0 {
+		attributes = append(attributes, s.Sync.DisplayName)
+	}
+	request := ldap.NewSearchRequest(s.Config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, s.Sync.Filter, attributes, nil)
+	sr, err := conn.SearchWithPaging(request, s.Sync.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	cache := make(map[string][]string)
+	users := make([]SyncUser, 0, len(sr.Entries))
+	for _, e := range sr.Entries {
+		id := e.GetAttributeValue(s.Sync.Id)
+		if len(id) == 0 {
+			continue
+		}
+		groups, err := s.Groups.GetGroups(conn, e.DN, e.GetAttributeValues(s.Groups.Group.MemberOf), cache)
+		if err != nil {
+			return nil, err
+		}
+		roles, privileges := s.Mapper.Map(groups)
+		u := SyncUser{Id: id, Username: e.GetAttributeValue(s.Sync.Username), Roles: roles, Privileges: privileges}
+		if len(s.Sync.Email) > 0 {
+			u.Email = e.GetAttributeValue(s.Sync.Email)
+		}
+		if len(s.Sync.DisplayName) > 0 {
+			u.DisplayName = e.GetAttributeValue(s.Sync.DisplayName)
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func diffFields(old SyncUser, u SyncUser) []string {
+	fields := make([]string, 0)
+	if old.Username != u.Username {
+		fields = append(fields, "username")
+	}
+	if !strings.EqualFold(old.Email, u.Email) {
+		fields = append(fields, "email")
+	}
+	if old.DisplayName != u.DisplayName {
+		fields = append(fields, "displayName")
+	}
+	return fields
+}
+func hasChanges(change SyncChange) bool {
+	return len(change.AddedRoles) > 0 || len(change.RemovedRoles) > 0 || len(change.AddedPrivileges) > 0 || len(change.RemovedPrivileges) > 0
+}
+func toSet(list []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, v := range list {
+		set[v] = true
+	}
+	return set
+}
+func diffList(old []string, values []string, managed map[string]bool) ([]string, []string) {
+	oldSet := toSet(old)
+	newSet := make(map[string]bool)
+	added := make([]string, 0)
+	for _, r := range values {
+		newSet[r] = true
+		if !oldSet[r] {
+			added = append(added, r)
+		}
+	}
+	removed := make([]string, 0)
+	for _, r := range old {
+		if managed[r] && !newSet[r] {
+			removed = append(removed, r)
+		}
+	}
+	sort.Strings(removed)
+	return added, removed
+}
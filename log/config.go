@@ -8,12 +8,16 @@ type Config struct {
 	Duration        string           `yaml:"duration" mapstructure:"duration" json:"duration,omitempty" gorm:"column:duration" bson:"duration,omitempty" dynamodbav:"duration,omitempty" firestore:"duration,omitempty"`
 	Fields          string           `yaml:"fields" mapstructure:"fields" json:"fields,omitempty" gorm:"column:fields" bson:"fields,omitempty" dynamodbav:"fields,omitempty" firestore:"fields,omitempty"`
 	FieldMap        string           `yaml:"field_map" mapstructure:"field_map" json:"fieldMap,omitempty" gorm:"column:fieldmap" bson:"fieldMap,omitempty" dynamodbav:"fieldMap,omitempty" firestore:"fieldMap,omitempty"`
+	TraceId         string           `yaml:"trace_id" mapstructure:"trace_id" json:"traceId,omitempty" gorm:"column:traceid" bson:"traceId,omitempty" dynamodbav:"traceId,omitempty" firestore:"traceId,omitempty"`
+	SpanId          string           `yaml:"span_id" mapstructure:"span_id" json:"spanId,omitempty" gorm:"column:spanid" bson:"spanId,omitempty" dynamodbav:"spanId,omitempty" firestore:"spanId,omitempty"`
 	Map             *logrus.FieldMap `yaml:"map" mapstructure:"map" json:"map,omitempty" gorm:"column:map" bson:"map,omitempty" dynamodbav:"map,omitempty" firestore:"map,omitempty"`
 	TimestampFormat string           `yaml:"timestamp_format" mapstructure:"timestamp_format" json:"timestampFormat,omitempty" gorm:"column:timestampformat" bson:"timestampFormat,omitempty" dynamodbav:"timestampFormat,omitempty" firestore:"timestampFormat,omitempty"`
 }
 
 type FieldConfig struct {
 	FieldMap string    `yaml:"field_map" mapstructure:"field_map" json:"fieldMap,omitempty" gorm:"column:fieldmap" bson:"fieldMap,omitempty" dynamodbav:"fieldMap,omitempty" firestore:"fieldMap,omitempty"`
+	TraceId  string    `yaml:"trace_id" mapstructure:"trace_id" json:"traceId,omitempty" gorm:"column:traceid" bson:"traceId,omitempty" dynamodbav:"traceId,omitempty" firestore:"traceId,omitempty"`
+	SpanId   string    `yaml:"span_id" mapstructure:"span_id" json:"spanId,omitempty" gorm:"column:spanid" bson:"spanId,omitempty" dynamodbav:"spanId,omitempty" firestore:"spanId,omitempty"`
 	Duration string    `yaml:"duration" mapstructure:"duration" json:"duration,omitempty" gorm:"column:duration" bson:"duration,omitempty" dynamodbav:"duration,omitempty" firestore:"duration,omitempty"`
 	Fields   *[]string `yaml:"fields" mapstructure:"fields" json:"fields,omitempty" gorm:"column:fields" bson:"fields,omitempty" dynamodbav:"fields,omitempty" firestore:"fields,omitempty"`
 }
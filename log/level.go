@@ -0,0 +1,98 @@
+package log
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/core-go/core/log/level"
+)
+
+// Leveler changes the level of a logger at runtime. Without logger, it changes the standard logger and the logger of Initialize.
+type Leveler struct {
+	Logger *logrus.Logger
+}
+
+func NewLeveler(loggers ...*logrus.Logger) *Leveler {
+	var l *logrus.Logger
+	if len(loggers) > 0 {
+		l = loggers[0]
+	}
+	return &Leveler{Logger: l}
+}
+
+func (l *Leveler) GetLevel() string {
+	if l.Logger != nil {
+		return l.Logger.GetLevel().String()
+	}
+	return logrus.GetLevel().String()
+}
+
+func (l *Leveler) SetLevel(lv string) error {
+	parsed, err := logrus.ParseLevel(lv)
+	if err != nil {
+		return err
+	}
+	if l.Logger != nil {
+		l.Logger.SetLevel(parsed)
+		return nil
+	}
+	logrus.SetLevel(parsed)
+	if logger != nil {
+		logger.SetLevel(parsed)
+	}
+	return nil
+}
+
+func isLevelEnabled(ctx context.Context, lv logrus.Level) bool {
+	if logrus.IsLevelEnabled(lv) {
+		return true
+	}
+	return lv <= logrus.DebugLevel && level.IsDebug(ctx)
+}
+
+var debugMu sync.Mutex
+var debugLogger *logrus.Logger
+
+// lockedWriter serializes the writes of the standard logger and the debug logger, which share the output.
+type lockedWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.out.Write(p)
+}
+
+// initDebugLogger is called by Initialize, after the output and the formatter of the standard logger are set.
+// It creates the logger at debug level for the requests with debug context, which shares the output with the standard logger.
+func initDebugLogger() {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	std := logrus.StandardLogger()
+	out, ok := std.Out.(*lockedWriter)
+	if !ok {
+		out = &lockedWriter{out: std.Out}
+		std.SetOutput(out)
+	}
+	debugLogger = &logrus.Logger{Out: out, Hooks: std.Hooks, Formatter: std.Formatter, ReportCaller: std.ReportCaller, Level: logrus.DebugLevel, ExitFunc: std.ExitFunc, BufferPool: std.BufferPool}
+}
+
+// getLogger returns the standard logger, or the debug logger of Initialize for the requests with debug context.
+func getLogger(ctx context.Context) *logrus.Logger {
+	std := logrus.StandardLogger()
+	if std.IsLevelEnabled(logrus.DebugLevel) || !level.IsDebug(ctx) {
+		return std
+	}
+	debugMu.Lock()
+	l := debugLogger
+	debugMu.Unlock()
+	if l == nil {
+		return std
+	}
+	return l
+}
@@ -0,0 +1,18 @@
+package level
+
+import "context"
+
+type debugKey struct{}
+
+// WithDebug marks the context, so that the log and zap packages write the debug logs of this request, whatever the global level is.
+func WithDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugKey{}, true)
+}
+
+func IsDebug(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(debugKey{}).(bool)
+	return v
+}
@@ -0,0 +1,142 @@
+package level
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const Root = "root"
+
+var ErrNotFound = errors.New("logger not found")
+
+// Leveler is implemented by the log (logrus) and zap packages.
+type Leveler interface {
+	GetLevel() string
+	SetLevel(level string) error
+}
+
+type LoggerLevel struct {
+	Name    string     `yaml:"name" mapstructure:"name" json:"name,omitempty" gorm:"column:name" bson:"name,omitempty" dynamodbav:"name,omitempty" firestore:"name,omitempty"`
+	Level   string     `yaml:"level" mapstructure:"level" json:"level,omitempty" gorm:"column:level" bson:"level,omitempty" dynamodbav:"level,omitempty" firestore:"level,omitempty"`
+	Default string     `yaml:"default" mapstructure:"default" json:"default,omitempty" gorm:"column:default" bson:"default,omitempty" dynamodbav:"default,omitempty" firestore:"default,omitempty"`
+	Expires *time.Time `yaml:"expires" mapstructure:"expires" json:"expires,omitempty" gorm:"column:expires" bson:"expires,omitempty" dynamodbav:"expires,omitempty" firestore:"expires,omitempty"`
+}
+
+type override struct {
+	original string
+	expires  time.Time
+	timer    *time.Timer
+}
+
+// Controller changes the levels of the registered loggers at runtime. A change with a ttl is reverted to the level before the first change when the ttl expires.
+type Controller struct {
+	mu        sync.Mutex
+	loggers   map[string]Leveler
+	overrides map[string]*override
+}
+
+func NewController(root Leveler) *Controller {
+	c := &Controller{loggers: make(map[string]Leveler), overrides: make(map[string]*override)}
+	if root != nil {
+		c.loggers[Root] = root
+	}
+	return c
+}
+
+func (c *Controller) Register(name string, leveler Leveler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loggers[name] = leveler
+}
+
+func (c *Controller) Levels() []LoggerLevel {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := make([]string, 0, len(c.loggers))
+	for name := range c.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	levels := make([]LoggerLevel, 0, len(names))
+	for _, name := range names {
+		l := LoggerLevel{Name: name, Level: c.loggers[name].GetLevel(), Default: c.loggers[name].GetLevel()}
+		if o, ok := c.overrides[name]; ok {
+			l.Default = o.original
+			if !o.expires.IsZero() {
+				expires := o.expires
+				l.Expires = &expires
+			}
+		}
+		levels = append(levels, l)
+	}
+	return levels
+}
+
+func (c *Controller) SetLevel(name string, level string, ttl time.Duration) error {
+	if len(name) == 0 {
+		name = Root
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	leveler, ok := c.loggers[name]
+	if !ok {
+		return ErrNotFound
+	}
+	current := leveler.GetLevel()
+	if err := leveler.SetLevel(level); err != nil {
+		return err
+	}
+	o, ok := c.overrides[name]
+	if !ok {
+		o = &override{original: current}
+		c.overrides[name] = o
+	}
+	if o.timer != nil {
+		o.timer.Stop()
+		o.timer = nil
+	}
+	o.expires = time.Time{}
+	if ttl > 0 {
+		o.expires = time.Now().Add(ttl)
+		o.timer = time.AfterFunc(ttl, func() {
+			c.revert(name, o)
+		})
+	}
+	return nil
+}
+
+// Reset sets the level before the first change back.
+func (c *Controller) Reset(name string) error {
+	if len(name) == 0 {
+		name = Root
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	leveler, ok := c.loggers[name]
+	if !ok {
+		return ErrNotFound
+	}
+	o, ok := c.overrides[name]
+	if !ok {
+		return nil
+	}
+	if o.timer != nil {
+		o.timer.Stop()
+	}
+	delete(c.overrides, name)
+	return leveler.SetLevel(o.original)
+}
+
+func (c *Controller) revert(name string, o *override) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.overrides[name] != o {
+		return
+	}
+	delete(c.overrides, name)
+	if leveler, ok := c.loggers[name]; ok {
+		leveler.SetLevel(o.original)
+	}
+}
@@ -0,0 +1,85 @@
+package level
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const HeaderDebug = "X-Debug-Token"
+
+type DebugConfig struct {
+	Header string        `yaml:"header" mapstructure:"header" json:"header,omitempty" gorm:"column:header" bson:"header,omitempty" dynamodbav:"header,omitempty" firestore:"header,omitempty"`
+	Secret string        `yaml:"secret" mapstructure:"secret" json:"secret,omitempty" gorm:"column:secret" bson:"secret,omitempty" dynamodbav:"secret,omitempty" firestore:"secret,omitempty"`
+	MaxAge time.Duration `yaml:"max_age" mapstructure:"max_age" json:"maxAge,omitempty" gorm:"column:maxage" bson:"maxAge,omitempty" dynamodbav:"maxAge,omitempty" firestore:"maxAge,omitempty"`
+}
+
+// Debugger turns on debug logging for the requests which have a valid token in the header.
+// The token is "<expiry in unix seconds>.<hex of HMAC-SHA256 of the expiry>", so that it cannot be forged without the secret, and it cannot be used after the expiry.
+type Debugger struct {
+	Config DebugConfig
+}
+
+func NewDebugger(c DebugConfig) *Debugger {
+	if len(c.Header) == 0 {
+		c.Header = HeaderDebug
+	}
+	if c.MaxAge <= 0 {
+		c.MaxAge = time.Hour
+	}
+	return &Debugger{Config: c}
+}
+
+func (d *Debugger) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, d.Apply(r))
+	})
+}
+
+// Apply returns the request with a debug context if the token is valid, or else the request as it is.
+func (d *Debugger) Apply(r *http.Request) *http.Request {
+	token := r.Header.Get(d.Config.Header)
+	if len(token) == 0 || !d.Verify(token, time.Now()) {
+		return r
+	}
+	return r.WithContext(WithDebug(r.Context()))
+}
+
+func (d *Debugger) Verify(token string, now time.Time) bool {
+	if len(d.Config.Secret) == 0 {
+		return false
+	}
+	i := strings.Index(token, ".")
+	if i <= 0 {
+		return false
+	}
+	expiry, err := strconv.ParseInt(token[:i], 10, 64)
+	if err != nil {
+		return false
+	}
+	expires := time.Unix(expiry, 0)
+	if !now.Before(expires) || expires.Sub(now) > d.Config.MaxAge {
+		return false
+	}
+	signature, err := hex.DecodeString(token[i+1:])
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(signature, sign(d.Config.Secret, token[:i]))
+}
+
+// GenerateToken creates a token which is valid until now + ttl. The ttl must not exceed the max age of the debugger.
+func GenerateToken(secret string, ttl time.Duration) string {
+	expiry := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	return expiry + "." + hex.EncodeToString(sign(secret, expiry))
+}
+
+func sign(secret string, expiry string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(expiry))
+	return mac.Sum(nil)
+}
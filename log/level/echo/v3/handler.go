@@ -0,0 +1,55 @@
+package echo
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/core-go/core/log/level"
+)
+
+type Handler struct {
+	Controller *level.Controller
+}
+
+func NewHandler(controller *level.Controller) *Handler {
+	return &Handler{Controller: controller}
+}
+
+func (h *Handler) GetLevels(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, h.Controller.Levels())
+}
+
+func (h *Handler) SetLevel(ctx echo.Context) error {
+	var req level.LevelRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.String(http.StatusBadRequest, err.Error())
+	}
+	status, err := level.Apply(h.Controller, req)
+	if err != nil {
+		return ctx.String(status, err.Error())
+	}
+	return ctx.JSON(status, h.Controller.Levels())
+}
+
+func (h *Handler) Reset(ctx echo.Context) error {
+	if err := h.Controller.Reset(ctx.QueryParam("name")); err != nil {
+		return ctx.String(http.StatusNotFound, err.Error())
+	}
+	return ctx.JSON(http.StatusOK, h.Controller.Levels())
+}
+
+type Debugger struct {
+	*level.Debugger
+}
+
+func NewDebugger(c level.DebugConfig) *Debugger {
+	return &Debugger{level.NewDebugger(c)}
+}
+
+func (d *Debugger) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.SetRequest(d.Apply(c.Request()))
+		return next(c)
+	}
+}
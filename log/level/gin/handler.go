@@ -0,0 +1,58 @@
+package gin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/core-go/core/log/level"
+)
+
+type Handler struct {
+	Controller *level.Controller
+}
+
+func NewHandler(controller *level.Controller) *Handler {
+	return &Handler{Controller: controller}
+}
+
+func (h *Handler) GetLevels(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, h.Controller.Levels())
+}
+
+func (h *Handler) SetLevel(ctx *gin.Context) {
+	var req level.LevelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	status, err := level.Apply(h.Controller, req)
+	if err != nil {
+		ctx.String(status, err.Error())
+		return
+	}
+	ctx.JSON(status, h.Controller.Levels())
+}
+
+func (h *Handler) Reset(ctx *gin.Context) {
+	if err := h.Controller.Reset(ctx.Query("name")); err != nil {
+		ctx.String(http.StatusNotFound, err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, h.Controller.Levels())
+}
+
+type Debugger struct {
+	*level.Debugger
+}
+
+func NewDebugger(c level.DebugConfig) *Debugger {
+	return &Debugger{level.NewDebugger(c)}
+}
+
+func (d *Debugger) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = d.Apply(c.Request)
+		c.Next()
+	}
+}
@@ -0,0 +1,87 @@
+package level
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type LevelRequest struct {
+	Name  string `yaml:"name" mapstructure:"name" json:"name,omitempty" gorm:"column:name" bson:"name,omitempty" dynamodbav:"name,omitempty" firestore:"name,omitempty"`
+	Level string `yaml:"level" mapstructure:"level" json:"level,omitempty" gorm:"column:level" bson:"level,omitempty" dynamodbav:"level,omitempty" firestore:"level,omitempty"`
+	TTL   string `yaml:"ttl" mapstructure:"ttl" json:"ttl,omitempty" gorm:"column:ttl" bson:"ttl,omitempty" dynamodbav:"ttl,omitempty" firestore:"ttl,omitempty"`
+}
+
+// Handler is the admin handler of the levels. It must be protected by the authorization of the application.
+// GET returns the levels, PUT or POST changes a level with an optional ttl such as "15m", DELETE?name= resets a level.
+type Handler struct {
+	Controller *Controller
+}
+
+func NewHandler(controller *Controller) *Handler {
+	return &Handler{Controller: controller}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetLevels(w, r)
+	case http.MethodPut, http.MethodPost:
+		h.SetLevel(w, r)
+	case http.MethodDelete:
+		h.Reset(w, r)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) GetLevels(w http.ResponseWriter, r *http.Request) {
+	respond(w, http.StatusOK, h.Controller.Levels())
+}
+
+func (h *Handler) SetLevel(w http.ResponseWriter, r *http.Request) {
+	var req LevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	status, err := Apply(h.Controller, req)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	respond(w, status, h.Controller.Levels())
+}
+
+func (h *Handler) Reset(w http.ResponseWriter, r *http.Request) {
+	if err := h.Controller.Reset(r.URL.Query().Get("name")); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	respond(w, http.StatusOK, h.Controller.Levels())
+}
+
+// Apply changes the level of the request, and returns the http status.
+func Apply(controller *Controller, req LevelRequest) (int, error) {
+	var ttl time.Duration
+	if len(req.TTL) > 0 {
+		var err error
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+	}
+	if err := controller.SetLevel(req.Name, req.Level, ttl); err != nil {
+		if err == ErrNotFound {
+			return http.StatusNotFound, err
+		}
+		return http.StatusBadRequest, err
+	}
+	return http.StatusOK, nil
+}
+
+func respond(w http.ResponseWriter, code int, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(result)
+}
@@ -9,6 +9,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/core-go/core/trace"
 )
 
 var fieldConfig FieldConfig
@@ -25,6 +27,9 @@ func Initialize(c Config, opts...func(logLocation string, rotationTime time.Dura
 	} else {
 		fieldConfig.Duration = "duration"
 	}
+	// the trace id and span id are logged only if their field names are configured
+	fieldConfig.TraceId = c.TraceId
+	fieldConfig.SpanId = c.SpanId
 	if len(c.Fields) > 0 {
 		fields := strings.Split(c.Fields, ",")
 		fieldConfig.Fields = &fields
@@ -61,6 +66,7 @@ func Initialize(c Config, opts...func(logLocation string, rotationTime time.Dura
 		}
 	}
 	logger = l
+	initDebugLogger()
 	return l
 }
 
@@ -126,50 +132,50 @@ func InfoDuration(ctx context.Context, start time.Time, args ...interface{}) {
 	LogDuration(ctx, logrus.InfoLevel, start, args)
 }
 func LogDuration(ctx context.Context, level logrus.Level, start time.Time, args ...interface{}) {
-	if logrus.IsLevelEnabled(level) {
+	if isLevelEnabled(ctx, level) {
 		end := time.Now()
 		duration := end.Sub(start)
 		fields := AppendFields(ctx, logrus.Fields{})
 		fields[fieldConfig.Duration] = duration.Milliseconds()
-		logrus.WithFields(fields).Log(level, args...)
+		getLogger(ctx).WithFields(fields).Log(level, args...)
 	}
 }
 func LogfDuration(ctx context.Context, level logrus.Level, start time.Time, format string, args ...interface{}) {
-	if logrus.IsLevelEnabled(level) {
+	if isLevelEnabled(ctx, level) {
 		end := time.Now()
 		duration := end.Sub(start)
 		fields := AppendFields(ctx, logrus.Fields{})
 		fields[fieldConfig.Duration] = duration.Milliseconds()
-		logrus.WithFields(fields).Logf(level, format, args...)
+		getLogger(ctx).WithFields(fields).Logf(level, format, args...)
 	}
 }
 
 func Log(ctx context.Context, level logrus.Level, args ...interface{}) {
-	if logrus.IsLevelEnabled(level) {
+	if isLevelEnabled(ctx, level) {
 		fields := AppendFields(ctx, logrus.Fields{})
 		if len(args) == 1 {
 			msg := args[0]
 			s1, ok := msg.(string)
 			if ok {
-				logrus.WithFields(fields).Log(level, s1)
+				getLogger(ctx).WithFields(fields).Log(level, s1)
 			} else {
 				bs, err := json.Marshal(msg)
 				if err != nil {
-					logrus.WithFields(fields).Log(level, args...)
+					getLogger(ctx).WithFields(fields).Log(level, args...)
 				} else {
 					s2 := string(bs)
-					logrus.WithFields(fields).Log(level, s2)
+					getLogger(ctx).WithFields(fields).Log(level, s2)
 				}
 			}
 		} else {
-			logrus.WithFields(fields).Log(level, args...)
+			getLogger(ctx).WithFields(fields).Log(level, args...)
 		}
 	}
 }
 func Logf(ctx context.Context, level logrus.Level, format string, args ...interface{}) {
-	if logrus.IsLevelEnabled(level) {
+	if isLevelEnabled(ctx, level) {
 		fields := AppendFields(ctx, logrus.Fields{})
-		logrus.WithFields(fields).Logf(level, format, args...)
+		getLogger(ctx).WithFields(fields).Logf(level, format, args...)
 	}
 }
 
@@ -211,6 +217,16 @@ func AppendFields(ctx context.Context, fields logrus.Fields) logrus.Fields {
 			}
 		}
 	}
+	if len(fieldConfig.TraceId) > 0 || len(fieldConfig.SpanId) > 0 {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			if len(fieldConfig.TraceId) > 0 {
+				fields[fieldConfig.TraceId] = sc.TraceID.String()
+			}
+			if len(fieldConfig.SpanId) > 0 {
+				fields[fieldConfig.SpanId] = sc.SpanID.String()
+			}
+		}
+	}
 	return fields
 }
 
@@ -250,29 +266,29 @@ func LogWithFields(ctx context.Context, level logrus.Level, msg interface{}, fie
 	if msg == nil {
 		return
 	}
-	if logrus.IsLevelEnabled(level) {
+	if isLevelEnabled(ctx, level) {
 		fs := BuildLogFields(fields)
 		fs2 := AppendFields(ctx, fs)
 		s1, ok := msg.(string)
 		if ok {
-			logrus.WithFields(fs2).Log(level, s1)
+			getLogger(ctx).WithFields(fs2).Log(level, s1)
 		} else {
 			bs, err := json.Marshal(msg)
 			if err != nil {
-				logrus.WithFields(fs2).Log(level, msg)
+				getLogger(ctx).WithFields(fs2).Log(level, msg)
 			} else {
 				s2 := string(bs)
-				logrus.WithFields(fs2).Log(level, s2)
+				getLogger(ctx).WithFields(fs2).Log(level, s2)
 			}
 		}
 	}
 }
 func LogfWithFields(ctx context.Context, level logrus.Level, fields map[string]interface{}, format string, args ...interface{}) {
-	if logrus.IsLevelEnabled(level) {
+	if isLevelEnabled(ctx, level) {
 		fs := BuildLogFields(fields)
 		fs2 := AppendFields(ctx, fs)
 		msg := fmt.Sprintf(format, args...)
-		logrus.WithFields(fs2).Log(level, msg)
+		getLogger(ctx).WithFields(fs2).Log(level, msg)
 	}
 }
 
@@ -280,7 +296,7 @@ func TraceWithFields(ctx context.Context, msg interface{}, fields map[string]int
 	LogWithFields(ctx, logrus.TraceLevel, msg, fields)
 }
 func TracefWithFields(ctx context.Context, fields map[string]interface{}, format string, args ...interface{}) {
-	if logrus.IsLevelEnabled(logrus.TraceLevel) {
+	if isLevelEnabled(ctx, logrus.TraceLevel) {
 		msg := fmt.Sprintf(format, args...)
 		LogWithFields(ctx, logrus.TraceLevel, msg, fields)
 	}
@@ -289,7 +305,7 @@ func DebugWithFields(ctx context.Context, msg interface{}, fields map[string]int
 	LogWithFields(ctx, logrus.DebugLevel, msg, fields)
 }
 func DebugfWithFields(ctx context.Context, fields map[string]interface{}, format string, args ...interface{}) {
-	if logrus.IsLevelEnabled(logrus.DebugLevel) {
+	if isLevelEnabled(ctx, logrus.DebugLevel) {
 		msg := fmt.Sprintf(format, args...)
 		LogWithFields(ctx, logrus.DebugLevel, msg, fields)
 	}
@@ -298,7 +314,7 @@ func InfoWithFields(ctx context.Context, msg interface{}, fields map[string]inte
 	LogWithFields(ctx, logrus.InfoLevel, msg, fields)
 }
 func InfofWithFields(ctx context.Context, fields map[string]interface{}, format string, args ...interface{}) {
-	if logrus.IsLevelEnabled(logrus.InfoLevel) {
+	if isLevelEnabled(ctx, logrus.InfoLevel) {
 		msg := fmt.Sprintf(format, args...)
 		LogWithFields(ctx, logrus.InfoLevel, msg, fields)
 	}
@@ -307,7 +323,7 @@ func WarnWithFields(ctx context.Context, msg interface{}, fields map[string]inte
 	LogWithFields(ctx, logrus.WarnLevel, msg, fields)
 }
 func WarnfWithFields(ctx context.Context, fields map[string]interface{}, format string, args ...interface{}) {
-	if logrus.IsLevelEnabled(logrus.WarnLevel) {
+	if isLevelEnabled(ctx, logrus.WarnLevel) {
 		msg := fmt.Sprintf(format, args...)
 		LogWithFields(ctx, logrus.WarnLevel, msg, fields)
 	}
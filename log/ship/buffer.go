@@ -0,0 +1,126 @@
+package ship
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var ErrBufferFull = errors.New("disk buffer is full")
+
+// Buffer keeps the logs in a file, as records with a 4 bytes length. Read does not remove the records, Commit removes the records of the last Read after they are sent.
+// The file is truncated when all records are committed, so the records of a crash are sent again.
+type Buffer struct {
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	offset int64
+	next   int64
+	max    int64
+}
+
+func NewBuffer(path string, max int64) (*Buffer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &Buffer{file: f, size: st.Size(), max: max}, nil
+}
+
+func (b *Buffer) Write(items [][]byte) error {
+	total := 0
+	for _, item := range items {
+		total += 4 + len(item)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.max > 0 && b.size+int64(total) > b.max {
+		return ErrBufferFull
+	}
+	buf := make([]byte, total)
+	i := 0
+	for _, item := range items {
+		binary.BigEndian.PutUint32(buf[i:], uint32(len(item)))
+		i += 4 + copy(buf[i+4:], item)
+	}
+	n, err := b.file.WriteAt(buf, b.size)
+	b.size += int64(n)
+	return err
+}
+
+// Read returns the next records, at most maxCount records and maxBytes bytes, but at least one record. The records are read again until Commit is called.
+func (b *Buffer) Read(maxCount int, maxBytes int) ([][]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var items [][]byte
+	bytes := 0
+	head := make([]byte, 4)
+	next := b.offset
+	for len(items) < maxCount && next < b.size {
+		if _, err := b.file.ReadAt(head, next); err != nil {
+			return items, b.cut(next)
+		}
+		n := int(binary.BigEndian.Uint32(head))
+		if next+4+int64(n) > b.size {
+			// the last record is incomplete, because of a crash
+			return items, b.cut(next)
+		}
+		if len(items) > 0 && bytes+n > maxBytes {
+			break
+		}
+		item := make([]byte, n)
+		if _, err := b.file.ReadAt(item, next+4); err != nil {
+			return items, b.cut(next)
+		}
+		items = append(items, item)
+		bytes += n
+		next += 4 + int64(n)
+		b.next = next
+	}
+	return items, nil
+}
+
+// Commit removes the records of the last Read.
+func (b *Buffer) Commit() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.next > b.offset {
+		b.offset = b.next
+	}
+	if b.offset >= b.size && b.size > 0 {
+		b.size = 0
+		b.offset = 0
+		b.next = 0
+		return b.file.Truncate(0)
+	}
+	return nil
+}
+
+// Size returns the bytes of the records, which are not read.
+func (b *Buffer) Size() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size - b.offset
+}
+
+func (b *Buffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.file.Close()
+}
+
+// cut removes the broken records from the position to the end.
+func (b *Buffer) cut(position int64) error {
+	b.size = position
+	return b.file.Truncate(position)
+}
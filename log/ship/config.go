@@ -0,0 +1,51 @@
+package ship
+
+import "time"
+
+// Config of Shipper. Retries is the number of retries after the first attempt, -1 for no retry.
+// Buffer is the path of the disk buffer, which keeps the logs when the queue is full or when a batch is not sent after the retries.
+type Config struct {
+	MaxQueue   int           `yaml:"max_queue" mapstructure:"max_queue" json:"maxQueue,omitempty" gorm:"column:maxqueue" bson:"maxQueue,omitempty" dynamodbav:"maxQueue,omitempty" firestore:"maxQueue,omitempty"`
+	BatchSize  int           `yaml:"batch_size" mapstructure:"batch_size" json:"batchSize,omitempty" gorm:"column:batchsize" bson:"batchSize,omitempty" dynamodbav:"batchSize,omitempty" firestore:"batchSize,omitempty"`
+	BatchBytes int           `yaml:"batch_bytes" mapstructure:"batch_bytes" json:"batchBytes,omitempty" gorm:"column:batchbytes" bson:"batchBytes,omitempty" dynamodbav:"batchBytes,omitempty" firestore:"batchBytes,omitempty"`
+	Interval   time.Duration `yaml:"interval" mapstructure:"interval" json:"interval,omitempty" gorm:"column:interval" bson:"interval,omitempty" dynamodbav:"interval,omitempty" firestore:"interval,omitempty"`
+	Timeout    time.Duration `yaml:"timeout" mapstructure:"timeout" json:"timeout,omitempty" gorm:"column:timeout" bson:"timeout,omitempty" dynamodbav:"timeout,omitempty" firestore:"timeout,omitempty"`
+	Retries    int           `yaml:"retries" mapstructure:"retries" json:"retries,omitempty" gorm:"column:retries" bson:"retries,omitempty" dynamodbav:"retries,omitempty" firestore:"retries,omitempty"`
+	Backoff    time.Duration `yaml:"backoff" mapstructure:"backoff" json:"backoff,omitempty" gorm:"column:backoff" bson:"backoff,omitempty" dynamodbav:"backoff,omitempty" firestore:"backoff,omitempty"`
+	MaxBackoff time.Duration `yaml:"max_backoff" mapstructure:"max_backoff" json:"maxBackoff,omitempty" gorm:"column:maxbackoff" bson:"maxBackoff,omitempty" dynamodbav:"maxBackoff,omitempty" firestore:"maxBackoff,omitempty"`
+	Buffer     string        `yaml:"buffer" mapstructure:"buffer" json:"buffer,omitempty" gorm:"column:buffer" bson:"buffer,omitempty" dynamodbav:"buffer,omitempty" firestore:"buffer,omitempty"`
+	MaxBuffer  int64         `yaml:"max_buffer" mapstructure:"max_buffer" json:"maxBuffer,omitempty" gorm:"column:maxbuffer" bson:"maxBuffer,omitempty" dynamodbav:"maxBuffer,omitempty" firestore:"maxBuffer,omitempty"`
+}
+
+func InitConfig(c Config) Config {
+	if c.MaxQueue <= 0 {
+		c.MaxQueue = 10000
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+	if c.BatchBytes <= 0 {
+		c.BatchBytes = 1024 * 1024
+	}
+	if c.Interval <= 0 {
+		c.Interval = 5 * time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	if c.Retries < 0 {
+		c.Retries = 0
+	} else if c.Retries == 0 {
+		c.Retries = 3
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = 500 * time.Millisecond
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 30 * time.Second
+	}
+	if c.MaxBuffer <= 0 {
+		c.MaxBuffer = 100 * 1024 * 1024
+	}
+	return c
+}
@@ -0,0 +1,67 @@
+package ship
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Lines sends the batch as newline delimited JSON in one message, by the send function of the middleware loggers, such as the writer of a queue.
+func Lines(send func(context.Context, []byte, map[string]string) error, opts ...map[string]string) Sender {
+	var headers map[string]string
+	if len(opts) > 0 {
+		headers = opts[0]
+	}
+	return func(ctx context.Context, batch [][]byte) error {
+		return send(ctx, bytes.Join(batch, []byte("\n")), headers)
+	}
+}
+
+// Each sends the logs of the batch one by one. If a log is not sent, the batch is sent again, so the logs are sent at least once.
+func Each(send func(context.Context, []byte, map[string]string) error, opts ...map[string]string) Sender {
+	var headers map[string]string
+	if len(opts) > 0 {
+		headers = opts[0]
+	}
+	return func(ctx context.Context, batch [][]byte) error {
+		for _, data := range batch {
+			if err := send(ctx, data, headers); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// NewHttpSender posts the batch as newline delimited JSON to the url.
+func NewHttpSender(client *http.Client, url string, opts ...map[string]string) Sender {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var headers map[string]string
+	if len(opts) > 0 {
+		headers = opts[0]
+	}
+	return func(ctx context.Context, batch [][]byte) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bytes.Join(batch, []byte("\n"))))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		io.Copy(io.Discard, res.Body)
+		if res.StatusCode >= 300 {
+			return fmt.Errorf("cannot send logs to %s: status %d", url, res.StatusCode)
+		}
+		return nil
+	}
+}
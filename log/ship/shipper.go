@@ -0,0 +1,315 @@
+package ship
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrDropped = errors.New("log is dropped, because the queue is full")
+var ErrClosed = errors.New("log is dropped, because the shipper is shut down")
+
+// Sender sends a batch of logs.
+type Sender func(ctx context.Context, batch [][]byte) error
+
+type Stats struct {
+	Queued  int   `json:"queued"`
+	Sent    int64 `json:"sent"`
+	Dropped int64 `json:"dropped"`
+	Failed  int64 `json:"failed"`
+	Retries int64 `json:"retries"`
+	Buffer  int64 `json:"buffer"`
+}
+
+// Shipper queues the logs without blocking, and sends them in batches, when the batch is full by count or bytes, or every interval.
+// When the queue is full, the logs are written to the disk buffer, or dropped if there is no buffer.
+// NewShipper starts Run, so Shutdown must be called to send the queued logs and to close the disk buffer.
+type Shipper struct {
+	Config   Config
+	Sender   Sender
+	LogError func(context.Context, string, ...map[string]interface{})
+	buffer   *Buffer
+	queue    chan []byte
+	bytes    int64
+	full     chan struct{}
+	carry    []byte
+	sendMu   sync.Mutex
+	mu       sync.Mutex
+	stop     chan struct{}
+	cancel   context.CancelFunc
+	closeMu  sync.RWMutex
+	closed   bool
+	wg       sync.WaitGroup
+	sent     int64
+	dropped  int64
+	failed   int64
+	retries  int64
+}
+
+func NewShipper(c Config, sender Sender, opts ...func(context.Context, string, ...map[string]interface{})) (*Shipper, error) {
+	var logError func(context.Context, string, ...map[string]interface{})
+	if len(opts) > 0 {
+		logError = opts[0]
+	}
+	c = InitConfig(c)
+	s := &Shipper{Config: c, Sender: sender, LogError: logError, queue: make(chan []byte, c.MaxQueue), full: make(chan struct{}, 1)}
+	if len(c.Buffer) > 0 {
+		b, err := NewBuffer(c.Buffer, c.MaxBuffer)
+		if err != nil {
+			return nil, err
+		}
+		s.buffer = b
+	}
+	s.Run()
+	return s, nil
+}
+
+// Send queues the log without blocking. It has the signature of the send function of the middleware loggers. The headers are not used.
+func (s *Shipper) Send(ctx context.Context, data []byte, headers map[string]string) error {
+	// Shutdown waits for the logs which are being queued, so that they are sent by the last Flush
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+	if s.closed {
+		atomic.AddInt64(&s.dropped, 1)
+		return ErrClosed
+	}
+	select {
+	case s.queue <- data:
+		n := atomic.AddInt64(&s.bytes, int64(len(data)))
+		if len(s.queue) >= s.Config.BatchSize || n >= int64(s.Config.BatchBytes) {
+			select {
+			case s.full <- struct{}{}:
+			default:
+			}
+		}
+		return nil
+	default:
+		if s.buffer != nil && s.buffer.Write([][]byte{data}) == nil {
+			return nil
+		}
+		atomic.AddInt64(&s.dropped, 1)
+		return ErrDropped
+	}
+}
+
+// Run sends the full batches, and sends all queued logs every interval, until Shutdown is called. It is started by NewShipper.
+func (s *Shipper) Run() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	stop := s.stop
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.mu.Unlock()
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.Config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.full:
+				s.flush(ctx, true)
+			case <-ticker.C:
+				s.Flush(ctx)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops Run, stops queuing, and sends the queued logs and the disk buffer until the context is done.
+// If the context is done before Run stops, the batch of Run is cancelled, and after Run stops, the queued logs are written to the disk buffer or counted as failed, and the disk buffer is closed.
+func (s *Shipper) Shutdown(ctx context.Context) error {
+	s.closeMu.Lock()
+	s.closed = true
+	s.closeMu.Unlock()
+	var cancel context.CancelFunc
+	s.mu.Lock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+		cancel = s.cancel
+		s.cancel = nil
+	}
+	s.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if cancel != nil {
+			cancel()
+		}
+		go func() {
+			<-done
+			// the context is done, so the queued logs are written to the disk buffer, or counted as failed
+			s.Flush(ctx)
+			s.closeBuffer()
+		}()
+		return ctx.Err()
+	}
+	if cancel != nil {
+		cancel()
+	}
+	err := s.Flush(ctx)
+	if er2 := s.closeBuffer(); err == nil {
+		err = er2
+	}
+	return err
+}
+func (s *Shipper) closeBuffer() error {
+	if s.buffer == nil {
+		return nil
+	}
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.buffer.Close()
+}
+
+// Flush sends all queued logs, then the disk buffer.
+func (s *Shipper) Flush(ctx context.Context) error {
+	return s.flush(ctx, false)
+}
+
+// flush keeps draining the queue when a batch is not sent, the batch is written to the disk buffer, or counted as failed. It returns the first error.
+func (s *Shipper) flush(ctx context.Context, fullOnly bool) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	var first error
+	for {
+		if fullOnly && len(s.queue) < s.Config.BatchSize && atomic.LoadInt64(&s.bytes) < int64(s.Config.BatchBytes) {
+			return first
+		}
+		batch := s.take()
+		if len(batch) == 0 {
+			break
+		}
+		if err := s.deliver(ctx, batch); err != nil && first == nil {
+			first = err
+		}
+	}
+	if first != nil || s.buffer == nil {
+		// the logs of the disk buffer are kept, until they are sent
+		return first
+	}
+	for s.buffer.Size() > 0 {
+		batch, err := s.buffer.Read(s.Config.BatchSize, s.Config.BatchBytes)
+		if len(batch) > 0 {
+			// the records are kept in the buffer until they are sent
+			if er2 := s.send(ctx, batch); er2 != nil {
+				return er2
+			}
+			if er2 := s.buffer.Commit(); er2 != nil {
+				return er2
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// take returns the next batch from the queue, at most BatchSize logs and BatchBytes bytes, but at least one log.
+func (s *Shipper) take() [][]byte {
+	var batch [][]byte
+	size := 0
+	if s.carry != nil {
+		batch = append(batch, s.carry)
+		size += len(s.carry)
+		s.carry = nil
+	}
+	for len(batch) < s.Config.BatchSize {
+		select {
+		case data := <-s.queue:
+			atomic.AddInt64(&s.bytes, -int64(len(data)))
+			if len(batch) > 0 && size+len(data) > s.Config.BatchBytes {
+				s.carry = data
+				return batch
+			}
+			batch = append(batch, data)
+			size += len(data)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// deliver sends the batch. The batch which is not sent is written to the disk buffer.
+func (s *Shipper) deliver(ctx context.Context, batch [][]byte) error {
+	err := s.send(ctx, batch)
+	if err == nil {
+		return nil
+	}
+	if s.buffer != nil && s.buffer.Write(batch) == nil {
+		return err
+	}
+	atomic.AddInt64(&s.failed, int64(len(batch)))
+	if s.LogError != nil {
+		s.LogError(ctx, "Cannot send logs: "+err.Error(), map[string]interface{}{"size": len(batch)})
+	}
+	return err
+}
+
+// send sends the batch, and retries with exponential backoff.
+func (s *Shipper) send(ctx context.Context, batch [][]byte) error {
+	backoff := s.Config.Backoff
+	var err error
+retry:
+	for i := 0; ; i++ {
+		sendCtx, cancel := context.WithTimeout(ctx, s.Config.Timeout)
+		err = s.Sender(sendCtx, batch)
+		cancel()
+		if err == nil {
+			atomic.AddInt64(&s.sent, int64(len(batch)))
+			return nil
+		}
+		if i >= s.Config.Retries {
+			break
+		}
+		atomic.AddInt64(&s.retries, 1)
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			break retry
+		}
+		backoff *= 2
+		if backoff > s.Config.MaxBackoff {
+			backoff = s.Config.MaxBackoff
+		}
+	}
+	return err
+}
+
+func (s *Shipper) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+func (s *Shipper) Stats() Stats {
+	st := Stats{
+		Queued:  len(s.queue),
+		Sent:    atomic.LoadInt64(&s.sent),
+		Dropped: atomic.LoadInt64(&s.dropped),
+		Failed:  atomic.LoadInt64(&s.failed),
+		Retries: atomic.LoadInt64(&s.retries),
+	}
+	if s.buffer != nil {
+		st.Buffer = s.buffer.Size()
+	}
+	return st
+}
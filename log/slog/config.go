@@ -0,0 +1,87 @@
+//go:build go1.21
+
+package slog
+
+import (
+	"github.com/sirupsen/logrus"
+
+	lr "github.com/core-go/core/log"
+	lz "github.com/core-go/core/zap"
+)
+
+type Config struct {
+	Level           string        `yaml:"level" mapstructure:"level" json:"level,omitempty" gorm:"column:level" bson:"level,omitempty" dynamodbav:"level,omitempty" firestore:"level,omitempty"`
+	Output          string        `yaml:"output" mapstructure:"output" json:"output,omitempty" gorm:"column:output" bson:"output,omitempty" dynamodbav:"output,omitempty" firestore:"output,omitempty"`
+	Duration        string        `yaml:"duration" mapstructure:"duration" json:"duration,omitempty" gorm:"column:duration" bson:"duration,omitempty" dynamodbav:"duration,omitempty" firestore:"duration,omitempty"`
+	Fields          string        `yaml:"fields" mapstructure:"fields" json:"fields,omitempty" gorm:"column:fields" bson:"fields,omitempty" dynamodbav:"fields,omitempty" firestore:"fields,omitempty"`
+	FieldMap        string        `yaml:"field_map" mapstructure:"field_map" json:"fieldMap,omitempty" gorm:"column:fieldmap" bson:"fieldMap,omitempty" dynamodbav:"fieldMap,omitempty" firestore:"fieldMap,omitempty"`
+	TraceId         string        `yaml:"trace_id" mapstructure:"trace_id" json:"traceId,omitempty" gorm:"column:traceid" bson:"traceId,omitempty" dynamodbav:"traceId,omitempty" firestore:"traceId,omitempty"`
+	SpanId          string        `yaml:"span_id" mapstructure:"span_id" json:"spanId,omitempty" gorm:"column:spanid" bson:"spanId,omitempty" dynamodbav:"spanId,omitempty" firestore:"spanId,omitempty"`
+	Map             *FieldMap     `yaml:"map" mapstructure:"map" json:"map,omitempty" gorm:"column:map" bson:"map,omitempty" dynamodbav:"map,omitempty" firestore:"map,omitempty"`
+	TimestampFormat string        `yaml:"timestamp_format" mapstructure:"timestamp_format" json:"timestampFormat,omitempty" gorm:"column:timestampformat" bson:"timestampFormat,omitempty" dynamodbav:"timestampFormat,omitempty" firestore:"timestampFormat,omitempty"`
+	AddSource       bool          `yaml:"add_source" mapstructure:"add_source" json:"addSource,omitempty" gorm:"column:addsource" bson:"addSource,omitempty" dynamodbav:"addSource,omitempty" firestore:"addSource,omitempty"`
+	MaxSize         lz.SizeOfFile `yaml:"max_file_size" mapstructure:"max_file_size" json:"max_file_size,omitempty" gorm:"column:max_file_size" bson:"max_file_size,omitempty" dynamodbav:"max_file_size,omitempty" firestore:"max_file_size,omitempty"`
+}
+
+type FieldMap struct {
+	Time   string `yaml:"time" mapstructure:"time" json:"time,omitempty" gorm:"column:time" bson:"time,omitempty" dynamodbav:"time,omitempty" firestore:"time,omitempty"`
+	Level  string `yaml:"level" mapstructure:"level" json:"level,omitempty" gorm:"column:level" bson:"level,omitempty" dynamodbav:"level,omitempty" firestore:"level,omitempty"`
+	Msg    string `yaml:"msg" mapstructure:"msg" json:"msg,omitempty" gorm:"column:msg" bson:"msg,omitempty" dynamodbav:"msg,omitempty" firestore:"msg,omitempty"`
+	Source string `yaml:"source" mapstructure:"source" json:"source,omitempty" gorm:"column:source" bson:"source,omitempty" dynamodbav:"source,omitempty" firestore:"source,omitempty"`
+}
+
+type FieldConfig struct {
+	FieldMap string    `yaml:"field_map" mapstructure:"field_map" json:"fieldMap,omitempty" gorm:"column:fieldmap" bson:"fieldMap,omitempty" dynamodbav:"fieldMap,omitempty" firestore:"fieldMap,omitempty"`
+	TraceId  string    `yaml:"trace_id" mapstructure:"trace_id" json:"traceId,omitempty" gorm:"column:traceid" bson:"traceId,omitempty" dynamodbav:"traceId,omitempty" firestore:"traceId,omitempty"`
+	SpanId   string    `yaml:"span_id" mapstructure:"span_id" json:"spanId,omitempty" gorm:"column:spanid" bson:"spanId,omitempty" dynamodbav:"spanId,omitempty" firestore:"spanId,omitempty"`
+	Duration string    `yaml:"duration" mapstructure:"duration" json:"duration,omitempty" gorm:"column:duration" bson:"duration,omitempty" dynamodbav:"duration,omitempty" firestore:"duration,omitempty"`
+	Fields   *[]string `yaml:"fields" mapstructure:"fields" json:"fields,omitempty" gorm:"column:fields" bson:"fields,omitempty" dynamodbav:"fields,omitempty" firestore:"fields,omitempty"`
+}
+
+// FromLogConfig maps the config of the logrus package, so that the slog handler writes the same keys.
+func FromLogConfig(c lr.Config) Config {
+	conf := Config{
+		Level:           c.Level,
+		Output:          c.Output,
+		Duration:        c.Duration,
+		Fields:          c.Fields,
+		FieldMap:        c.FieldMap,
+		TraceId:         c.TraceId,
+		SpanId:          c.SpanId,
+		TimestampFormat: c.TimestampFormat,
+	}
+	if c.Map != nil {
+		m := *c.Map
+		conf.Map = &FieldMap{
+			Time:   m[logrus.FieldKeyTime],
+			Level:  m[logrus.FieldKeyLevel],
+			Msg:    m[logrus.FieldKeyMsg],
+			Source: m[logrus.FieldKeyFile],
+		}
+	}
+	return conf
+}
+
+// FromZapConfig maps the config of the zap package, so that the slog handler writes the same keys.
+func FromZapConfig(c lz.Config) Config {
+	conf := Config{
+		Level:           c.Level,
+		Output:          c.Output,
+		Duration:        c.Duration,
+		Fields:          c.Fields,
+		FieldMap:        c.FieldMap,
+		TraceId:         c.TraceId,
+		SpanId:          c.SpanId,
+		TimestampFormat: "2006-01-02T15:04:05.000Z0700",
+		MaxSize:         c.MaxSize,
+	}
+	if c.Map != nil {
+		conf.Map = &FieldMap{
+			Time:   c.Map.Time,
+			Level:  c.Map.Level,
+			Msg:    c.Map.Msg,
+			Source: c.Map.Caller,
+		}
+	}
+	return conf
+}
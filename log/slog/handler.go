@@ -0,0 +1,287 @@
+//go:build go1.21
+
+package slog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	lr "github.com/core-go/core/log"
+	"github.com/core-go/core/log/level"
+	"github.com/core-go/core/trace"
+	lz "github.com/core-go/core/zap"
+)
+
+const (
+	LevelTrace = slog.Level(-8)
+	LevelDebug = slog.LevelDebug
+	LevelInfo  = slog.LevelInfo
+	LevelWarn  = slog.LevelWarn
+	LevelError = slog.LevelError
+	LevelFatal = slog.Level(12)
+	LevelPanic = slog.Level(16)
+)
+
+// Handler writes the records as JSON, with the keys of Config.Map, and adds the fields of the context like the log and zap packages.
+// It implements level.Leveler, so that its level can be changed at runtime.
+// The fields of the context are always added at the top level, so the handler keeps the root handler and the groups and attributes of WithGroup and WithAttrs, to build the record in a group.
+type Handler struct {
+	handler     slog.Handler
+	root        slog.Handler
+	scopes      []scope
+	level       *slog.LevelVar
+	FieldConfig FieldConfig
+	close       func() error
+}
+type scope struct {
+	group string
+	attrs []slog.Attr
+}
+
+func NewHandler(c Config, opts ...func(logLocation string, rotationTime time.Duration, maxSize int64) (io.Writer, func() error)) (*Handler, error) {
+	var getWriter func(logLocation string, rotationTime time.Duration, maxSize int64) (io.Writer, func() error)
+	if len(opts) > 0 && opts[0] != nil {
+		getWriter = opts[0]
+	}
+	lv := new(slog.LevelVar)
+	if len(c.Level) > 0 {
+		parsed, err := ParseLevel(c.Level)
+		if err != nil {
+			return nil, err
+		}
+		lv.Set(parsed)
+	}
+	var w io.Writer = os.Stderr
+	var close func() error
+	if len(c.Output) > 0 && getWriter != nil {
+		err := lz.CreatePath(c.Output)
+		if err != nil {
+			return nil, err
+		}
+		byteSizeLog, err := c.MaxSize.GetByteSize()
+		if err != nil {
+			byteSizeLog = 0
+		}
+		fw, closeWriter := getWriter(c.Output, 24*time.Hour, byteSizeLog)
+		w = io.MultiWriter(os.Stderr, fw)
+		close = closeWriter
+	}
+	options := &slog.HandlerOptions{AddSource: c.AddSource, Level: LevelTrace, ReplaceAttr: NewReplaceAttr(c)}
+	handler := slog.NewJSONHandler(w, options)
+	return &Handler{handler: handler, root: handler, level: lv, FieldConfig: NewFieldConfig(c), close: close}, nil
+}
+
+// NewHandlerWithLogConfig builds the handler from the config of the logrus package, with the same writer function of log.Initialize.
+func NewHandlerWithLogConfig(c lr.Config, getWriter func(logLocation string, rotationTime time.Duration) (io.Writer, func() error)) (*Handler, error) {
+	if getWriter == nil {
+		return NewHandler(FromLogConfig(c))
+	}
+	return NewHandler(FromLogConfig(c), func(logLocation string, rotationTime time.Duration, maxSize int64) (io.Writer, func() error) {
+		return getWriter(logLocation, rotationTime)
+	})
+}
+
+// NewHandlerWithZapConfig builds the handler from the config of the zap package, with the same writer function of log.InitializeWithWriter.
+func NewHandlerWithZapConfig(c lz.Config, getWriter func(logLocation string, rotationTime time.Duration, maxSize int64) (io.Writer, func() error)) (*Handler, error) {
+	return NewHandler(FromZapConfig(c), getWriter)
+}
+
+func NewFieldConfig(c Config) FieldConfig {
+	fc := FieldConfig{FieldMap: c.FieldMap, Duration: c.Duration, TraceId: c.TraceId, SpanId: c.SpanId}
+	if len(fc.Duration) == 0 {
+		fc.Duration = "duration"
+	}
+	if len(c.Fields) > 0 {
+		fields := strings.Split(c.Fields, ",")
+		fc.Fields = &fields
+	}
+	return fc
+}
+
+// NewReplaceAttr renames the built-in keys by Config.Map, formats the time by Config.TimestampFormat and writes the levels in lower case.
+func NewReplaceAttr(c Config) func(groups []string, a slog.Attr) slog.Attr {
+	m := FieldMap{}
+	if c.Map != nil {
+		m = *c.Map
+	}
+	timestampFormat := c.TimestampFormat
+	if len(timestampFormat) == 0 {
+		timestampFormat = "2006-01-02T15:04:05.000Z0700"
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return a
+		}
+		switch a.Key {
+		case slog.TimeKey:
+			if t, ok := a.Value.Any().(time.Time); ok {
+				a.Value = slog.StringValue(t.Format(timestampFormat))
+			}
+			if len(m.Time) > 0 {
+				a.Key = m.Time
+			}
+		case slog.LevelKey:
+			if lv, ok := a.Value.Any().(slog.Level); ok {
+				a.Value = slog.StringValue(LevelString(lv))
+			}
+			if len(m.Level) > 0 {
+				a.Key = m.Level
+			}
+		case slog.MessageKey:
+			if len(m.Msg) > 0 {
+				a.Key = m.Msg
+			}
+		case slog.SourceKey:
+			if len(m.Source) > 0 {
+				a.Key = m.Source
+			}
+		}
+		return a
+	}
+}
+
+func (h *Handler) Enabled(ctx context.Context, lv slog.Level) bool {
+	if lv >= h.level.Level() {
+		return true
+	}
+	return lv >= LevelDebug && level.IsDebug(ctx)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := h.AppendAttrs(ctx, nil)
+	if len(attrs) == 0 {
+		return h.handler.Handle(ctx, r)
+	}
+	if !h.grouped() {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+		return h.handler.Handle(ctx, r)
+	}
+	handler := h.root.WithAttrs(attrs)
+	for _, s := range h.scopes {
+		if len(s.group) > 0 {
+			handler = handler.WithGroup(s.group)
+		} else {
+			handler = handler.WithAttrs(s.attrs)
+		}
+	}
+	return handler.Handle(ctx, r)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(h.handler.WithAttrs(attrs), scope{attrs: attrs})
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+	return h.with(h.handler.WithGroup(name), scope{group: name})
+}
+func (h *Handler) with(handler slog.Handler, s scope) *Handler {
+	scopes := make([]scope, len(h.scopes), len(h.scopes)+1)
+	copy(scopes, h.scopes)
+	return &Handler{handler: handler, root: h.root, scopes: append(scopes, s), level: h.level, FieldConfig: h.FieldConfig, close: h.close}
+}
+func (h *Handler) grouped() bool {
+	for _, s := range h.scopes {
+		if len(s.group) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendAttrs adds the fields of the context: the map of FieldConfig.FieldMap, the string values of FieldConfig.Fields, and the trace id and span id.
+func (h *Handler) AppendAttrs(ctx context.Context, attrs []slog.Attr) []slog.Attr {
+	if ctx == nil {
+		return attrs
+	}
+	fc := h.FieldConfig
+	if len(fc.FieldMap) > 0 {
+		switch logFields := ctx.Value(fc.FieldMap).(type) {
+		case map[string]interface{}:
+			for k, v := range logFields {
+				attrs = append(attrs, slog.Any(k, v))
+			}
+		case map[string]string:
+			for k, v := range logFields {
+				attrs = append(attrs, slog.String(k, v))
+			}
+		}
+	}
+	if fc.Fields != nil {
+		for _, k := range *fc.Fields {
+			if v, ok := ctx.Value(k).(string); ok && len(v) > 0 {
+				attrs = append(attrs, slog.String(k, v))
+			}
+		}
+	}
+	if len(fc.TraceId) > 0 || len(fc.SpanId) > 0 {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			if len(fc.TraceId) > 0 {
+				attrs = append(attrs, slog.String(fc.TraceId, sc.TraceID.String()))
+			}
+			if len(fc.SpanId) > 0 {
+				attrs = append(attrs, slog.String(fc.SpanId, sc.SpanID.String()))
+			}
+		}
+	}
+	return attrs
+}
+
+func (h *Handler) GetLevel() string {
+	return LevelString(h.level.Level())
+}
+
+func (h *Handler) SetLevel(lv string) error {
+	parsed, err := ParseLevel(lv)
+	if err != nil {
+		return err
+	}
+	h.level.Set(parsed)
+	return nil
+}
+
+// Close closes the writer of Config.Output.
+func (h *Handler) Close() error {
+	if h.close != nil {
+		return h.close()
+	}
+	return nil
+}
+
+func ParseLevel(lv string) (slog.Level, error) {
+	switch strings.ToLower(lv) {
+	case "trace":
+		return LevelTrace, nil
+	case "fatal":
+		return LevelFatal, nil
+	case "panic":
+		return LevelPanic, nil
+	case "warning":
+		return LevelWarn, nil
+	}
+	var l slog.Level
+	err := l.UnmarshalText([]byte(lv))
+	return l, err
+}
+
+func LevelString(lv slog.Level) string {
+	switch lv {
+	case LevelTrace:
+		return "trace"
+	case LevelFatal:
+		return "fatal"
+	case LevelPanic:
+		return "panic"
+	}
+	return strings.ToLower(lv.String())
+}
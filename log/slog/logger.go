@@ -0,0 +1,175 @@
+//go:build go1.21
+
+package slog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"runtime"
+	"sort"
+	"time"
+)
+
+var logger *slog.Logger
+var handler *Handler
+
+// Initialize builds the handler, and sets the logger as the default logger of slog and of the log functions of this package.
+func Initialize(c Config, opts ...func(logLocation string, rotationTime time.Duration, maxSize int64) (io.Writer, func() error)) (*slog.Logger, error) {
+	h, err := NewHandler(c, opts...)
+	if err != nil {
+		return nil, err
+	}
+	l := slog.New(h)
+	slog.SetDefault(l)
+	logger = l
+	handler = h
+	return l, nil
+}
+
+func Logger() *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
+
+// GetHandler returns the handler of Initialize, to register it to level.Controller, or to close it.
+func GetHandler() *Handler {
+	return handler
+}
+
+// Fields adapts the logger to func(ctx, msg, fields), which is used by the middleware, the clients and the handlers of core.
+func Fields(l *slog.Logger, lv slog.Level) func(context.Context, string, map[string]interface{}) {
+	return func(ctx context.Context, msg string, fields map[string]interface{}) {
+		log(ctx, 3, l, lv, msg, fields)
+	}
+}
+
+// Opts adapts the logger to func(ctx, msg, ...fields).
+func Opts(l *slog.Logger, lv slog.Level) func(context.Context, string, ...map[string]interface{}) {
+	return func(ctx context.Context, msg string, opts ...map[string]interface{}) {
+		if len(opts) > 0 {
+			log(ctx, 3, l, lv, msg, opts[0])
+		} else {
+			log(ctx, 3, l, lv, msg, nil)
+		}
+	}
+}
+
+// Msg adapts the logger to func(ctx, msg).
+func Msg(l *slog.Logger, lv slog.Level) func(context.Context, string) {
+	return func(ctx context.Context, msg string) {
+		log(ctx, 3, l, lv, msg, nil)
+	}
+}
+
+func TraceFields(ctx context.Context, msg string, fields map[string]interface{}) {
+	log(ctx, 3, nil, LevelTrace, msg, fields)
+}
+func DebugFields(ctx context.Context, msg string, fields map[string]interface{}) {
+	log(ctx, 3, nil, LevelDebug, msg, fields)
+}
+func InfoFields(ctx context.Context, msg string, fields map[string]interface{}) {
+	log(ctx, 3, nil, LevelInfo, msg, fields)
+}
+func WarnFields(ctx context.Context, msg string, fields map[string]interface{}) {
+	log(ctx, 3, nil, LevelWarn, msg, fields)
+}
+func ErrorFields(ctx context.Context, msg string, fields map[string]interface{}) {
+	log(ctx, 3, nil, LevelError, msg, fields)
+}
+
+func LogTrace(ctx context.Context, msg string, opts ...map[string]interface{}) {
+	log(ctx, 3, nil, LevelTrace, msg, first(opts))
+}
+func LogDebug(ctx context.Context, msg string, opts ...map[string]interface{}) {
+	log(ctx, 3, nil, LevelDebug, msg, first(opts))
+}
+func LogInfo(ctx context.Context, msg string, opts ...map[string]interface{}) {
+	log(ctx, 3, nil, LevelInfo, msg, first(opts))
+}
+func LogWarn(ctx context.Context, msg string, opts ...map[string]interface{}) {
+	log(ctx, 3, nil, LevelWarn, msg, first(opts))
+}
+func LogError(ctx context.Context, msg string, opts ...map[string]interface{}) {
+	log(ctx, 3, nil, LevelError, msg, first(opts))
+}
+
+func TraceMsg(ctx context.Context, msg string) {
+	log(ctx, 3, nil, LevelTrace, msg, nil)
+}
+func DebugMsg(ctx context.Context, msg string) {
+	log(ctx, 3, nil, LevelDebug, msg, nil)
+}
+func InfoMsg(ctx context.Context, msg string) {
+	log(ctx, 3, nil, LevelInfo, msg, nil)
+}
+func WarnMsg(ctx context.Context, msg string) {
+	log(ctx, 3, nil, LevelWarn, msg, nil)
+}
+func ErrorMsg(ctx context.Context, msg string) {
+	log(ctx, 3, nil, LevelError, msg, nil)
+}
+
+func DebugDuration(ctx context.Context, start time.Time, msg string, opts ...map[string]interface{}) {
+	logDuration(ctx, LevelDebug, start, msg, opts...)
+}
+func InfoDuration(ctx context.Context, start time.Time, msg string, opts ...map[string]interface{}) {
+	logDuration(ctx, LevelInfo, start, msg, opts...)
+}
+func LogDuration(ctx context.Context, lv slog.Level, start time.Time, msg string, opts ...map[string]interface{}) {
+	logDuration(ctx, lv, start, msg, opts...)
+}
+func logDuration(ctx context.Context, lv slog.Level, start time.Time, msg string, opts ...map[string]interface{}) {
+	l := Logger()
+	if !l.Enabled(ctx, lv) {
+		return
+	}
+	key := "duration"
+	if h, ok := l.Handler().(*Handler); ok {
+		key = h.FieldConfig.Duration
+	}
+	fields := make(map[string]interface{})
+	if len(opts) > 0 {
+		for k, v := range opts[0] {
+			fields[k] = v
+		}
+	}
+	fields[key] = time.Since(start).Milliseconds()
+	log(ctx, 4, l, lv, msg, fields)
+}
+
+func first(opts []map[string]interface{}) map[string]interface{} {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return nil
+}
+
+// log writes the record with the caller of the exported function as source. skip is the number of frames to skip for runtime.Callers, 3 if the exported function calls log directly.
+func log(ctx context.Context, skip int, l *slog.Logger, lv slog.Level, msg string, fields map[string]interface{}) {
+	if l == nil {
+		l = Logger()
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.Enabled(ctx, lv) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(skip, pcs[:])
+	r := slog.NewRecord(time.Now(), lv, msg, pcs[0])
+	if len(fields) > 0 {
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			r.AddAttrs(slog.Any(k, fields[k]))
+		}
+	}
+	_ = l.Handler().Handle(ctx, r)
+}
@@ -0,0 +1,35 @@
+package mask
+
+const (
+	StrategyFull    = "full"
+	StrategyPartial = "partial"
+	StrategyMargin  = "margin"
+	StrategyHash    = "hash"
+	StrategyDrop    = "drop"
+
+	DetectorCard       = "card"
+	DetectorEmail      = "email"
+	DetectorNationalId = "national_id"
+)
+
+// Config of the masker. Key is the HMAC key of the strategy "hash", and is required if a rule uses it.
+type Config struct {
+	Mask  string `yaml:"mask" mapstructure:"mask" json:"mask,omitempty" gorm:"column:mask" bson:"mask,omitempty" dynamodbav:"mask,omitempty" firestore:"mask,omitempty"`
+	Key   string `yaml:"key" mapstructure:"key" json:"key,omitempty" gorm:"column:key" bson:"key,omitempty" dynamodbav:"key,omitempty" firestore:"key,omitempty"`
+	Rules []Rule `yaml:"rules" mapstructure:"rules" json:"rules,omitempty" gorm:"column:rules" bson:"rules,omitempty" dynamodbav:"rules,omitempty" firestore:"rules,omitempty"`
+}
+
+// Rule masks the values of Paths in the bodies, of Headers and of Queries by Strategy.
+// A rule with Detector or Pattern masks the matched parts of all string values, headers and query values.
+// Paths are like JSONPath: "password", "user.cards[*].number", "items[0].ssn", "$..email" (any depth).
+type Rule struct {
+	Paths    []string `yaml:"paths" mapstructure:"paths" json:"paths,omitempty" gorm:"column:paths" bson:"paths,omitempty" dynamodbav:"paths,omitempty" firestore:"paths,omitempty"`
+	Headers  []string `yaml:"headers" mapstructure:"headers" json:"headers,omitempty" gorm:"column:headers" bson:"headers,omitempty" dynamodbav:"headers,omitempty" firestore:"headers,omitempty"`
+	Queries  []string `yaml:"queries" mapstructure:"queries" json:"queries,omitempty" gorm:"column:queries" bson:"queries,omitempty" dynamodbav:"queries,omitempty" firestore:"queries,omitempty"`
+	Detector string   `yaml:"detector" mapstructure:"detector" json:"detector,omitempty" gorm:"column:detector" bson:"detector,omitempty" dynamodbav:"detector,omitempty" firestore:"detector,omitempty"`
+	Pattern  string   `yaml:"pattern" mapstructure:"pattern" json:"pattern,omitempty" gorm:"column:pattern" bson:"pattern,omitempty" dynamodbav:"pattern,omitempty" firestore:"pattern,omitempty"`
+	Strategy string   `yaml:"strategy" mapstructure:"strategy" json:"strategy,omitempty" gorm:"column:strategy" bson:"strategy,omitempty" dynamodbav:"strategy,omitempty" firestore:"strategy,omitempty"`
+	Start    int      `yaml:"start" mapstructure:"start" json:"start,omitempty" gorm:"column:start" bson:"start,omitempty" dynamodbav:"start,omitempty" firestore:"start,omitempty"`
+	End      int      `yaml:"end" mapstructure:"end" json:"end,omitempty" gorm:"column:end" bson:"end,omitempty" dynamodbav:"end,omitempty" firestore:"end,omitempty"`
+	Mask     string   `yaml:"mask" mapstructure:"mask" json:"mask,omitempty" gorm:"column:mask" bson:"mask,omitempty" dynamodbav:"mask,omitempty" firestore:"mask,omitempty"`
+}
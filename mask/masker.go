@@ -0,0 +1,364 @@
+package mask
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	cs "github.com/core-go/core/strings"
+)
+
+var detectors = map[string]string{
+	DetectorCard:  `\b(?:\d[ -]?){12,18}\d\b`,
+	DetectorEmail: `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`,
+	// the format of US social security numbers, use Pattern for the national ids of other countries
+	DetectorNationalId: `\b\d{3}-\d{2}-\d{4}\b`,
+}
+
+type rule struct {
+	paths    [][]step
+	fields   []string
+	field    *regexp.Regexp
+	headers  []string
+	queries  []string
+	detector *regexp.Regexp
+	luhn     bool
+	strategy string
+	start    int
+	end      int
+	mask     string
+	key      []byte
+}
+
+type Masker struct {
+	rules     []*rule
+	detectors []*rule
+}
+
+func NewMasker(c Config) (*Masker, error) {
+	m := &Masker{}
+	for i, r := range c.Rules {
+		x := &rule{headers: r.Headers, queries: r.Queries, strategy: strings.ToLower(r.Strategy), start: r.Start, end: r.End, mask: r.Mask, key: []byte(c.Key)}
+		if len(x.strategy) == 0 {
+			x.strategy = StrategyFull
+		}
+		switch x.strategy {
+		case StrategyFull, StrategyPartial, StrategyMargin, StrategyHash, StrategyDrop:
+		default:
+			return nil, fmt.Errorf("rule %d: invalid strategy '%s'", i, r.Strategy)
+		}
+		if x.strategy == StrategyHash && len(x.key) == 0 {
+			return nil, fmt.Errorf("rule %d: key is required for strategy '%s'", i, StrategyHash)
+		}
+		if len(x.mask) == 0 {
+			x.mask = c.Mask
+		}
+		if len(x.mask) == 0 {
+			x.mask = "*"
+		}
+		for _, p := range r.Paths {
+			steps, err := parsePath(p)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i, err)
+			}
+			x.paths = append(x.paths, steps)
+			if last := steps[len(steps)-1]; last.kind == stepKey {
+				x.fields = append(x.fields, last.key)
+			}
+		}
+		if len(x.fields) > 0 {
+			names := make([]string, len(x.fields))
+			for j, f := range x.fields {
+				names[j] = regexp.QuoteMeta(f)
+			}
+			// the value of the field in a JSON body, which cannot be decoded, such as a truncated body
+			x.field = regexp.MustCompile(`("(?:` + strings.Join(names, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+		}
+		pattern := r.Pattern
+		if len(r.Detector) > 0 {
+			p, ok := detectors[r.Detector]
+			if !ok {
+				return nil, fmt.Errorf("rule %d: invalid detector '%s'", i, r.Detector)
+			}
+			pattern = p
+			x.luhn = r.Detector == DetectorCard
+		}
+		if len(pattern) > 0 {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i, err)
+			}
+			x.detector = re
+			m.detectors = append(m.detectors, x)
+		}
+		m.rules = append(m.rules, x)
+	}
+	return m, nil
+}
+
+func MustNewMasker(c Config) *Masker {
+	m, err := NewMasker(c)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MaskValue masks the decoded JSON value by the paths and the detectors. The maps are changed in place.
+func (m *Masker) MaskValue(v interface{}) interface{} {
+	if m == nil {
+		return v
+	}
+	for _, r := range m.rules {
+		for _, steps := range r.paths {
+			v = walk(v, steps, r)
+		}
+	}
+	if len(m.detectors) > 0 {
+		v = m.detect(v)
+	}
+	return v
+}
+
+// MaskMap can be used as maskRequest and maskResponse of MaskLogger.
+func (m *Masker) MaskMap(v map[string]interface{}) {
+	m.MaskValue(v)
+}
+
+// Mask masks s by the rules with the top level path fieldName, then by the detectors. It can be used as the mask function of the middleware.
+func (m *Masker) Mask(fieldName, s string) string {
+	if m == nil {
+		return s
+	}
+	for _, r := range m.rules {
+		for _, steps := range r.paths {
+			if len(steps) == 1 && steps[0].kind == stepKey && steps[0].key == fieldName {
+				return r.maskString(s)
+			}
+		}
+	}
+	return m.MaskText(s)
+}
+
+// MaskText masks the parts of s, which are matched by the detectors.
+func (m *Masker) MaskText(s string) string {
+	if m == nil {
+		return s
+	}
+	for _, r := range m.detectors {
+		s = r.detector.ReplaceAllStringFunc(s, func(x string) string {
+			if r.luhn && !isLuhn(x) {
+				return x
+			}
+			return r.maskString(x)
+		})
+	}
+	return s
+}
+
+// MaskBody masks a JSON body by the paths and the detectors, a dump of http response by the header rules too, a form body by the fields of the paths and the query rules, and other bodies by the detectors.
+// If a JSON body cannot be decoded, such as a truncated body, the values of the fields of the paths are masked as text.
+func (m *Masker) MaskBody(s string) string {
+	if m == nil || len(s) == 0 {
+		return s
+	}
+	t := strings.TrimSpace(s)
+	if strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[") {
+		d := json.NewDecoder(strings.NewReader(t))
+		d.UseNumber()
+		var v interface{}
+		if err := d.Decode(&v); err == nil {
+			buf := new(bytes.Buffer)
+			e := json.NewEncoder(buf)
+			e.SetEscapeHTML(false)
+			if err := e.Encode(m.MaskValue(v)); err == nil {
+				return strings.TrimSuffix(buf.String(), "\n")
+			}
+		}
+		return m.MaskText(m.maskFields(s))
+	} else if strings.HasPrefix(t, "HTTP/") {
+		return m.MaskDump(s)
+	} else if isForm(t) {
+		return m.MaskForm(t)
+	}
+	return m.MaskText(s)
+}
+
+// maskFields masks the values of the fields of the paths in a JSON body, which cannot be decoded.
+func (m *Masker) maskFields(s string) string {
+	for _, r := range m.rules {
+		if r.field == nil {
+			continue
+		}
+		s = r.field.ReplaceAllStringFunc(s, func(x string) string {
+			sub := r.field.FindStringSubmatch(x)
+			v := sub[2]
+			if strings.HasPrefix(v, "\"") {
+				v = strings.TrimSuffix(v[1:], "\"")
+			}
+			return sub[1] + "\"" + r.maskString(v) + "\""
+		})
+	}
+	return s
+}
+
+func isForm(s string) bool {
+	return strings.Contains(s, "=") && !strings.ContainsAny(s, " \t\r\n")
+}
+
+// MaskDump masks the headers and the body of the dump of an http request or response.
+func (m *Masker) MaskDump(s string) string {
+	if m == nil {
+		return s
+	}
+	head, body := s, ""
+	i := strings.Index(s, "\r\n\r\n")
+	if i >= 0 {
+		head, body = s[:i], s[i+4:]
+	}
+	lines := strings.Split(head, "\r\n")
+	out := make([]string, 1, len(lines))
+	out[0] = lines[0]
+	for _, line := range lines[1:] {
+		j := strings.Index(line, ":")
+		if j <= 0 {
+			out = append(out, line)
+			continue
+		}
+		name := line[:j]
+		v, ok := m.maskHeader(name, strings.TrimSpace(line[j+1:]))
+		if ok {
+			out = append(out, name+": "+v)
+		}
+	}
+	if i < 0 {
+		return strings.Join(out, "\r\n")
+	}
+	return strings.Join(out, "\r\n") + "\r\n\r\n" + m.MaskBody(body)
+}
+
+// MaskHeader masks the value of the header. The value of a dropped header is empty.
+func (m *Masker) MaskHeader(name, value string) string {
+	v, _ := m.maskHeader(name, value)
+	return v
+}
+
+// MaskHeaders returns a copy of h, with the values masked and the dropped headers removed.
+func (m *Masker) MaskHeaders(h http.Header) http.Header {
+	if m == nil {
+		return h
+	}
+	h2 := make(http.Header, len(h))
+	for k, vs := range h {
+		for _, v := range vs {
+			if v2, ok := m.maskHeader(k, v); ok {
+				h2[k] = append(h2[k], v2)
+			}
+		}
+	}
+	return h2
+}
+
+func (m *Masker) maskHeader(name, value string) (string, bool) {
+	if m == nil {
+		return value, true
+	}
+	for _, r := range m.rules {
+		for _, h := range r.headers {
+			if strings.EqualFold(h, name) {
+				if r.strategy == StrategyDrop {
+					return "", false
+				}
+				return r.maskString(value), true
+			}
+		}
+	}
+	return m.MaskText(value), true
+}
+
+func (m *Masker) detect(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string:
+		return m.MaskText(x)
+	case json.Number:
+		s := m.MaskText(x.String())
+		if s == x.String() {
+			return x
+		}
+		return s
+	case map[string]interface{}:
+		for k, c := range x {
+			x[k] = m.detect(c)
+		}
+	case []interface{}:
+		for i, c := range x {
+			x[i] = m.detect(c)
+		}
+	}
+	return v
+}
+
+func (r *rule) maskString(s string) string {
+	switch r.strategy {
+	case StrategyPartial:
+		return cs.Mask(s, r.start, r.end, r.mask)
+	case StrategyMargin:
+		return cs.MaskMargin(s, r.start, r.end, r.mask)
+	case StrategyHash:
+		h := hmac.New(sha256.New, r.key)
+		h.Write([]byte(s))
+		return hex.EncodeToString(h.Sum(nil))
+	case StrategyDrop:
+		return ""
+	default:
+		return strings.Repeat(r.mask, len(s))
+	}
+}
+
+func (r *rule) maskValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return r.maskString(x)
+	case map[string]interface{}:
+		for k, c := range x {
+			x[k] = r.maskValue(c)
+		}
+		return x
+	case []interface{}:
+		for i, c := range x {
+			x[i] = r.maskValue(c)
+		}
+		return x
+	default:
+		return r.maskString(fmt.Sprint(x))
+	}
+}
+
+func isLuhn(s string) bool {
+	sum := 0
+	n := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
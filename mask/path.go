@@ -0,0 +1,144 @@
+package mask
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	stepKey = iota
+	stepAnyKey
+	stepIndex
+	stepAnyIndex
+)
+
+type step struct {
+	kind      int
+	key       string
+	index     int
+	recursive bool
+}
+
+// parsePath parses paths like "a.b", "a.b[*].c", "a[0]", "$.a['b']" and "$..a".
+func parsePath(path string) ([]step, error) {
+	s := strings.TrimSpace(path)
+	s = strings.TrimPrefix(s, "$")
+	var steps []step
+	recursive := false
+	i := 0
+	for i < len(s) {
+		switch s[i] {
+		case '.':
+			if i+1 < len(s) && s[i+1] == '.' {
+				recursive = true
+				i += 2
+			} else {
+				i++
+			}
+		case '[':
+			j := strings.IndexByte(s[i:], ']')
+			if j < 0 {
+				return nil, fmt.Errorf("invalid path '%s': missing ]", path)
+			}
+			v := strings.TrimSpace(s[i+1 : i+j])
+			i += j + 1
+			var st step
+			if v == "*" || len(v) == 0 {
+				st = step{kind: stepAnyIndex}
+			} else if len(v) >= 2 && (v[0] == '\'' || v[0] == '"') && v[len(v)-1] == v[0] {
+				st = step{kind: stepKey, key: v[1 : len(v)-1]}
+			} else {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid path '%s': bad index %s", path, v)
+				}
+				st = step{kind: stepIndex, index: n}
+			}
+			st.recursive = recursive
+			recursive = false
+			steps = append(steps, st)
+		default:
+			j := strings.IndexAny(s[i:], ".[")
+			if j < 0 {
+				j = len(s) - i
+			}
+			k := s[i : i+j]
+			i += j
+			st := step{kind: stepKey, key: k, recursive: recursive}
+			if k == "*" {
+				st.kind = stepAnyKey
+			}
+			recursive = false
+			steps = append(steps, st)
+		}
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("invalid path '%s'", path)
+	}
+	return steps, nil
+}
+
+// walk applies the rule to the values at the steps, and returns the value, because the arrays are rebuilt when their items are dropped.
+func walk(v interface{}, steps []step, r *rule) interface{} {
+	s := steps[0]
+	if s.recursive {
+		switch x := v.(type) {
+		case map[string]interface{}:
+			for k, c := range x {
+				x[k] = walk(c, steps, r)
+			}
+		case []interface{}:
+			for i, c := range x {
+				x[i] = walk(c, steps, r)
+			}
+		}
+		s.recursive = false
+	}
+	rest := steps[1:]
+	switch s.kind {
+	case stepKey, stepAnyKey:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		for k, c := range m {
+			if s.kind == stepKey && k != s.key {
+				continue
+			}
+			if len(rest) > 0 {
+				m[k] = walk(c, rest, r)
+			} else if r.strategy == StrategyDrop {
+				delete(m, k)
+			} else {
+				m[k] = r.maskValue(c)
+			}
+		}
+		return m
+	default:
+		a, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		if len(rest) == 0 && r.strategy == StrategyDrop {
+			if s.kind == stepAnyIndex {
+				return []interface{}{}
+			}
+			if s.index < len(a) {
+				return append(a[:s.index:s.index], a[s.index+1:]...)
+			}
+			return a
+		}
+		for i, c := range a {
+			if s.kind == stepIndex && i != s.index {
+				continue
+			}
+			if len(rest) > 0 {
+				a[i] = walk(c, rest, r)
+			} else {
+				a[i] = r.maskValue(c)
+			}
+		}
+		return a
+	}
+}
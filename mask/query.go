@@ -0,0 +1,94 @@
+package mask
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MaskURI masks the query string of the request uri or the url.
+func (m *Masker) MaskURI(uri string) string {
+	if m == nil {
+		return uri
+	}
+	i := strings.Index(uri, "?")
+	if i < 0 {
+		return uri
+	}
+	q := m.MaskQuery(uri[i+1:])
+	if len(q) == 0 {
+		return uri[:i]
+	}
+	return uri[:i+1] + q
+}
+
+// MaskQuery masks the values of the query parameters by the query rules, then by the detectors. The order of the parameters is kept.
+func (m *Masker) MaskQuery(rawQuery string) string {
+	if m == nil || len(rawQuery) == 0 {
+		return rawQuery
+	}
+	return m.maskParams(rawQuery, m.maskQuery)
+}
+
+// MaskForm masks the values of a form-urlencoded body by the fields of the paths and the query rules, then by the detectors.
+func (m *Masker) MaskForm(body string) string {
+	if m == nil || len(body) == 0 {
+		return body
+	}
+	return m.maskParams(body, m.maskFormValue)
+}
+
+func (m *Masker) maskParams(rawQuery string, mask func(name, value string) (string, bool)) string {
+	params := strings.Split(rawQuery, "&")
+	out := make([]string, 0, len(params))
+	for _, p := range params {
+		k, v, found := strings.Cut(p, "=")
+		if !found {
+			out = append(out, p)
+			continue
+		}
+		name, err := url.QueryUnescape(k)
+		if err != nil {
+			name = k
+		}
+		value, err := url.QueryUnescape(v)
+		if err != nil {
+			value = v
+		}
+		v2, ok := mask(name, value)
+		if !ok {
+			continue
+		}
+		if v2 != value {
+			v = strings.ReplaceAll(url.QueryEscape(v2), "%2A", "*")
+		}
+		out = append(out, k+"="+v)
+	}
+	return strings.Join(out, "&")
+}
+
+func (m *Masker) maskQuery(name, value string) (string, bool) {
+	for _, r := range m.rules {
+		for _, q := range r.queries {
+			if q == name {
+				if r.strategy == StrategyDrop {
+					return "", false
+				}
+				return r.maskString(value), true
+			}
+		}
+	}
+	return m.MaskText(value), true
+}
+func (m *Masker) maskFormValue(name, value string) (string, bool) {
+	for _, r := range m.rules {
+		for _, f := range r.fields {
+			if f == name {
+				if r.strategy == StrategyDrop {
+					return "", false
+				}
+				return r.maskString(value), true
+			}
+		}
+	}
+	return m.maskQuery(name, value)
+}
@@ -0,0 +1,178 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/core-go/core/cache"
+	"github.com/core-go/core/caching"
+)
+
+type CacheMetrics struct {
+	Hits   *CounterVec
+	Misses *CounterVec
+	Ratio  *CollectorFunc
+}
+
+func NewCacheMetrics(registry *Registry, options ...string) *CacheMetrics {
+	prefix := "cache"
+	if len(options) > 0 && len(options[0]) > 0 {
+		prefix = BuildName(options[0], prefix)
+	}
+	m := &CacheMetrics{
+		Hits:   NewCounterVec(prefix+"_hits_total", "Total number of cache hits.", "cache"),
+		Misses: NewCounterVec(prefix+"_misses_total", "Total number of cache misses.", "cache"),
+	}
+	m.Ratio = NewCollectorFunc(prefix+"_hit_ratio", "Ratio of cache hits to lookups.", TypeGauge, m.collectRatio)
+	if registry != nil {
+		registry.MustRegister(m.Hits, m.Misses, m.Ratio)
+	}
+	return m
+}
+
+func (m *CacheMetrics) Hit(name string) {
+	m.Hits.Inc(name)
+}
+func (m *CacheMetrics) Miss(name string) {
+	m.Misses.Inc(name)
+}
+
+func (m *CacheMetrics) HitRatio(name string) float64 {
+	hits := m.Hits.Value(name)
+	total := hits + m.Misses.Value(name)
+	if total == 0 {
+		return 0
+	}
+	return hits / total
+}
+
+func (m *CacheMetrics) collectRatio() []Sample {
+	names := make(map[string]bool)
+	for _, s := range m.Hits.Collect() {
+		names[s.Labels[0].Value] = true
+	}
+	for _, s := range m.Misses.Collect() {
+		names[s.Labels[0].Value] = true
+	}
+	samples := make([]Sample, 0, len(names))
+	for name := range names {
+		samples = append(samples, Sample{Labels: []Label{{Name: "cache", Value: name}}, Value: m.HitRatio(name)})
+	}
+	sortSamples(samples)
+	return samples
+}
+
+func (m *CacheMetrics) observe(name string, hit bool) {
+	if hit {
+		m.Hits.Inc(name)
+	} else {
+		m.Misses.Inc(name)
+	}
+}
+
+// CacheService counts the hits and misses of cache.CacheService. The other methods are delegated.
+type CacheService struct {
+	cache.CacheService
+	Name    string
+	Metrics *CacheMetrics
+}
+
+func NewCacheService(service cache.CacheService, m *CacheMetrics, options ...string) *CacheService {
+	return &CacheService{CacheService: service, Name: getName(options), Metrics: m}
+}
+
+func (c *CacheService) Get(key string) (interface{}, error) {
+	obj, err := c.CacheService.Get(key)
+	if err == nil {
+		c.Metrics.observe(c.Name, obj != nil)
+	}
+	return obj, err
+}
+
+func (c *CacheService) GetMany(keys []string) (map[string]interface{}, []string, error) {
+	found, notFound, err := c.CacheService.GetMany(keys)
+	if err == nil {
+		c.Metrics.Hits.Add(float64(len(found)), c.Name)
+		c.Metrics.Misses.Add(float64(len(notFound)), c.Name)
+	}
+	return found, notFound, err
+}
+
+func (c *CacheService) GetManyStrings(keys []string) (map[string]string, []string, error) {
+	found, notFound, err := c.CacheService.GetManyStrings(keys)
+	if err == nil {
+		c.Metrics.Hits.Add(float64(len(found)), c.Name)
+		c.Metrics.Misses.Add(float64(len(notFound)), c.Name)
+	}
+	return found, notFound, err
+}
+
+type ContextCacheService struct {
+	cache.ContextCacheService
+	Name    string
+	Metrics *CacheMetrics
+}
+
+func NewContextCacheService(service cache.ContextCacheService, m *CacheMetrics, options ...string) *ContextCacheService {
+	return &ContextCacheService{ContextCacheService: service, Name: getName(options), Metrics: m}
+}
+
+func (c *ContextCacheService) Get(ctx context.Context, key string) (interface{}, error) {
+	obj, err := c.ContextCacheService.Get(ctx, key)
+	if err == nil {
+		c.Metrics.observe(c.Name, obj != nil)
+	}
+	return obj, err
+}
+
+func (c *ContextCacheService) GetMany(ctx context.Context, keys []string) (map[string]interface{}, []string, error) {
+	found, notFound, err := c.ContextCacheService.GetMany(ctx, keys)
+	if err == nil {
+		c.Metrics.Hits.Add(float64(len(found)), c.Name)
+		c.Metrics.Misses.Add(float64(len(notFound)), c.Name)
+	}
+	return found, notFound, err
+}
+
+func (c *ContextCacheService) GetManyStrings(ctx context.Context, keys []string) (map[string]string, []string, error) {
+	found, notFound, err := c.ContextCacheService.GetManyStrings(ctx, keys)
+	if err == nil {
+		c.Metrics.Hits.Add(float64(len(found)), c.Name)
+		c.Metrics.Misses.Add(float64(len(notFound)), c.Name)
+	}
+	return found, notFound, err
+}
+
+// CachingService counts the hits and misses of caching.CacheService, where a miss is an empty string.
+type CachingService struct {
+	caching.CacheService
+	Name    string
+	Metrics *CacheMetrics
+}
+
+func NewCachingService(service caching.CacheService, m *CacheMetrics, options ...string) *CachingService {
+	return &CachingService{CacheService: service, Name: getName(options), Metrics: m}
+}
+
+func (c *CachingService) Get(ctx context.Context, key string) (string, error) {
+	s, err := c.CacheService.Get(ctx, key)
+	if err == nil {
+		c.Metrics.observe(c.Name, len(s) > 0)
+	}
+	return s, err
+}
+
+func (c *CachingService) GetMany(ctx context.Context, keys []string) (map[string]string, []string, error) {
+	found, notFound, err := c.CacheService.GetMany(ctx, keys)
+	if err == nil {
+		c.Metrics.Hits.Add(float64(len(found)), c.Name)
+		c.Metrics.Misses.Add(float64(len(notFound)), c.Name)
+	}
+	return found, notFound, err
+}
+
+func getName(options []string) string {
+	if len(options) > 0 && len(options[0]) > 0 {
+		return options[0]
+	}
+	return "default"
+}
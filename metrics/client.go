@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ClientMetrics keeps the metrics of the outbound requests, per host, method and status. Transport errors have the status "error".
+type ClientMetrics struct {
+	Requests *CounterVec
+	Duration *HistogramVec
+}
+
+func NewClientMetrics(registry *Registry, namespace string, buckets []float64, options ...string) *ClientMetrics {
+	subsystem := "http_client"
+	if len(options) > 0 && len(options[0]) > 0 {
+		subsystem = options[0]
+	}
+	prefix := BuildName(namespace, subsystem)
+	m := &ClientMetrics{
+		Requests: NewCounterVec(prefix+"_requests_total", "Total number of outbound HTTP requests.", "host", "method", "status"),
+		Duration: NewHistogramVec(prefix+"_request_duration_seconds", "Duration of outbound HTTP requests in seconds.", buckets, "host", "method"),
+	}
+	if registry != nil {
+		registry.MustRegister(m.Requests, m.Duration)
+	}
+	return m
+}
+
+func (m *ClientMetrics) Observe(host string, method string, status string, duration time.Duration) {
+	method = Method(method)
+	m.Requests.Inc(host, method, status)
+	m.Duration.Observe(duration.Seconds(), host, method)
+}
+
+type Transport struct {
+	Next    http.RoundTripper
+	Metrics *ClientMetrics
+}
+
+func NewTransport(next http.RoundTripper, m *ClientMetrics) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{Next: next, Metrics: m}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.Next.RoundTrip(req)
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(res.StatusCode)
+	}
+	t.Metrics.Observe(req.URL.Host, req.Method, status, time.Since(start))
+	return res, err
+}
+
+// InstrumentClient wraps the transport of the client, so that the functions of the client package, which take *http.Client, are measured.
+func InstrumentClient(client *http.Client, m *ClientMetrics) *http.Client {
+	if _, ok := client.Transport.(*Transport); ok {
+		return client
+	}
+	client.Transport = NewTransport(client.Transport, m)
+	return client
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"database/sql"
+	"sort"
+	"sync"
+)
+
+// DBStats exposes sql.DB.Stats() of the registered databases at scrape time.
+type DBStats struct {
+	mu  sync.RWMutex
+	dbs map[string]*sql.DB
+}
+
+func NewDBStats(registry *Registry, options ...string) *DBStats {
+	prefix := "sql"
+	if len(options) > 0 && len(options[0]) > 0 {
+		prefix = BuildName(options[0], prefix)
+	}
+	s := &DBStats{dbs: make(map[string]*sql.DB)}
+	if registry != nil {
+		registry.MustRegister(
+			s.collector(prefix+"_max_open_connections", "Maximum number of open connections.", TypeGauge, func(st sql.DBStats) float64 { return float64(st.MaxOpenConnections) }),
+			s.collector(prefix+"_open_connections", "Number of open connections.", TypeGauge, func(st sql.DBStats) float64 { return float64(st.OpenConnections) }),
+			s.collector(prefix+"_in_use_connections", "Number of connections in use.", TypeGauge, func(st sql.DBStats) float64 { return float64(st.InUse) }),
+			s.collector(prefix+"_idle_connections", "Number of idle connections.", TypeGauge, func(st sql.DBStats) float64 { return float64(st.Idle) }),
+			s.collector(prefix+"_wait_count_total", "Total number of connections waited for.", TypeCounter, func(st sql.DBStats) float64 { return float64(st.WaitCount) }),
+			s.collector(prefix+"_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", TypeCounter, func(st sql.DBStats) float64 { return st.WaitDuration.Seconds() }),
+			s.collector(prefix+"_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.", TypeCounter, func(st sql.DBStats) float64 { return float64(st.MaxIdleClosed) }),
+			s.collector(prefix+"_max_idle_time_closed_total", "Total number of connections closed due to SetConnMaxIdleTime.", TypeCounter, func(st sql.DBStats) float64 { return float64(st.MaxIdleTimeClosed) }),
+			s.collector(prefix+"_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.", TypeCounter, func(st sql.DBStats) float64 { return float64(st.MaxLifetimeClosed) }),
+		)
+	}
+	return s
+}
+
+func (s *DBStats) Add(name string, db *sql.DB) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dbs[name] = db
+}
+
+func (s *DBStats) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.dbs, name)
+}
+
+func (s *DBStats) collector(name string, help string, typ string, value func(sql.DBStats) float64) Collector {
+	return NewCollectorFunc(name, help, typ, func() []Sample {
+		s.mu.RLock()
+		names := make([]string, 0, len(s.dbs))
+		for n := range s.dbs {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		samples := make([]Sample, 0, len(names))
+		for _, n := range names {
+			samples = append(samples, Sample{Labels: []Label{{Name: "db", Value: n}}, Value: value(s.dbs[n].Stats())})
+		}
+		s.mu.RUnlock()
+		return samples
+	})
+}
@@ -0,0 +1,28 @@
+package echo
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/core-go/core/metrics"
+)
+
+type Handler struct {
+	Registry *metrics.Registry
+}
+
+func NewHandler(registries ...*metrics.Registry) *Handler {
+	registry := metrics.DefaultRegistry
+	if len(registries) > 0 && registries[0] != nil {
+		registry = registries[0]
+	}
+	return &Handler{Registry: registry}
+}
+
+func (h *Handler) Metrics(ctx echo.Context) error {
+	res := ctx.Response()
+	res.Header().Set("Content-Type", metrics.ContentType)
+	res.WriteHeader(http.StatusOK)
+	return h.Registry.Write(ctx.Request().Context(), res)
+}
@@ -0,0 +1,27 @@
+package gin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/core-go/core/metrics"
+)
+
+type Handler struct {
+	Registry *metrics.Registry
+}
+
+func NewHandler(registries ...*metrics.Registry) *Handler {
+	registry := metrics.DefaultRegistry
+	if len(registries) > 0 && registries[0] != nil {
+		registry = registries[0]
+	}
+	return &Handler{Registry: registry}
+}
+
+func (h *Handler) Metrics(ctx *gin.Context) {
+	ctx.Writer.Header().Set("Content-Type", metrics.ContentType)
+	ctx.Writer.WriteHeader(http.StatusOK)
+	h.Registry.Write(ctx.Request.Context(), ctx.Writer)
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import "net/http"
+
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type Handler struct {
+	Registry *Registry
+}
+
+func NewHandler(registries ...*Registry) *Handler {
+	registry := DefaultRegistry
+	if len(registries) > 0 && registries[0] != nil {
+		registry = registries[0]
+	}
+	return &Handler{Registry: registry}
+}
+
+func (h *Handler) Metrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(http.StatusOK)
+	h.Registry.Write(r.Context(), w)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.Metrics(w, r)
+}
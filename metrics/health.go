@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/core-go/core/health"
+)
+
+// HealthMetrics exposes the last health result: the overall status as one gauge per status, and the status and latency of each checker.
+// If the runner is set, the checks are run once before each scrape, with the cache of the runner.
+type HealthMetrics struct {
+	Runner  *health.Runner
+	Timeout time.Duration
+	Status  *GaugeVec
+	Up      *GaugeVec
+	Latency *GaugeVec
+}
+
+func NewHealthMetrics(registry *Registry, runner *health.Runner, options ...string) *HealthMetrics {
+	prefix := "health"
+	if len(options) > 0 && len(options[0]) > 0 {
+		prefix = BuildName(options[0], prefix)
+	}
+	m := &HealthMetrics{
+		Runner:  runner,
+		Timeout: 10 * time.Second,
+		Status:  NewGaugeVec(prefix+"_status", "Overall health status, 1 for the current status.", "status"),
+		Up:      NewGaugeVec(prefix+"_check_up", "Status of the checker, 1 if it is up.", "checker"),
+		Latency: NewGaugeVec(prefix+"_check_latency_seconds", "Latency of the last check in seconds.", "checker"),
+	}
+	if registry != nil {
+		registry.MustRegister(m.Status, m.Up, m.Latency)
+		if runner != nil {
+			registry.OnCollect(m.Update)
+		}
+	}
+	return m
+}
+
+func (m *HealthMetrics) Update(ctx context.Context) {
+	if m.Runner == nil {
+		return
+	}
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+	m.Set(m.Runner.Check(ctx))
+}
+
+func (m *HealthMetrics) Set(h health.Health) {
+	for _, status := range []string{health.StatusUp, health.StatusDegraded, health.StatusDown} {
+		v := 0.0
+		if h.Status == status {
+			v = 1
+		}
+		m.Status.Set(v, status)
+	}
+	for name, sub := range h.Details {
+		up := 0.0
+		if sub.Status == health.StatusUp {
+			up = 1
+		}
+		m.Up.Set(up, name)
+		if latency, ok := sub.Data["latency"].(int64); ok {
+			m.Latency.Set(float64(latency)/1000, name)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/core-go/core/paths"
+)
+
+const (
+	RouteOther  = "other"
+	MethodOther = "other"
+)
+
+type HttpConfig struct {
+	Namespace string    `yaml:"namespace" mapstructure:"namespace" json:"namespace,omitempty" gorm:"column:namespace" bson:"namespace,omitempty" dynamodbav:"namespace,omitempty" firestore:"namespace,omitempty"`
+	Buckets   []float64 `yaml:"buckets" mapstructure:"buckets" json:"buckets,omitempty" gorm:"column:buckets" bson:"buckets,omitempty" dynamodbav:"buckets,omitempty" firestore:"buckets,omitempty"`
+	Routes    []string  `yaml:"routes" mapstructure:"routes" json:"routes,omitempty" gorm:"column:routes" bson:"routes,omitempty" dynamodbav:"routes,omitempty" firestore:"routes,omitempty"`
+	Skips     []string  `yaml:"skips" mapstructure:"skips" json:"skips,omitempty" gorm:"column:skips" bson:"skips,omitempty" dynamodbav:"skips,omitempty" firestore:"skips,omitempty"`
+}
+
+// HttpMetrics keeps the RED metrics (rate, errors, duration) of the server, per method, route template and status.
+// The route must be a template, such as /users/:id, not the raw path, to keep the number of series bounded.
+type HttpMetrics struct {
+	Config   HttpConfig
+	Requests *CounterVec
+	Errors   *CounterVec
+	Duration *HistogramVec
+	InFlight *GaugeVec
+}
+
+func NewHttpMetrics(registry *Registry, c HttpConfig, options ...string) *HttpMetrics {
+	subsystem := "http_server"
+	if len(options) > 0 && len(options[0]) > 0 {
+		subsystem = options[0]
+	}
+	prefix := BuildName(c.Namespace, subsystem)
+	m := &HttpMetrics{
+		Config:   c,
+		Requests: NewCounterVec(prefix+"_requests_total", "Total number of HTTP requests.", "method", "route", "status"),
+		Errors:   NewCounterVec(prefix+"_errors_total", "Total number of HTTP requests with a 5xx status.", "method", "route"),
+		Duration: NewHistogramVec(prefix+"_request_duration_seconds", "Duration of HTTP requests in seconds.", c.Buckets, "method", "route"),
+		InFlight: NewGaugeVec(prefix+"_requests_in_flight", "Number of HTTP requests being served."),
+	}
+	if registry != nil {
+		registry.MustRegister(m.Requests, m.Errors, m.Duration, m.InFlight)
+	}
+	return m
+}
+
+// Route returns the first configured template which matches the path, or RouteOther.
+func (m *HttpMetrics) Route(path string) string {
+	for _, route := range m.Config.Routes {
+		if paths.MatchPath(route, path) {
+			return route
+		}
+	}
+	return RouteOther
+}
+
+func (m *HttpMetrics) Skip(path string) bool {
+	for _, skip := range m.Config.Skips {
+		if paths.MatchPath(skip, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *HttpMetrics) Begin() {
+	m.InFlight.Inc()
+}
+
+func (m *HttpMetrics) End(method string, route string, status int, duration time.Duration) {
+	m.InFlight.Dec()
+	m.Observe(method, route, status, duration)
+}
+
+// Observe records the request. The non-standard methods are recorded as MethodOther, to keep the number of series bounded.
+func (m *HttpMetrics) Observe(method string, route string, status int, duration time.Duration) {
+	method = Method(method)
+	if status <= 0 {
+		status = 200
+	}
+	m.Requests.Inc(method, route, strconv.Itoa(status))
+	if status >= 500 {
+		m.Errors.Inc(method, route)
+	}
+	m.Duration.Observe(duration.Seconds(), method, route)
+}
+
+// Method returns the standard http method, or MethodOther.
+func Method(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return MethodOther
+}
+
+func BuildName(namespace string, name string) string {
+	if len(namespace) == 0 {
+		return name
+	}
+	return namespace + "_" + name
+}
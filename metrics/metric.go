@@ -0,0 +1,318 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type series struct {
+	values []string
+	bits   uint64
+}
+
+func (s *series) add(v float64) {
+	for {
+		old := atomic.LoadUint64(&s.bits)
+		n := math.Float64bits(math.Float64frombits(old) + v)
+		if atomic.CompareAndSwapUint64(&s.bits, old, n) {
+			return
+		}
+	}
+}
+func (s *series) set(v float64) {
+	atomic.StoreUint64(&s.bits, math.Float64bits(v))
+}
+func (s *series) get() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&s.bits))
+}
+
+// vec keeps one series per combination of label values. Missing label values are empty, extra values are ignored.
+type vec struct {
+	name   string
+	help   string
+	labels []string
+	mu     sync.RWMutex
+	series map[string]*series
+}
+
+func newVec(name string, help string, labels []string) vec {
+	return vec{name: name, help: help, labels: labels, series: make(map[string]*series)}
+}
+
+func (v *vec) Name() string {
+	return v.name
+}
+func (v *vec) Help() string {
+	return v.help
+}
+
+func (v *vec) get(values []string) *series {
+	values = normalize(values, len(v.labels))
+	key := strings.Join(values, "\xff")
+	v.mu.RLock()
+	s, ok := v.series[key]
+	v.mu.RUnlock()
+	if ok {
+		return s
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if s, ok = v.series[key]; ok {
+		return s
+	}
+	s = &series{values: append([]string(nil), values...)}
+	v.series[key] = s
+	return s
+}
+
+func (v *vec) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.series = make(map[string]*series)
+}
+
+func (v *vec) collect() []Sample {
+	v.mu.RLock()
+	samples := make([]Sample, 0, len(v.series))
+	for _, s := range v.series {
+		samples = append(samples, Sample{Labels: toLabels(v.labels, s.values), Value: s.get()})
+	}
+	v.mu.RUnlock()
+	sortSamples(samples)
+	return samples
+}
+
+type CounterVec struct {
+	vec
+}
+
+func NewCounterVec(name string, help string, labels ...string) *CounterVec {
+	return &CounterVec{newVec(name, help, labels)}
+}
+
+func (c *CounterVec) Type() string {
+	return TypeCounter
+}
+func (c *CounterVec) Collect() []Sample {
+	return c.collect()
+}
+
+// Add ignores negative values, because a counter only goes up.
+func (c *CounterVec) Add(v float64, values ...string) {
+	if v < 0 {
+		return
+	}
+	c.get(values).add(v)
+}
+func (c *CounterVec) Inc(values ...string) {
+	c.get(values).add(1)
+}
+func (c *CounterVec) Value(values ...string) float64 {
+	return c.get(values).get()
+}
+
+type GaugeVec struct {
+	vec
+}
+
+func NewGaugeVec(name string, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{newVec(name, help, labels)}
+}
+
+func (g *GaugeVec) Type() string {
+	return TypeGauge
+}
+func (g *GaugeVec) Collect() []Sample {
+	return g.collect()
+}
+
+func (g *GaugeVec) Set(v float64, values ...string) {
+	g.get(values).set(v)
+}
+func (g *GaugeVec) Add(v float64, values ...string) {
+	g.get(values).add(v)
+}
+func (g *GaugeVec) Inc(values ...string) {
+	g.get(values).add(1)
+}
+func (g *GaugeVec) Dec(values ...string) {
+	g.get(values).add(-1)
+}
+func (g *GaugeVec) Value(values ...string) float64 {
+	return g.get(values).get()
+}
+
+type histogram struct {
+	values []string
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	mu      sync.RWMutex
+	series  map[string]*histogram
+}
+
+// NewHistogramVec uses DefaultBuckets if buckets is empty. The buckets are sorted, +Inf is implicit.
+func NewHistogramVec(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	bs := make([]float64, 0, len(buckets))
+	for _, b := range buckets {
+		if !math.IsInf(b, 1) {
+			bs = append(bs, b)
+		}
+	}
+	sort.Float64s(bs)
+	return &HistogramVec{name: name, help: help, labels: labels, buckets: bs, series: make(map[string]*histogram)}
+}
+
+func (h *HistogramVec) Name() string {
+	return h.name
+}
+func (h *HistogramVec) Help() string {
+	return h.help
+}
+func (h *HistogramVec) Type() string {
+	return TypeHistogram
+}
+
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	values = normalize(values, len(h.labels))
+	key := strings.Join(values, "\xff")
+	h.mu.RLock()
+	s, ok := h.series[key]
+	h.mu.RUnlock()
+	if !ok {
+		h.mu.Lock()
+		if s, ok = h.series[key]; !ok {
+			s = &histogram{values: append([]string(nil), values...), counts: make([]uint64, len(h.buckets))}
+			h.series[key] = s
+		}
+		h.mu.Unlock()
+	}
+	i := sort.SearchFloat64s(h.buckets, v)
+	s.mu.Lock()
+	if i < len(s.counts) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+	s.mu.Unlock()
+}
+
+// Count returns the number of observations of the series.
+func (h *HistogramVec) Count(values ...string) uint64 {
+	key := strings.Join(normalize(values, len(h.labels)), "\xff")
+	h.mu.RLock()
+	s, ok := h.series[key]
+	h.mu.RUnlock()
+	if !ok {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+func (h *HistogramVec) Collect() []Sample {
+	h.mu.RLock()
+	list := make([]*histogram, 0, len(h.series))
+	for _, s := range h.series {
+		list = append(list, s)
+	}
+	h.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool {
+		return strings.Join(list[i].values, "\xff") < strings.Join(list[j].values, "\xff")
+	})
+	samples := make([]Sample, 0, len(list)*(len(h.buckets)+3))
+	for _, s := range list {
+		labels := toLabels(h.labels, s.values)
+		s.mu.Lock()
+		var cumulative uint64
+		for i, b := range h.buckets {
+			cumulative += s.counts[i]
+			samples = append(samples, Sample{Suffix: "_bucket", Labels: append(copyLabels(labels), Label{Name: "le", Value: FormatValue(b)}), Value: float64(cumulative)})
+		}
+		samples = append(samples, Sample{Suffix: "_bucket", Labels: append(copyLabels(labels), Label{Name: "le", Value: "+Inf"}), Value: float64(s.count)})
+		samples = append(samples, Sample{Suffix: "_sum", Labels: labels, Value: s.sum})
+		samples = append(samples, Sample{Suffix: "_count", Labels: labels, Value: float64(s.count)})
+		s.mu.Unlock()
+	}
+	return samples
+}
+
+// CollectorFunc computes the samples at scrape time, for the values which are owned by another component, such as sql.DB stats.
+type CollectorFunc struct {
+	name    string
+	help    string
+	typ     string
+	collect func() []Sample
+}
+
+func NewCollectorFunc(name string, help string, typ string, collect func() []Sample) *CollectorFunc {
+	return &CollectorFunc{name: name, help: help, typ: typ, collect: collect}
+}
+
+func NewGaugeFunc(name string, help string, f func() float64) *CollectorFunc {
+	return NewCollectorFunc(name, help, TypeGauge, func() []Sample {
+		return []Sample{{Value: f()}}
+	})
+}
+
+func (c *CollectorFunc) Name() string {
+	return c.name
+}
+func (c *CollectorFunc) Help() string {
+	return c.help
+}
+func (c *CollectorFunc) Type() string {
+	return c.typ
+}
+func (c *CollectorFunc) Collect() []Sample {
+	return c.collect()
+}
+
+func normalize(values []string, n int) []string {
+	if len(values) == n {
+		return values
+	}
+	res := make([]string, n)
+	copy(res, values)
+	return res
+}
+func toLabels(names []string, values []string) []Label {
+	labels := make([]Label, len(names))
+	for i, name := range names {
+		labels[i] = Label{Name: name, Value: values[i]}
+	}
+	return labels
+}
+func copyLabels(labels []Label) []Label {
+	res := make([]Label, len(labels), len(labels)+1)
+	copy(res, labels)
+	return res
+}
+func sortSamples(samples []Sample) {
+	sort.Slice(samples, func(i, j int) bool {
+		a, b := samples[i].Labels, samples[j].Labels
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k].Value != b[k].Value {
+				return a[k].Value < b[k].Value
+			}
+		}
+		return len(a) < len(b)
+	})
+}
@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+	TypeUntyped   = "untyped"
+)
+
+var ErrDuplicate = errors.New("metric is already registered")
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is one line of the exposition. Suffix is appended to the name of the collector, such as "_bucket", "_sum" or "_count".
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+type Collector interface {
+	Name() string
+	Help() string
+	Type() string
+	Collect() []Sample
+}
+
+// Registry keeps the collectors and writes them in the Prometheus text format.
+// The hooks are called before each scrape, so that the collectors which are expensive to compute can be refreshed once per scrape.
+type Registry struct {
+	mu         sync.RWMutex
+	collectors map[string]Collector
+	hooks      []func(context.Context)
+}
+
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]Collector)}
+}
+
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.collectors[c.Name()]; ok {
+		return ErrDuplicate
+	}
+	r.collectors[c.Name()] = c
+	return nil
+}
+
+func (r *Registry) MustRegister(collectors ...Collector) {
+	for _, c := range collectors {
+		if err := r.Register(c); err != nil {
+			panic(c.Name() + ": " + err.Error())
+		}
+	}
+}
+
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.collectors[name]
+	delete(r.collectors, name)
+	return ok
+}
+
+func (r *Registry) Get(name string) Collector {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.collectors[name]
+}
+
+func (r *Registry) OnCollect(hook func(context.Context)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, hook)
+}
+
+func (r *Registry) Collect(ctx context.Context) []Collector {
+	r.mu.RLock()
+	hooks := r.hooks
+	collectors := make([]Collector, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		collectors = append(collectors, c)
+	}
+	r.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].Name() < collectors[j].Name() })
+	return collectors
+}
+
+func (r *Registry) Write(ctx context.Context, w io.Writer) error {
+	var sb strings.Builder
+	for _, c := range r.Collect(ctx) {
+		WriteCollector(&sb, c)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func WriteCollector(sb *strings.Builder, c Collector) {
+	samples := c.Collect()
+	name := c.Name()
+	if len(c.Help()) > 0 {
+		sb.WriteString("# HELP " + name + " " + escapeHelp(c.Help()) + "\n")
+	}
+	sb.WriteString("# TYPE " + name + " " + c.Type() + "\n")
+	for _, s := range samples {
+		sb.WriteString(name + s.Suffix)
+		if len(s.Labels) > 0 {
+			sb.WriteByte('{')
+			for i, l := range s.Labels {
+				if i > 0 {
+					sb.WriteByte(',')
+				}
+				sb.WriteString(l.Name + "=\"" + escapeLabel(l.Value) + "\"")
+			}
+			sb.WriteByte('}')
+		}
+		sb.WriteByte(' ')
+		sb.WriteString(FormatValue(s.Value))
+		sb.WriteByte('\n')
+	}
+}
+
+func FormatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+var labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
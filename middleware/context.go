@@ -27,7 +27,7 @@ func BuildContextWithMask(next http.Handler, mask func(fieldName, s string) stri
 		if fieldConfig.Headers != nil && len(fieldConfig.Headers) > 0 {
 			for k, e := range fieldConfig.Headers {
 				if len(e) > 0 {
-					header := r.Header.Get(e)
+					header := masker.MaskHeader(e, r.Header.Get(e))
 					ctx = context.WithValue(ctx, k, header)
 				}
 			}
@@ -45,6 +45,7 @@ func BuildContextWithMask(next http.Handler, mask func(fieldName, s string) stri
 					next.ServeHTTP(w, r.WithContext(ctx))
 				}
 			} else {
+				v = masker.MaskValue(v)
 				m, ok := v.(map[string]interface{})
 				if !ok {
 					if len(fieldConfig.Ip) == 0 && fieldConfig.Constants == nil {
@@ -1,5 +1,7 @@
 package echo
 
+import "github.com/core-go/core/mask"
+
 type LogConfig struct {
 	Separate       bool              `yaml:"separate" mapstructure:"separate" json:"separate,omitempty" gorm:"column:separate" bson:"separate,omitempty" dynamodbav:"separate,omitempty" firestore:"separate,omitempty"`
 	Build          bool              `yaml:"build" mapstructure:"build" json:"build,omitempty" gorm:"column:build" bson:"build,omitempty" dynamodbav:"build,omitempty" firestore:"build,omitempty"`
@@ -26,6 +28,7 @@ type LogConfig struct {
 	Map            map[string]string `yaml:"map" mapstructure:"map" json:"map,omitempty" gorm:"column:map" bson:"map,omitempty" dynamodbav:"map,omitempty" firestore:"map,omitempty"`
 	Constants      map[string]string `yaml:"constants" mapstructure:"constants" json:"constants,omitempty" gorm:"column:constants" bson:"constants,omitempty" dynamodbav:"constants,omitempty" firestore:"constants,omitempty"`
 	Headers        map[string]string `yaml:"headers" mapstructure:"headers" json:"headers,omitempty" gorm:"column:headers" bson:"headers,omitempty" dynamodbav:"headers,omitempty" firestore:"headers,omitempty"`
+	Mask           *mask.Config      `yaml:"mask" mapstructure:"mask" json:"mask,omitempty" gorm:"column:mask" bson:"mask,omitempty" dynamodbav:"mask,omitempty" firestore:"mask,omitempty"`
 }
 
 type FieldConfig struct {
@@ -23,8 +23,18 @@ func NewEchoLogger(c LogConfig, logInfo func(ctx context.Context, msg string, fi
 	return &EchoLogger{c, logInfo, f, mask}
 }
 
+// Logger panics if the mask config is invalid. LoggerWithError returns the error instead.
 func (l *EchoLogger) Logger(next echo.HandlerFunc) echo.HandlerFunc {
 	InitializeFieldConfig(l.Config)
+	return l.logger(next)
+}
+func (l *EchoLogger) LoggerWithError() (echo.MiddlewareFunc, error) {
+	if err := InitializeFieldConfigWithError(l.Config); err != nil {
+		return nil, err
+	}
+	return l.logger, nil
+}
+func (l *EchoLogger) logger(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		if !fieldConfig.Log || InSkipList(c.Request(), fieldConfig.Skips) {
 			return next(c)
@@ -39,17 +49,19 @@ func (l *EchoLogger) Logger(next echo.HandlerFunc) echo.HandlerFunc {
 				includeRequest = true
 			} else {
 				BuildRequest(r, l.Config.Request, fields)
+				maskRequestBody(l.Config.Request, fields)
 			}
+			lr := maskRequestURI(r)
 			if !includeRequest {
-				go l.f.LogRequest(l.LogInfo, r, fields)
+				go l.f.LogRequest(l.LogInfo, lr, fields)
 			}
 			c.Response().Writer = ww
 			defer func() {
 				if includeRequest {
-					go l.f.LogResponse(l.LogInfo, r, ww, l.Config, startTime, dw.Body.String(), fields, includeRequest)
+					go l.f.LogResponse(l.LogInfo, lr, ww, l.Config, startTime, masker.MaskBody(dw.Body.String()), fields, includeRequest)
 				} else {
 					resFields := BuildLogFields(l.Config, r)
-					go l.f.LogResponse(l.LogInfo, r, ww, l.Config, startTime, dw.Body.String(), resFields, includeRequest)
+					go l.f.LogResponse(l.LogInfo, lr, ww, l.Config, startTime, masker.MaskBody(dw.Body.String()), resFields, includeRequest)
 				}
 			}()
 			return next(c)
@@ -75,7 +87,7 @@ func (l *EchoLogger) BuildContextWithMask(next echo.HandlerFunc) echo.HandlerFun
 		if fieldConfig.Headers != nil && len(fieldConfig.Headers) > 0 {
 			for k, e := range fieldConfig.Headers {
 				if len(e) > 0 {
-					header := r.Header.Get(e)
+					header := masker.MaskHeader(e, r.Header.Get(e))
 					ctx = context.WithValue(ctx, k, header)
 				}
 			}
@@ -94,6 +106,7 @@ func (l *EchoLogger) BuildContextWithMask(next echo.HandlerFunc) echo.HandlerFun
 					next(ctxEcho)
 				}
 			} else {
+				v = masker.MaskValue(v)
 				m, ok := v.(map[string]interface{})
 				if !ok {
 					if len(fieldConfig.Ip) == 0 && fieldConfig.Constants == nil {
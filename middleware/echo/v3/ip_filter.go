@@ -0,0 +1,31 @@
+package echo
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/core-go/core/clientip"
+)
+
+type IpFilter struct {
+	Filter *clientip.Filter
+}
+
+func NewIpFilter(c clientip.FilterConfig) (*IpFilter, error) {
+	filter, err := clientip.NewFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	return &IpFilter{Filter: filter}, nil
+}
+
+func (f *IpFilter) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r := c.Request()
+		if !f.Filter.Allowed(r.Method, r.URL.Path, getRemoteIp(r)) {
+			return c.String(http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		}
+		return next(c)
+	}
+}
@@ -1,12 +1,20 @@
 package echo
 
 import (
-	"net"
 	"net/http"
 	"strings"
+
+	"github.com/core-go/core/clientip"
+	"github.com/core-go/core/mask"
 )
 
+// InitializeFieldConfig panics if the mask config is invalid. InitializeFieldConfigWithError returns the error instead.
 func InitializeFieldConfig(c LogConfig) {
+	if err := InitializeFieldConfigWithError(c); err != nil {
+		panic(err)
+	}
+}
+func InitializeFieldConfigWithError(c LogConfig) error {
 	if len(c.Duration) > 0 {
 		fieldConfig.Duration = c.Duration
 	} else {
@@ -35,6 +43,14 @@ func InitializeFieldConfig(c LogConfig) {
 		fields := strings.Split(c.Skips, ",")
 		fieldConfig.Skips = fields
 	}
+	if c.Mask != nil {
+		m, err := mask.NewMasker(*c.Mask)
+		if err != nil {
+			return err
+		}
+		masker = m
+	}
+	return nil
 }
 func InSkipList(r *http.Request, skips []string) bool {
 	if skips == nil || len(skips) == 0 {
@@ -57,7 +73,7 @@ func BuildLogFields(c LogConfig, r *http.Request) map[string]interface{} {
 		scheme = "https"
 	}
 	if len(c.Uri) > 0 {
-		fields[c.Uri] = r.RequestURI
+		fields[c.Uri] = masker.MaskURI(r.RequestURI)
 	}
 
 	if len(c.ReqId) > 0 {
@@ -87,9 +103,5 @@ func BuildLogFields(c LogConfig, r *http.Request) map[string]interface{} {
 	return fields
 }
 func getRemoteIp(r *http.Request) string {
-	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		remoteIP = r.RemoteAddr
-	}
-	return remoteIP
+	return clientip.GetClientIp(r)
 }
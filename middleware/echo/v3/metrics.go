@@ -0,0 +1,52 @@
+package echo
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+
+	"github.com/core-go/core/metrics"
+)
+
+type Metrics struct {
+	Metrics *metrics.HttpMetrics
+}
+
+func NewMetrics(m *metrics.HttpMetrics) *Metrics {
+	return &Metrics{Metrics: m}
+}
+
+// Handle uses the path of the matched route as the route template. Unmatched requests are resolved by the routes of the config.
+func (m *Metrics) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r := c.Request()
+		if m.Metrics.Skip(r.URL.Path) {
+			return next(c)
+		}
+		start := time.Now()
+		m.Metrics.Begin()
+		completed := false
+		var err error
+		defer func() {
+			status := c.Response().Status
+			if !completed {
+				status = http.StatusInternalServerError
+			} else if err != nil {
+				if he, ok := err.(*echo.HTTPError); ok {
+					status = he.Code
+				} else {
+					status = http.StatusInternalServerError
+				}
+			}
+			route := c.Path()
+			if len(route) == 0 {
+				route = m.Metrics.Route(r.URL.Path)
+			}
+			m.Metrics.End(metrics.Method(r.Method), route, status, time.Since(start))
+		}()
+		err = next(c)
+		completed = true
+		return err
+	}
+}
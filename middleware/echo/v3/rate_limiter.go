@@ -0,0 +1,45 @@
+package echo
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/core-go/core/ratelimit"
+)
+
+type RateLimiter struct {
+	Config   ratelimit.Config
+	Limiter  ratelimit.Limiter
+	LogError func(context.Context, string, ...map[string]interface{})
+}
+
+func NewRateLimiter(c ratelimit.Config, limiter ratelimit.Limiter, opts ...func(context.Context, string, ...map[string]interface{})) *RateLimiter {
+	var logError func(context.Context, string, ...map[string]interface{})
+	if len(opts) > 0 {
+		logError = opts[0]
+	}
+	return &RateLimiter{Config: ratelimit.InitConfig(c), Limiter: limiter, LogError: logError}
+}
+
+func (l *RateLimiter) Limit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r := c.Request()
+		res, err := ratelimit.Check(r.Context(), l.Limiter, l.Config, r, getRemoteIp(r))
+		if err != nil {
+			if l.LogError != nil {
+				l.LogError(r.Context(), "cannot check rate limit: "+err.Error())
+			}
+			return next(c)
+		}
+		if res == nil {
+			return next(c)
+		}
+		ratelimit.SetHeaders(c.Response().Header(), res)
+		if !res.Allowed {
+			return c.String(http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests))
+		}
+		return next(c)
+	}
+}
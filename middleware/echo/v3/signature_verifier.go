@@ -0,0 +1,40 @@
+package echo
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/core-go/core/webhook"
+)
+
+type SignatureVerifier struct {
+	Verifier *webhook.Verifier
+	LogError func(context.Context, string, ...map[string]interface{})
+}
+
+func NewSignatureVerifier(verifier *webhook.Verifier, opts ...func(context.Context, string, ...map[string]interface{})) *SignatureVerifier {
+	var logError func(context.Context, string, ...map[string]interface{})
+	if len(opts) > 0 {
+		logError = opts[0]
+	}
+	return &SignatureVerifier{Verifier: verifier, LogError: logError}
+}
+
+func (v *SignatureVerifier) Verify(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r := c.Request()
+		if err := v.Verifier.VerifyRequest(r); err != nil {
+			status := webhook.GetStatus(err)
+			if status == http.StatusInternalServerError {
+				if v.LogError != nil {
+					v.LogError(r.Context(), "cannot verify signature: "+err.Error())
+				}
+				return c.JSON(status, http.StatusText(status))
+			}
+			return c.JSON(status, err.Error())
+		}
+		return next(c)
+	}
+}
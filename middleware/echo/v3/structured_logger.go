@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/core-go/core/log/ship"
 )
 
 type Formatter interface {
@@ -15,6 +17,7 @@ type Formatter interface {
 }
 type StructuredLogger struct {
 	send       func(context.Context, []byte, map[string]string) error
+	queued     bool
 	KeyMap     map[string]string
 	RequestKey string
 	JsonFormat bool
@@ -35,13 +38,31 @@ func NewLoggerWithSending(requestKey string, jsonFormat bool, send func(context.
 	}
 	return &StructuredLogger{RequestKey: requestKey, JsonFormat: jsonFormat, send: send, KeyMap: keyMap}
 }
+
+// NewLoggerWithShipper queues the logs to the shipper, which sends them in batches, instead of a goroutine per log.
+// The shipper is started by ship.NewShipper, and its Shutdown must be called when the server stops, to send the queued logs.
+// If the shipper is nil, the logs are not sent.
+func NewLoggerWithShipper(requestKey string, jsonFormat bool, shipper *ship.Shipper, options ...map[string]string) *StructuredLogger {
+	var keyMap map[string]string
+	if len(options) >= 1 {
+		keyMap = options[0]
+	}
+	if shipper == nil {
+		return &StructuredLogger{RequestKey: requestKey, JsonFormat: jsonFormat, KeyMap: keyMap}
+	}
+	return &StructuredLogger{RequestKey: requestKey, JsonFormat: jsonFormat, send: shipper.Send, queued: true, KeyMap: keyMap}
+}
 func (l *StructuredLogger) LogResponse(log func(context.Context, string, map[string]interface{}), r *http.Request, ww WrapResponseWriter,
 	c LogConfig, t1 time.Time, response string, fields map[string]interface{}, includeRequest bool) {
 	BuildResponse(ww, c, t1, response, fields, l.JsonFormat)
 	msg := r.Method + " " + r.RequestURI
 	log(r.Context(), msg, fields)
 	if l.send != nil {
-		go Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		if l.queued {
+			Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		} else {
+			go Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		}
 	}
 }
 func Send(ctx context.Context, send func(context.Context, []byte, map[string]string) error, msg string, fields map[string]interface{}, keyMap map[string]string) {
@@ -68,7 +89,11 @@ func (l *StructuredLogger) LogRequest(log func(context.Context, string, map[stri
 	}
 	log(r.Context(), msg, fields)
 	if l.send != nil {
-		go Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		if l.queued {
+			Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		} else {
+			go Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		}
 	}
 }
 
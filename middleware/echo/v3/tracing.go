@@ -0,0 +1,36 @@
+package echo
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/core-go/core/trace"
+)
+
+type Tracing struct {
+	Tracer *trace.Tracer
+}
+
+func NewTracing(tracer *trace.Tracer) *Tracing {
+	return &Tracing{Tracer: tracer}
+}
+
+func (t *Tracing) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r, span := trace.Resolve(t.Tracer).StartServer(c.Response(), c.Request())
+		c.SetRequest(r)
+		err := next(c)
+		status := c.Response().Status
+		if err != nil {
+			span.SetError(err)
+			if he, ok := err.(*echo.HTTPError); ok {
+				status = he.Code
+			} else {
+				status = http.StatusInternalServerError
+			}
+		}
+		trace.FinishServer(span, c.Path(), status)
+		return err
+	}
+}
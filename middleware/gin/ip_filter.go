@@ -0,0 +1,32 @@
+package gin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/core-go/core/clientip"
+)
+
+type IpFilter struct {
+	Filter *clientip.Filter
+}
+
+func NewIpFilter(c clientip.FilterConfig) (*IpFilter, error) {
+	filter, err := clientip.NewFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	return &IpFilter{Filter: filter}, nil
+}
+
+func (f *IpFilter) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r := c.Request
+		if !f.Filter.Allowed(r.Method, r.URL.Path, getRemoteIp(r)) {
+			c.AbortWithStatusJSON(http.StatusForbidden, http.StatusText(http.StatusForbidden))
+			return
+		}
+		c.Next()
+	}
+}
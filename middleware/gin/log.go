@@ -23,8 +23,18 @@ func NewGinLogger(c LogConfig, logInfo func(ctx context.Context, msg string, fie
 	return &GinLogger{c, logInfo, f, mask}
 }
 
+// Logger panics if the mask config is invalid. LoggerWithError returns the error instead.
 func (l *GinLogger) Logger() gin.HandlerFunc {
 	InitializeFieldConfig(l.Config)
+	return l.logger()
+}
+func (l *GinLogger) LoggerWithError() (gin.HandlerFunc, error) {
+	if err := InitializeFieldConfigWithError(l.Config); err != nil {
+		return nil, err
+	}
+	return l.logger(), nil
+}
+func (l *GinLogger) logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if !fieldConfig.Log || InSkipList(c.Request, fieldConfig.Skips) {
 			c.Next()
@@ -39,17 +49,19 @@ func (l *GinLogger) Logger() gin.HandlerFunc {
 				includeRequest = true
 			} else {
 				BuildRequest(r, l.Config.Request, fields)
+				maskRequestBody(l.Config.Request, fields)
 			}
+			lr := maskRequestURI(r)
 			if !includeRequest {
-				go l.f.LogRequest(l.LogInfo, r, fields)
+				go l.f.LogRequest(l.LogInfo, lr, fields)
 			}
 			c.Writer = dw
 			defer func() {
 				if includeRequest {
-					go l.f.LogResponse(l.LogInfo, r, *dw, l.Config, startTime, dw.Body.String(), fields, includeRequest)
+					go l.f.LogResponse(l.LogInfo, lr, *dw, l.Config, startTime, masker.MaskBody(dw.Body.String()), fields, includeRequest)
 				} else {
 					resFields := BuildLogFields(l.Config, r)
-					go l.f.LogResponse(l.LogInfo, r, *dw, l.Config, startTime, dw.Body.String(), resFields, includeRequest)
+					go l.f.LogResponse(l.LogInfo, lr, *dw, l.Config, startTime, masker.MaskBody(dw.Body.String()), resFields, includeRequest)
 				}
 			}()
 			c.Next()
@@ -74,7 +86,7 @@ func (l *GinLogger) BuildContextWithMask() gin.HandlerFunc {
 		if fieldConfig.Headers != nil && len(fieldConfig.Headers) > 0 {
 			for k, e := range fieldConfig.Headers {
 				if len(e) > 0 {
-					header := r.Header.Get(e)
+					header := masker.MaskHeader(e, r.Header.Get(e))
 					ctx = context.WithValue(ctx, k, header)
 				}
 			}
@@ -94,6 +106,7 @@ func (l *GinLogger) BuildContextWithMask() gin.HandlerFunc {
 					ctxGin.Next()
 				}
 			} else {
+				v = masker.MaskValue(v)
 				m, ok := v.(map[string]interface{})
 				if !ok {
 					if len(fieldConfig.Ip) == 0 && fieldConfig.Constants == nil {
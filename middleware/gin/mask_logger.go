@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/core-go/core/log/ship"
 )
 
 type MaskLogger struct {
 	send         func(context.Context, []byte, map[string]string) error
+	queued       bool
 	KeyMap       map[string]string
 	RequestKey   string
 	MaskRequest  func(map[string]interface{})
@@ -32,6 +35,20 @@ func NewMaskLoggerWithSending(requestKey string, maskRequest func(map[string]int
 	return &MaskLogger{RequestKey: requestKey, MaskRequest: maskRequest, MaskResponse: maskResponse, JsonFormat: jsonFormat, send: send, KeyMap: keyMap}
 }
 
+// NewMaskLoggerWithShipper queues the logs to the shipper, which sends them in batches, instead of a goroutine per log.
+// The shipper is started by ship.NewShipper, and its Shutdown must be called when the server stops, to send the queued logs.
+// If the shipper is nil, the logs are not sent.
+func NewMaskLoggerWithShipper(requestKey string, maskRequest func(map[string]interface{}), maskResponse func(map[string]interface{}), jsonFormat bool, shipper *ship.Shipper, options ...map[string]string) *MaskLogger {
+	var keyMap map[string]string
+	if len(options) >= 1 {
+		keyMap = options[0]
+	}
+	if shipper == nil {
+		return &MaskLogger{RequestKey: requestKey, MaskRequest: maskRequest, MaskResponse: maskResponse, JsonFormat: jsonFormat, KeyMap: keyMap}
+	}
+	return &MaskLogger{RequestKey: requestKey, MaskRequest: maskRequest, MaskResponse: maskResponse, JsonFormat: jsonFormat, send: shipper.Send, queued: true, KeyMap: keyMap}
+}
+
 func (l *MaskLogger) LogResponse(log func(context.Context, string, map[string]interface{}), r *http.Request, ww ResponseWriter,
 	c LogConfig, t1 time.Time, response string, fields map[string]interface{}, includeRequest bool) {
 	if includeRequest && len(c.Request) > 0 {
@@ -41,7 +58,11 @@ func (l *MaskLogger) LogResponse(log func(context.Context, string, map[string]in
 	msg := r.Method + " " + r.RequestURI
 	log(r.Context(), msg, fields)
 	if l.send != nil {
-		go Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		if l.queued {
+			Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		} else {
+			go Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		}
 	}
 }
 func (l *MaskLogger) LogRequest(log func(context.Context, string, map[string]interface{}), r *http.Request, fields map[string]interface{}) {
@@ -49,7 +70,11 @@ func (l *MaskLogger) LogRequest(log func(context.Context, string, map[string]int
 	msg := "Request " + r.Method + " " + r.RequestURI
 	log(r.Context(), msg, fields)
 	if l.send != nil {
-		go Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		if l.queued {
+			Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		} else {
+			go Send(r.Context(), l.send, msg, fields, l.KeyMap)
+		}
 	}
 }
 
@@ -0,0 +1,28 @@
+package gin
+
+import (
+	"net/http"
+
+	"github.com/core-go/core/mask"
+)
+
+var masker *mask.Masker
+
+// maskRequestURI returns a copy of the request with the query string masked by LogConfig.Mask, so that the formatters log the masked uri.
+func maskRequestURI(r *http.Request) *http.Request {
+	if masker == nil {
+		return r
+	}
+	r2 := r.WithContext(r.Context())
+	r2.RequestURI = masker.MaskURI(r.RequestURI)
+	return r2
+}
+
+func maskRequestBody(request string, fields map[string]interface{}) {
+	if masker == nil || len(request) == 0 {
+		return
+	}
+	if s, ok := fields[request].(string); ok {
+		fields[request] = masker.MaskBody(s)
+	}
+}
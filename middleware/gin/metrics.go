@@ -0,0 +1,45 @@
+package gin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/core-go/core/metrics"
+)
+
+type Metrics struct {
+	Metrics *metrics.HttpMetrics
+}
+
+func NewMetrics(m *metrics.HttpMetrics) *Metrics {
+	return &Metrics{Metrics: m}
+}
+
+// Handle uses the full path of the matched route as the route template. Unmatched requests are resolved by the routes of the config.
+func (m *Metrics) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r := c.Request
+		if m.Metrics.Skip(r.URL.Path) {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		m.Metrics.Begin()
+		completed := false
+		defer func() {
+			route := c.FullPath()
+			if len(route) == 0 {
+				route = m.Metrics.Route(r.URL.Path)
+			}
+			status := c.Writer.Status()
+			if !completed {
+				status = http.StatusInternalServerError
+			}
+			m.Metrics.End(metrics.Method(r.Method), route, status, time.Since(start))
+		}()
+		c.Next()
+		completed = true
+	}
+}
@@ -0,0 +1,48 @@
+package gin
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/core-go/core/ratelimit"
+)
+
+type RateLimiter struct {
+	Config   ratelimit.Config
+	Limiter  ratelimit.Limiter
+	LogError func(context.Context, string, ...map[string]interface{})
+}
+
+func NewRateLimiter(c ratelimit.Config, limiter ratelimit.Limiter, opts ...func(context.Context, string, ...map[string]interface{})) *RateLimiter {
+	var logError func(context.Context, string, ...map[string]interface{})
+	if len(opts) > 0 {
+		logError = opts[0]
+	}
+	return &RateLimiter{Config: ratelimit.InitConfig(c), Limiter: limiter, LogError: logError}
+}
+
+func (l *RateLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r := c.Request
+		res, err := ratelimit.Check(r.Context(), l.Limiter, l.Config, r, getRemoteIp(r))
+		if err != nil {
+			if l.LogError != nil {
+				l.LogError(r.Context(), "cannot check rate limit: "+err.Error())
+			}
+			c.Next()
+			return
+		}
+		if res == nil {
+			c.Next()
+			return
+		}
+		ratelimit.SetHeaders(c.Writer.Header(), res)
+		if !res.Allowed {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,24 @@
+package gin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/core-go/core/trace"
+)
+
+type Tracing struct {
+	Tracer *trace.Tracer
+}
+
+func NewTracing(tracer *trace.Tracer) *Tracing {
+	return &Tracing{Tracer: tracer}
+}
+
+func (t *Tracing) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r, span := trace.Resolve(t.Tracer).StartServer(c.Writer, c.Request)
+		c.Request = r
+		c.Next()
+		trace.FinishServer(span, c.FullPath(), c.Writer.Status())
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/core-go/core/clientip"
+)
+
+type IpFilter struct {
+	Filter *clientip.Filter
+}
+
+func NewIpFilter(c clientip.FilterConfig) (*IpFilter, error) {
+	filter, err := clientip.NewFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	return &IpFilter{Filter: filter}, nil
+}
+
+func (f *IpFilter) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !f.Filter.Allowed(r.Method, r.URL.Path, getRemoteIp(r)) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
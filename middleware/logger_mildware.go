@@ -2,13 +2,21 @@ package middleware
 
 import (
 	"context"
-	"net"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/core-go/core/clientip"
+	"github.com/core-go/core/mask"
 )
 
+// InitializeFieldConfig panics if the mask config is invalid. InitializeFieldConfigWithError returns the error instead.
 func InitializeFieldConfig(c LogConfig) {
+	if err := InitializeFieldConfigWithError(c); err != nil {
+		panic(err)
+	}
+}
+func InitializeFieldConfigWithError(c LogConfig) error {
 	if len(c.Duration) > 0 {
 		fieldConfig.Duration = c.Duration
 	} else {
@@ -37,9 +45,28 @@ func InitializeFieldConfig(c LogConfig) {
 		fields := strings.Split(c.Skips, ",")
 		fieldConfig.Skips = fields
 	}
+	if c.Mask != nil {
+		m, err := mask.NewMasker(*c.Mask)
+		if err != nil {
+			return err
+		}
+		masker = m
+	}
+	return nil
 }
+
+// Logger panics if the mask config is invalid. LoggerWithError returns the error instead.
 func Logger(c LogConfig, log func(ctx context.Context, msg string, fields map[string]interface{}), f Formatter) func(h http.Handler) http.Handler {
 	InitializeFieldConfig(c)
+	return logger(c, log, f)
+}
+func LoggerWithError(c LogConfig, log func(ctx context.Context, msg string, fields map[string]interface{}), f Formatter) (func(h http.Handler) http.Handler, error) {
+	if err := InitializeFieldConfigWithError(c); err != nil {
+		return nil, err
+	}
+	return logger(c, log, f), nil
+}
+func logger(c LogConfig, log func(ctx context.Context, msg string, fields map[string]interface{}), f Formatter) func(h http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			if !fieldConfig.Log || InSkipList(r, fieldConfig.Skips) {
@@ -54,16 +81,18 @@ func Logger(c LogConfig, log func(ctx context.Context, msg string, fields map[st
 					includeRequest = true
 				} else {
 					BuildRequestBody(r, c.Request, fields)
+					maskRequestBody(c.Request, fields)
 				}
+				lr := maskRequestURI(r)
 				if !includeRequest {
-					go f.LogRequest(log, r, fields)
+					go f.LogRequest(log, lr, fields)
 				}
 				defer func() {
 					if includeRequest {
-						go f.LogResponse(log, r, ww, c, startTime, dw.Body.String(), fields, includeRequest)
+						go f.LogResponse(log, lr, ww, c, startTime, masker.MaskBody(dw.Body.String()), fields, includeRequest)
 					} else {
 						resFields := BuildLogFields(c, r)
-						go f.LogResponse(log, r, ww, c, startTime, dw.Body.String(), resFields, includeRequest)
+						go f.LogResponse(log, lr, ww, c, startTime, masker.MaskBody(dw.Body.String()), resFields, includeRequest)
 					}
 				}()
 				h.ServeHTTP(ww, r)
@@ -93,7 +122,7 @@ func BuildLogFields(c LogConfig, r *http.Request) map[string]interface{} {
 		scheme = "https"
 	}
 	if len(c.Uri) > 0 {
-		fields[c.Uri] = r.RequestURI
+		fields[c.Uri] = masker.MaskURI(r.RequestURI)
 	}
 
 	if len(c.ReqId) > 0 {
@@ -123,9 +152,5 @@ func BuildLogFields(c LogConfig, r *http.Request) map[string]interface{} {
 	return fields
 }
 func getRemoteIp(r *http.Request) string {
-	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		remoteIP = r.RemoteAddr
-	}
-	return remoteIP
+	return clientip.GetClientIp(r)
 }
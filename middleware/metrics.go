@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/core-go/core/metrics"
+)
+
+// Metrics records the RED metrics of the requests. The route template is resolved by GetRoute if it is set, for example from the router, or else by the routes of the config.
+type Metrics struct {
+	Metrics  *metrics.HttpMetrics
+	GetRoute func(*http.Request) string
+}
+
+func NewMetrics(m *metrics.HttpMetrics, opts ...func(*http.Request) string) *Metrics {
+	var getRoute func(*http.Request) string
+	if len(opts) > 0 {
+		getRoute = opts[0]
+	}
+	return &Metrics{Metrics: m, GetRoute: getRoute}
+}
+
+func (m *Metrics) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.Metrics.Skip(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ww := NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+		m.Metrics.Begin()
+		completed := false
+		defer func() {
+			var route string
+			if m.GetRoute != nil {
+				route = m.GetRoute(r)
+			}
+			if len(route) == 0 {
+				route = m.Metrics.Route(r.URL.Path)
+			}
+			status := ww.Status()
+			if !completed {
+				status = http.StatusInternalServerError
+			}
+			m.Metrics.End(metrics.Method(r.Method), route, status, time.Since(start))
+		}()
+		next.ServeHTTP(ww, r)
+		completed = true
+	})
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/core-go/core/ratelimit"
+)
+
+type RateLimiter struct {
+	Config   ratelimit.Config
+	Limiter  ratelimit.Limiter
+	LogError func(context.Context, string, ...map[string]interface{})
+}
+
+func NewRateLimiter(c ratelimit.Config, limiter ratelimit.Limiter, opts ...func(context.Context, string, ...map[string]interface{})) *RateLimiter {
+	var logError func(context.Context, string, ...map[string]interface{})
+	if len(opts) > 0 {
+		logError = opts[0]
+	}
+	return &RateLimiter{Config: ratelimit.InitConfig(c), Limiter: limiter, LogError: logError}
+}
+
+func (l *RateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, err := ratelimit.Check(r.Context(), l.Limiter, l.Config, r, getRemoteIp(r))
+		if err != nil {
+			if l.LogError != nil {
+				l.LogError(r.Context(), "cannot check rate limit: "+err.Error())
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		if res == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ratelimit.SetHeaders(w.Header(), res)
+		if !res.Allowed {
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
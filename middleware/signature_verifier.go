@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/core-go/core/webhook"
+)
+
+type SignatureVerifier struct {
+	Verifier *webhook.Verifier
+	LogError func(context.Context, string, ...map[string]interface{})
+}
+
+func NewSignatureVerifier(verifier *webhook.Verifier, opts ...func(context.Context, string, ...map[string]interface{})) *SignatureVerifier {
+	var logError func(context.Context, string, ...map[string]interface{})
+	if len(opts) > 0 {
+		logError = opts[0]
+	}
+	return &SignatureVerifier{Verifier: verifier, LogError: logError}
+}
+
+func (v *SignatureVerifier) Verify(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := v.Verifier.VerifyRequest(r); err != nil {
+			status := webhook.GetStatus(err)
+			if status == http.StatusInternalServerError {
+				if v.LogError != nil {
+					v.LogError(r.Context(), "cannot verify signature: "+err.Error())
+				}
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
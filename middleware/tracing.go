@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/core-go/core/trace"
+)
+
+// Tracing continues the trace of the traceparent header, or starts a new one, and runs the handler in a server span.
+type Tracing struct {
+	Tracer   *trace.Tracer
+	GetRoute func(*http.Request) string
+}
+
+func NewTracing(tracer *trace.Tracer, opts ...func(*http.Request) string) *Tracing {
+	var getRoute func(*http.Request) string
+	if len(opts) > 0 {
+		getRoute = opts[0]
+	}
+	return &Tracing{Tracer: tracer, GetRoute: getRoute}
+}
+
+func (t *Tracing) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, span := trace.Resolve(t.Tracer).StartServer(w, r)
+		ww := NewWrapResponseWriter(w, r.ProtoMajor)
+		completed := false
+		defer func() {
+			var route string
+			if t.GetRoute != nil {
+				route = t.GetRoute(r)
+			}
+			status := ww.Status()
+			if !completed {
+				status = http.StatusInternalServerError
+			}
+			trace.FinishServer(span, route, status)
+		}()
+		next.ServeHTTP(ww, r)
+		completed = true
+	})
+}
@@ -3,12 +3,13 @@ package core
 import (
 	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/core-go/core/clientip"
 )
 
 const (
@@ -203,9 +204,5 @@ func QueryRequiredTime(w http.ResponseWriter, s url.Values, name string) *time.T
 	return v
 }
 func GetRemoteIp(r *http.Request) string {
-	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		remoteIP = r.RemoteAddr
-	}
-	return remoteIP
+	return clientip.GetClientIp(r)
 }
@@ -0,0 +1,108 @@
+package passcode
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type CachePort interface {
+	Put(ctx context.Context, key string, obj interface{}, timeToLive time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+	ContainsKey(ctx context.Context, key string) (bool, error)
+	Remove(ctx context.Context, key string) (bool, error)
+}
+
+// AtomicCachePort is required by Replace, an empty old means the key does not exist.
+type AtomicCachePort interface {
+	CachePort
+	CompareAndSwap(ctx context.Context, key string, old string, value string, timeToLive time.Duration) (bool, error)
+}
+
+type CachePasscodeService struct {
+	Cache  CachePort
+	Prefix string
+}
+
+func NewCachePasscodeService(cache CachePort, options ...string) *CachePasscodeService {
+	prefix := "passcode:"
+	if len(options) > 0 && len(options[0]) > 0 {
+		prefix = options[0]
+	}
+	return &CachePasscodeService{Cache: cache, Prefix: prefix}
+}
+
+func (s *CachePasscodeService) Save(ctx context.Context, id string, passcode string, expireAt time.Time) (int64, error) {
+	ttl := time.Until(expireAt)
+	if ttl <= 0 {
+		return s.Delete(ctx, id)
+	}
+	bs, err := json.Marshal(Passcode{Id: id, Passcode: passcode, ExpiredAt: expireAt})
+	if err != nil {
+		return -1, err
+	}
+	if err = s.Cache.Put(ctx, s.Prefix+id, string(bs), ttl); err != nil {
+		return -1, err
+	}
+	return 1, nil
+}
+func (s *CachePasscodeService) Load(ctx context.Context, id string) (string, time.Time, error) {
+	ok, err := s.Cache.ContainsKey(ctx, s.Prefix+id)
+	if err != nil || !ok {
+		return "", time.Now(), err
+	}
+	v, err := s.Cache.Get(ctx, s.Prefix+id)
+	if err != nil {
+		return "", time.Now(), err
+	}
+	var code Passcode
+	if err = json.Unmarshal([]byte(v), &code); err != nil {
+		return "", time.Now(), err
+	}
+	return code.Passcode, code.ExpiredAt, nil
+}
+func (s *CachePasscodeService) Delete(ctx context.Context, id string) (int64, error) {
+	ok, err := s.Cache.Remove(ctx, s.Prefix+id)
+	if err != nil {
+		return -1, err
+	}
+	if ok {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Replace requires the cache to implement AtomicCachePort, it returns ErrNotAtomic if not.
+func (s *CachePasscodeService) Replace(ctx context.Context, id string, old string, passcode string, expireAt time.Time) (bool, error) {
+	cache, ok := s.Cache.(AtomicCachePort)
+	if !ok {
+		return false, ErrNotAtomic
+	}
+	var raw string
+	if len(old) > 0 {
+		exist, err := s.Cache.ContainsKey(ctx, s.Prefix+id)
+		if err != nil || !exist {
+			return false, err
+		}
+		raw, err = s.Cache.Get(ctx, s.Prefix+id)
+		if err != nil {
+			return false, err
+		}
+		var code Passcode
+		if err = json.Unmarshal([]byte(raw), &code); err != nil {
+			return false, err
+		}
+		if code.Passcode != old {
+			return false, nil
+		}
+	}
+	bs, err := json.Marshal(Passcode{Id: id, Passcode: passcode, ExpiredAt: expireAt})
+	if err != nil {
+		return false, err
+	}
+	ttl := time.Until(expireAt)
+	if ttl <= 0 {
+		ttl = time.Millisecond
+	}
+	return cache.CompareAndSwap(ctx, s.Prefix+id, raw, string(bs), ttl)
+}
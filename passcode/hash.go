@@ -0,0 +1,36 @@
+package passcode
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// HashPasscode returns the salted hash with the format <salt>:<hmac-sha256>, so that the plain code is never stored.
+func HashPasscode(code string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return hashWithSalt(salt, code), nil
+}
+func ComparePasscode(code string, hashed string) bool {
+	i := strings.Index(hashed, ":")
+	if i <= 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(hashed[:i])
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashWithSalt(salt, code)), []byte(hashed)) == 1
+}
+func hashWithSalt(salt []byte, code string) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(code))
+	return base64.RawStdEncoding.EncodeToString(salt) + ":" + hex.EncodeToString(mac.Sum(nil))
+}
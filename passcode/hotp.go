@@ -0,0 +1,64 @@
+package passcode
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+const (
+	SHA1   = "SHA1"
+	SHA256 = "SHA256"
+	SHA512 = "SHA512"
+)
+
+func getHash(algorithm string) func() hash.Hash {
+	switch strings.ToUpper(algorithm) {
+	case SHA256:
+		return sha256.New
+	case SHA512:
+		return sha512.New
+	default:
+		return sha1.New
+	}
+}
+
+// GenerateHOTP generates the code of RFC 4226 for the counter, using the dynamic truncation of the HMAC.
+func GenerateHOTP(secret []byte, counter uint64, digits int, algorithm string) string {
+	if digits <= 0 {
+		digits = 6
+	}
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+	mac := hmac.New(getHash(algorithm), secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod = mod * 10
+	}
+	return padLeft(strconv.FormatUint(uint64(code%mod), 10), digits, "0")
+}
+
+// VerifyHOTP checks the code from counter to counter+lookAhead, and returns the next counter to store if the code is valid.
+func VerifyHOTP(secret []byte, code string, counter uint64, lookAhead int, digits int, algorithm string) (uint64, bool) {
+	for i := 0; i <= lookAhead; i++ {
+		c := counter + uint64(i)
+		if EqualCode(GenerateHOTP(secret, c, digits, algorithm), code) {
+			return c + 1, true
+		}
+	}
+	return counter, false
+}
+
+func EqualCode(expected string, code string) bool {
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1
+}
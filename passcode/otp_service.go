@@ -0,0 +1,194 @@
+package passcode
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotEnrolled = errors.New("two factor authentication is not enrolled")
+	ErrInvalidCode = errors.New("invalid code")
+)
+
+// OtpService stores the TOTP secret, the last used counter and the hashed recovery codes by PasscodeService, with the keys prefixed by the user id.
+// Verify and Recover require the Service to implement AtomicPasscodeService.
+type OtpService struct {
+	Service        PasscodeService
+	TOTP           *TOTP
+	Prefix         string
+	EnrollExpires  time.Duration
+	RecoveryCodes  int
+	RecoveryLength int
+	Now            func() time.Time
+}
+
+func NewOtpService(service PasscodeService, totp *TOTP, options ...string) *OtpService {
+	prefix := "otp:"
+	if len(options) > 0 && len(options[0]) > 0 {
+		prefix = options[0]
+	}
+	return &OtpService{Service: service, TOTP: totp, Prefix: prefix, EnrollExpires: 10 * time.Minute, RecoveryCodes: 10, RecoveryLength: 10, Now: time.Now}
+}
+
+func (s *OtpService) secretKey(userId string) string {
+	return s.Prefix + "secret:" + userId
+}
+func (s *OtpService) pendingKey(userId string) string {
+	return s.Prefix + "pending:" + userId
+}
+func (s *OtpService) counterKey(userId string) string {
+	return s.Prefix + "counter:" + userId
+}
+func (s *OtpService) recoveryKey(userId string) string {
+	return s.Prefix + "recovery:" + userId
+}
+func (s *OtpService) never() time.Time {
+	return s.Now().AddDate(100, 0, 0)
+}
+
+// Enroll generates a pending secret and returns it with the otpauth URI. The secret is activated only after Confirm with a valid code.
+func (s *OtpService) Enroll(ctx context.Context, userId string, account string) (string, string, error) {
+	secret, err := GenerateSecret(20)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err = s.Service.Save(ctx, s.pendingKey(userId), secret, s.Now().Add(s.EnrollExpires)); err != nil {
+		return "", "", err
+	}
+	return secret, s.TOTP.URI(secret, account), nil
+}
+
+// Confirm activates the pending secret if the code is valid, and returns the plain recovery codes, which are shown to the user only once.
+func (s *OtpService) Confirm(ctx context.Context, userId string, code string) ([]string, error) {
+	secret, expiredAt, err := s.Service.Load(ctx, s.pendingKey(userId))
+	if err != nil {
+		return nil, err
+	}
+	now := s.Now()
+	if len(secret) == 0 || now.After(expiredAt) {
+		return nil, ErrNotEnrolled
+	}
+	counter, ok, err := s.TOTP.Validate(secret, code, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+	if _, err = s.Service.Save(ctx, s.secretKey(userId), secret, s.never()); err != nil {
+		return nil, err
+	}
+	if _, err = s.Service.Save(ctx, s.counterKey(userId), strconv.FormatUint(counter, 10), s.never()); err != nil {
+		return nil, err
+	}
+	if _, err = s.Service.Delete(ctx, s.pendingKey(userId)); err != nil {
+		return nil, err
+	}
+	return s.GenerateRecoveryCodes(ctx, userId)
+}
+
+// Verify checks the code of an enrolled user. A code is accepted only once: the counter must be greater than the last accepted one.
+// The counter is updated by an atomic replace, the request which loses the replace is rejected.
+func (s *OtpService) Verify(ctx context.Context, userId string, code string) (bool, error) {
+	service, ok := s.Service.(AtomicPasscodeService)
+	if !ok {
+		return false, ErrNotAtomic
+	}
+	secret, _, err := s.Service.Load(ctx, s.secretKey(userId))
+	if err != nil {
+		return false, err
+	}
+	if len(secret) == 0 {
+		return false, ErrNotEnrolled
+	}
+	counter, ok, err := s.TOTP.Validate(secret, code, s.Now())
+	if err != nil || !ok {
+		return false, err
+	}
+	last, _, err := s.Service.Load(ctx, s.counterKey(userId))
+	if err != nil {
+		return false, err
+	}
+	if len(last) > 0 {
+		if lastCounter, er1 := strconv.ParseUint(last, 10, 64); er1 == nil && counter <= lastCounter {
+			return false, nil
+		}
+	}
+	return service.Replace(ctx, s.counterKey(userId), last, strconv.FormatUint(counter, 10), s.never())
+}
+
+func (s *OtpService) GenerateRecoveryCodes(ctx context.Context, userId string) ([]string, error) {
+	codes := make([]string, s.RecoveryCodes)
+	hashes := make([]string, s.RecoveryCodes)
+	for i := range codes {
+		code, err := randomCode(s.RecoveryLength)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = HashRecoveryCode(code)
+	}
+	if _, err := s.Service.Save(ctx, s.recoveryKey(userId), strings.Join(hashes, ","), s.never()); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Recover checks the recovery code against the stored hashes, and removes it, so that each recovery code can be used only once.
+// The hashes are updated by an atomic replace, the request which loses the replace is rejected.
+func (s *OtpService) Recover(ctx context.Context, userId string, code string) (bool, error) {
+	service, ok := s.Service.(AtomicPasscodeService)
+	if !ok {
+		return false, ErrNotAtomic
+	}
+	stored, _, err := s.Service.Load(ctx, s.recoveryKey(userId))
+	if err != nil {
+		return false, err
+	}
+	if len(stored) == 0 {
+		return false, nil
+	}
+	hashed := HashRecoveryCode(code)
+	hashes := strings.Split(stored, ",")
+	for i, h := range hashes {
+		if EqualCode(h, hashed) {
+			remain := append(hashes[:i:i], hashes[i+1:]...)
+			return service.Replace(ctx, s.recoveryKey(userId), stored, strings.Join(remain, ","), s.never())
+		}
+	}
+	return false, nil
+}
+
+func (s *OtpService) Disable(ctx context.Context, userId string) error {
+	keys := []string{s.secretKey(userId), s.pendingKey(userId), s.counterKey(userId), s.recoveryKey(userId)}
+	for _, key := range keys {
+		if _, err := s.Service.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func HashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToUpper(strings.ReplaceAll(code, "-", ""))))
+	return hex.EncodeToString(sum[:])
+}
+
+const recoveryAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func randomCode(length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = recoveryAlphabet[int(b[i])%len(recoveryAlphabet)]
+	}
+	return string(b), nil
+}
@@ -2,11 +2,21 @@ package passcode
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+var ErrNotAtomic = errors.New("passcode service does not support atomic replace")
+
 type PasscodeService interface {
 	Save(ctx context.Context, id string, passcode string, expireAt time.Time) (int64, error)
 	Load(ctx context.Context, id string) (string, time.Time, error)
 	Delete(ctx context.Context, id string) (int64, error)
 }
+
+// AtomicPasscodeService replaces the passcode only if it is still old, an empty old means the id does not exist.
+// It returns false if another request changed the passcode first.
+type AtomicPasscodeService interface {
+	PasscodeService
+	Replace(ctx context.Context, id string, old string, passcode string, expireAt time.Time) (bool, error)
+}
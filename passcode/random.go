@@ -1,9 +1,8 @@
 package passcode
 
 import (
-	"math"
-	"math/rand"
-	"strconv"
+	"crypto/rand"
+	"math/big"
 )
 
 func times(str string, n int) (out string) {
@@ -19,7 +18,21 @@ func padLeft(str string, length int, pad string) string {
 	return times(pad, length-len(str)) + str
 }
 
+// Generate generates a numeric code by crypto/rand, it panics if crypto/rand fails. Use GenerateCode to get the error.
 func Generate(length int) string {
-	max := int(math.Pow(float64(10), float64(length))) - 1
-	return padLeft(strconv.Itoa(rand.Intn(max)), length, "0")
+	code, err := GenerateCode(length)
+	if err != nil {
+		panic(err)
+	}
+	return code
+}
+
+// GenerateCode generates a numeric code by crypto/rand, all values from 0 to 10^length-1 have the same probability.
+func GenerateCode(length int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(length)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return padLeft(n.String(), length, "0"), nil
 }
@@ -0,0 +1,132 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type PasscodeService struct {
+	DB        *sql.DB
+	Table     string
+	Id        string
+	Passcode  string
+	ExpiredAt string
+	Driver    string
+}
+
+func NewPasscodeService(db *sql.DB, table string, options ...string) *PasscodeService {
+	id := "id"
+	if len(options) > 0 && len(options[0]) > 0 {
+		id = options[0]
+	}
+	passcode := "passcode"
+	if len(options) > 1 && len(options[1]) > 0 {
+		passcode = options[1]
+	}
+	expiredAt := "expiredat"
+	if len(options) > 2 && len(options[2]) > 0 {
+		expiredAt = options[2]
+	}
+	return &PasscodeService{DB: db, Table: table, Id: id, Passcode: passcode, ExpiredAt: expiredAt, Driver: getDriver(db)}
+}
+
+func (s *PasscodeService) Save(ctx context.Context, id string, passcode string, expireAt time.Time) (int64, error) {
+	query := replaceQueryArgs(s.Driver, fmt.Sprintf("update %s set %s = ?, %s = ? where %s = ?", s.Table, s.Passcode, s.ExpiredAt, s.Id))
+	res, err := s.DB.ExecContext(ctx, query, passcode, expireAt, id)
+	if err != nil {
+		return -1, err
+	}
+	if n, er1 := res.RowsAffected(); er1 == nil && n > 0 {
+		return n, nil
+	}
+	query = replaceQueryArgs(s.Driver, fmt.Sprintf("insert into %s (%s, %s, %s) values (?, ?, ?)", s.Table, s.Id, s.Passcode, s.ExpiredAt))
+	res, err = s.DB.ExecContext(ctx, query, id, passcode, expireAt)
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
+func (s *PasscodeService) Replace(ctx context.Context, id string, old string, passcode string, expireAt time.Time) (bool, error) {
+	if len(old) == 0 {
+		query := replaceQueryArgs(s.Driver, fmt.Sprintf("insert into %s (%s, %s, %s) values (?, ?, ?)", s.Table, s.Id, s.Passcode, s.ExpiredAt))
+		_, err := s.DB.ExecContext(ctx, query, id, passcode, expireAt)
+		if err != nil {
+			if current, _, er1 := s.Load(ctx, id); er1 == nil && len(current) > 0 {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+	query := replaceQueryArgs(s.Driver, fmt.Sprintf("update %s set %s = ?, %s = ? where %s = ? and %s = ?", s.Table, s.Passcode, s.ExpiredAt, s.Id, s.Passcode))
+	res, err := s.DB.ExecContext(ctx, query, passcode, expireAt, id, old)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+func (s *PasscodeService) Load(ctx context.Context, id string) (string, time.Time, error) {
+	query := replaceQueryArgs(s.Driver, fmt.Sprintf("select %s, %s from %s where %s = ?", s.Passcode, s.ExpiredAt, s.Table, s.Id))
+	var passcode string
+	var expiredAt time.Time
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&passcode, &expiredAt)
+	if err == sql.ErrNoRows {
+		return "", time.Now(), nil
+	}
+	if err != nil {
+		return "", time.Now(), err
+	}
+	return passcode, expiredAt, nil
+}
+func (s *PasscodeService) Delete(ctx context.Context, id string) (int64, error) {
+	query := replaceQueryArgs(s.Driver, fmt.Sprintf("delete from %s where %s = ?", s.Table, s.Id))
+	res, err := s.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
+
+func replaceQueryArgs(driver string, query string) string {
+	var x string
+	switch driver {
+	case "oracle":
+		x = ":val"
+	case "postgres":
+		x = "$"
+	case "mssql":
+		x = "@p"
+	default:
+		return query
+	}
+	i := 1
+	for strings.Contains(query, "?") {
+		query = strings.Replace(query, "?", x+fmt.Sprintf("%v", i), 1)
+		i = i + 1
+	}
+	return query
+}
+func getDriver(db *sql.DB) string {
+	if db == nil {
+		return "no support"
+	}
+	switch reflect.TypeOf(db.Driver()).String() {
+	case "*pq.Driver":
+		return "postgres"
+	case "*godror.drv":
+		return "oracle"
+	case "*mysql.MySQLDriver":
+		return "mysql"
+	case "*mssql.Driver":
+		return "mssql"
+	case "*sqlite3.SQLiteDriver":
+		return "sqlite3"
+	default:
+		return "no support"
+	}
+}
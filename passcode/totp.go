@@ -0,0 +1,101 @@
+package passcode
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TOTP struct {
+	Issuer    string
+	Digits    int
+	Period    int64
+	Skew      int
+	Algorithm string
+}
+
+func NewTOTP(issuer string, options ...int) *TOTP {
+	digits := 6
+	if len(options) > 0 && options[0] > 0 {
+		digits = options[0]
+	}
+	var period int64 = 30
+	if len(options) > 1 && options[1] > 0 {
+		period = int64(options[1])
+	}
+	skew := 1
+	if len(options) > 2 && options[2] >= 0 {
+		skew = options[2]
+	}
+	return &TOTP{Issuer: issuer, Digits: digits, Period: period, Skew: skew, Algorithm: SHA1}
+}
+
+func GenerateSecret(size int) (string, error) {
+	if size <= 0 {
+		size = 20
+	}
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretEncoding.EncodeToString(b), nil
+}
+func DecodeSecret(secret string) ([]byte, error) {
+	return secretEncoding.DecodeString(strings.TrimRight(strings.ToUpper(strings.ReplaceAll(secret, " ", "")), "="))
+}
+
+func (t *TOTP) Counter(now time.Time) uint64 {
+	return uint64(now.Unix() / t.Period)
+}
+func (t *TOTP) Generate(secret string, now time.Time) (string, error) {
+	key, err := DecodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return GenerateHOTP(key, t.Counter(now), t.Digits, t.Algorithm), nil
+}
+
+// Validate checks the code of RFC 6238 within Skew periods before and after now, and returns the matched counter to prevent replay.
+func (t *TOTP) Validate(secret string, code string, now time.Time) (uint64, bool, error) {
+	key, err := DecodeSecret(secret)
+	if err != nil {
+		return 0, false, err
+	}
+	counter := t.Counter(now)
+	for i := -t.Skew; i <= t.Skew; i++ {
+		c := int64(counter) + int64(i)
+		if c < 0 {
+			continue
+		}
+		if EqualCode(GenerateHOTP(key, uint64(c), t.Digits, t.Algorithm), code) {
+			return uint64(c), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// URI builds the otpauth URI, which is used as the payload of the QR code for authenticator apps.
+func (t *TOTP) URI(secret string, account string) string {
+	label := url.PathEscape(account)
+	if len(t.Issuer) > 0 {
+		label = url.PathEscape(t.Issuer) + ":" + label
+	}
+	params := url.Values{}
+	params.Set("secret", secret)
+	if len(t.Issuer) > 0 {
+		params.Set("issuer", t.Issuer)
+	}
+	algorithm := strings.ToUpper(t.Algorithm)
+	if len(algorithm) == 0 {
+		algorithm = SHA1
+	}
+	params.Set("algorithm", algorithm)
+	params.Set("digits", strconv.Itoa(t.Digits))
+	params.Set("period", strconv.FormatInt(t.Period, 10))
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
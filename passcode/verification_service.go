@@ -0,0 +1,171 @@
+package passcode
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrLocked          = errors.New("too many failed attempts")
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+const maxRetries = 5
+
+type VerificationService struct {
+	Service      PasscodeService
+	Sender       Sender
+	Length       int
+	Expires      time.Duration
+	MaxAttempts  int
+	LockTime     time.Duration
+	ResendLimit  int
+	ResendWindow time.Duration
+	Now          func() time.Time
+}
+
+func NewVerificationService(service PasscodeService, sender Sender, length int, expires time.Duration, options ...int) *VerificationService {
+	maxAttempts := 5
+	if len(options) > 0 && options[0] > 0 {
+		maxAttempts = options[0]
+	}
+	resendLimit := 3
+	if len(options) > 1 && options[1] > 0 {
+		resendLimit = options[1]
+	}
+	if length <= 0 {
+		length = 6
+	}
+	return &VerificationService{Service: service, Sender: sender, Length: length, Expires: expires, MaxAttempts: maxAttempts, LockTime: 15 * time.Minute, ResendLimit: resendLimit, ResendWindow: time.Hour, Now: time.Now}
+}
+
+func attemptsKey(id string) string {
+	return "attempts:" + id
+}
+func resendKey(to string) string {
+	return "resend:" + to
+}
+
+// Send generates a new code by crypto/rand, stores its salted hash and sends the plain code to the recipient.
+func (s *VerificationService) Send(ctx context.Context, id string, to string, params interface{}) error {
+	locked, err := s.locked(ctx, id)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrLocked
+	}
+	if s.ResendLimit > 0 {
+		_, ok, err := s.increase(ctx, resendKey(to), s.ResendLimit, s.Now().Add(s.ResendWindow), true)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrTooManyRequests
+		}
+	}
+	code, err := GenerateCode(s.Length)
+	if err != nil {
+		return err
+	}
+	hashed, err := HashPasscode(code)
+	if err != nil {
+		return err
+	}
+	expireAt := s.Now().Add(s.Expires)
+	if _, err = s.Service.Save(ctx, id, hashed, expireAt); err != nil {
+		return err
+	}
+	if s.Sender == nil {
+		return nil
+	}
+	return s.Sender.Send(ctx, to, code, expireAt, params)
+}
+
+// Verify compares the code in constant time. The code is invalidated on success, and after MaxAttempts failures the id is locked for LockTime.
+// The attempt is counted before the comparison by an atomic replace, so the Service must implement AtomicPasscodeService.
+func (s *VerificationService) Verify(ctx context.Context, id string, code string) (bool, error) {
+	attempts, ok, err := s.increase(ctx, attemptsKey(id), s.MaxAttempts, s.Now().Add(s.LockTime), false)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, ErrLocked
+	}
+	hashed, expiredAt, err := s.Service.Load(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if len(hashed) == 0 || s.Now().After(expiredAt) {
+		return false, nil
+	}
+	if ComparePasscode(code, hashed) {
+		n, err := s.Service.Delete(ctx, id)
+		if err != nil || n <= 0 {
+			return false, err
+		}
+		_, err = s.Service.Delete(ctx, attemptsKey(id))
+		return true, err
+	}
+	if attempts >= s.MaxAttempts {
+		if _, err = s.Service.Delete(ctx, id); err != nil {
+			return false, err
+		}
+		return false, ErrLocked
+	}
+	return false, nil
+}
+
+func (s *VerificationService) locked(ctx context.Context, id string) (bool, error) {
+	attempts, _, err := s.count(ctx, attemptsKey(id))
+	return attempts >= s.MaxAttempts, err
+}
+func (s *VerificationService) count(ctx context.Context, key string) (int, time.Time, error) {
+	v, expiredAt, err := s.Service.Load(ctx, key)
+	if err != nil || len(v) == 0 || s.Now().After(expiredAt) {
+		return 0, expiredAt, err
+	}
+	count, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, expiredAt, nil
+	}
+	return count, expiredAt, nil
+}
+
+// increase adds 1 to the counter by compare and swap, it returns false without changing the counter if the counter reached limit.
+// If window is true, the counter keeps its expiry until it expires, else the expiry is moved to expireAt.
+func (s *VerificationService) increase(ctx context.Context, key string, limit int, expireAt time.Time, window bool) (int, bool, error) {
+	service, ok := s.Service.(AtomicPasscodeService)
+	if !ok {
+		return 0, false, ErrNotAtomic
+	}
+	for i := 0; i < maxRetries; i++ {
+		v, expiredAt, err := s.Service.Load(ctx, key)
+		if err != nil {
+			return 0, false, err
+		}
+		count := 0
+		exp := expireAt
+		if len(v) > 0 && !s.Now().After(expiredAt) {
+			if count, err = strconv.Atoi(v); err != nil {
+				count = 0
+			}
+			if window {
+				exp = expiredAt
+			}
+		}
+		if count >= limit {
+			return count, false, nil
+		}
+		ok, err = service.Replace(ctx, key, v, strconv.Itoa(count+1), exp)
+		if err != nil {
+			return 0, false, err
+		}
+		if ok {
+			return count + 1, true, nil
+		}
+	}
+	return 0, false, ErrTooManyRequests
+}
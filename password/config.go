@@ -0,0 +1,12 @@
+package password
+
+type PolicyConfig struct {
+	MinLength int    `yaml:"min_length" mapstructure:"min_length" json:"minLength,omitempty" gorm:"column:minlength" bson:"minLength,omitempty" dynamodbav:"minLength,omitempty" firestore:"minLength,omitempty"`
+	MaxLength int    `yaml:"max_length" mapstructure:"max_length" json:"maxLength,omitempty" gorm:"column:maxlength" bson:"maxLength,omitempty" dynamodbav:"maxLength,omitempty" firestore:"maxLength,omitempty"`
+	Uppercase int    `yaml:"uppercase" mapstructure:"uppercase" json:"uppercase,omitempty" gorm:"column:uppercase" bson:"uppercase,omitempty" dynamodbav:"uppercase,omitempty" firestore:"uppercase,omitempty"`
+	Lowercase int    `yaml:"lowercase" mapstructure:"lowercase" json:"lowercase,omitempty" gorm:"column:lowercase" bson:"lowercase,omitempty" dynamodbav:"lowercase,omitempty" firestore:"lowercase,omitempty"`
+	Digit     int    `yaml:"digit" mapstructure:"digit" json:"digit,omitempty" gorm:"column:digit" bson:"digit,omitempty" dynamodbav:"digit,omitempty" firestore:"digit,omitempty"`
+	Special   int    `yaml:"special" mapstructure:"special" json:"special,omitempty" gorm:"column:special" bson:"special,omitempty" dynamodbav:"special,omitempty" firestore:"special,omitempty"`
+	History   int    `yaml:"history" mapstructure:"history" json:"history,omitempty" gorm:"column:history" bson:"history,omitempty" dynamodbav:"history,omitempty" firestore:"history,omitempty"`
+	Breached  string `yaml:"breached" mapstructure:"breached" json:"breached,omitempty" gorm:"column:breached" bson:"breached,omitempty" dynamodbav:"breached,omitempty" firestore:"breached,omitempty"`
+}
@@ -0,0 +1,89 @@
+package password
+
+import (
+	"strings"
+
+	"github.com/core-go/core/crypto"
+)
+
+const (
+	Argon2id = "argon2id"
+	Scrypt   = "scrypt"
+	BCrypt   = "bcrypt"
+)
+
+type Rehasher interface {
+	NeedsRehash(hashed string) bool
+}
+
+// Hasher hashes new passwords by Current, and verifies old hashes by the comparator of their algorithm, so that login flows can upgrade the stored hash.
+type Hasher struct {
+	Algorithm   string
+	Current     crypto.StringComparator
+	Comparators map[string]crypto.StringComparator
+}
+
+func NewHasher(algorithm string, current crypto.StringComparator, comparators map[string]crypto.StringComparator) *Hasher {
+	if comparators == nil {
+		comparators = make(map[string]crypto.StringComparator)
+	}
+	comparators[algorithm] = current
+	return &Hasher{Algorithm: algorithm, Current: current, Comparators: comparators}
+}
+func NewDefaultHasher() *Hasher {
+	comparators := map[string]crypto.StringComparator{
+		Scrypt: crypto.NewScryptStringComparator(),
+		BCrypt: crypto.NewStringComparator(),
+	}
+	return NewHasher(Argon2id, crypto.NewArgon2StringComparator(), comparators)
+}
+
+func GetAlgorithm(hashed string) string {
+	switch {
+	case strings.HasPrefix(hashed, "$argon2id$"):
+		return Argon2id
+	case strings.HasPrefix(hashed, "$scrypt$"):
+		return Scrypt
+	case strings.HasPrefix(hashed, "$2a$"), strings.HasPrefix(hashed, "$2b$"), strings.HasPrefix(hashed, "$2y$"):
+		return BCrypt
+	}
+	return ""
+}
+
+func (h *Hasher) Hash(plaintext string) (string, error) {
+	return h.Current.Hash(plaintext)
+}
+func (h *Hasher) Compare(plaintext string, hashed string) (bool, error) {
+	comparator, ok := h.Comparators[GetAlgorithm(hashed)]
+	if !ok {
+		return false, crypto.ErrInvalidHash
+	}
+	return comparator.Compare(plaintext, hashed)
+}
+
+// NeedsRehash returns true if the hash is not created by the current algorithm, or is weaker than its current parameters.
+func (h *Hasher) NeedsRehash(hashed string) bool {
+	if GetAlgorithm(hashed) != h.Algorithm {
+		return true
+	}
+	if r, ok := h.Current.(Rehasher); ok {
+		return r.NeedsRehash(hashed)
+	}
+	return false
+}
+
+// Verify compares the password, and returns the new hash if the stored hash needs an upgrade; the caller should save it after a successful login.
+func (h *Hasher) Verify(plaintext string, hashed string) (bool, string, error) {
+	ok, err := h.Compare(plaintext, hashed)
+	if err != nil || !ok {
+		return false, "", err
+	}
+	if !h.NeedsRehash(hashed) {
+		return true, "", nil
+	}
+	newHash, err := h.Hash(plaintext)
+	if err != nil {
+		return true, "", err
+	}
+	return true, newHash, nil
+}
@@ -0,0 +1,154 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/core-go/core"
+	"github.com/core-go/core/crypto"
+)
+
+const (
+	CodeMinLength = "minlength"
+	CodeMaxLength = "maxlength"
+	CodeUppercase = "uppercase"
+	CodeLowercase = "lowercase"
+	CodeDigit     = "digit"
+	CodeSpecial   = "special"
+	CodeBreached  = "breached"
+	CodeHistory   = "history"
+)
+
+type Policy struct {
+	Config     PolicyConfig
+	Comparator crypto.StringComparator
+	Field      string
+	breached   map[string]struct{}
+}
+
+// NewPolicy creates the policy, and loads the breached passwords from Config.Breached, one plain password or SHA-1 hex per line.
+func NewPolicy(c PolicyConfig, comparator crypto.StringComparator, options ...string) (*Policy, error) {
+	field := "password"
+	if len(options) > 0 && len(options[0]) > 0 {
+		field = options[0]
+	}
+	p := &Policy{Config: c, Comparator: comparator, Field: field}
+	if len(c.Breached) > 0 {
+		breached, err := LoadBreached(c.Breached)
+		if err != nil {
+			return nil, err
+		}
+		p.breached = breached
+	}
+	return p, nil
+}
+
+func LoadBreached(file string) (map[string]struct{}, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	breached := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, ":"); i == 40 {
+			line = line[:i]
+		}
+		if isSha1(line) {
+			breached[strings.ToUpper(line)] = struct{}{}
+		} else {
+			breached[sha1Hex(line)] = struct{}{}
+		}
+	}
+	return breached, scanner.Err()
+}
+
+func (p *Policy) IsBreached(password string) bool {
+	if p.breached == nil {
+		return false
+	}
+	_, ok := p.breached[sha1Hex(password)]
+	return ok
+}
+
+// Validate checks the password against the policy. history contains the last hashes of the user, the newest first.
+func (p *Policy) Validate(password string, history []string) ([]core.ErrorMessage, error) {
+	errs := make([]core.ErrorMessage, 0)
+	c := p.Config
+	length := len([]rune(password))
+	if c.MinLength > 0 && length < c.MinLength {
+		errs = append(errs, p.error(CodeMinLength, c.MinLength))
+	}
+	if c.MaxLength > 0 && length > c.MaxLength {
+		errs = append(errs, p.error(CodeMaxLength, c.MaxLength))
+	}
+	var upper, lower, digit, special int
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		case unicode.IsDigit(r):
+			digit++
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			special++
+		}
+	}
+	if upper < c.Uppercase {
+		errs = append(errs, p.error(CodeUppercase, c.Uppercase))
+	}
+	if lower < c.Lowercase {
+		errs = append(errs, p.error(CodeLowercase, c.Lowercase))
+	}
+	if digit < c.Digit {
+		errs = append(errs, p.error(CodeDigit, c.Digit))
+	}
+	if special < c.Special {
+		errs = append(errs, p.error(CodeSpecial, c.Special))
+	}
+	if p.IsBreached(password) {
+		errs = append(errs, core.ErrorMessage{Field: p.Field, Code: CodeBreached})
+	}
+	if c.History > 0 && p.Comparator != nil {
+		for i, hashed := range history {
+			if i >= c.History {
+				break
+			}
+			ok, err := p.Comparator.Compare(password, hashed)
+			if err != nil {
+				return errs, err
+			}
+			if ok {
+				errs = append(errs, p.error(CodeHistory, c.History))
+				break
+			}
+		}
+	}
+	return errs, nil
+}
+
+func (p *Policy) error(code string, param int) core.ErrorMessage {
+	return core.ErrorMessage{Field: p.Field, Code: code, Param: strconv.Itoa(param)}
+}
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+func isSha1(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
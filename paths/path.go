@@ -0,0 +1,27 @@
+package paths
+
+import "strings"
+
+// MatchPath supports exact paths, a trailing "*" for prefixes and ":param" or "{param}" segments.
+func MatchPath(pattern string, path string) bool {
+	if pattern == path {
+		return true
+	}
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(path, pattern[:len(pattern)-1])
+	}
+	ps := strings.Split(strings.Trim(pattern, "/"), "/")
+	vs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(ps) != len(vs) {
+		return false
+	}
+	for i, p := range ps {
+		if strings.HasPrefix(p, ":") || (strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}")) {
+			continue
+		}
+		if p != vs[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+)
+
+// Check limits the api key by the id of the authenticated key in the context, which is set by the api key checker, so the limiter must run after the checker.
+// The api key header is not used, because the key is not verified yet, and a client could get a new bucket by a random key.
+func Check(ctx context.Context, limiter Limiter, c Config, r *http.Request, ip string) (*Result, error) {
+	route := Resolve(c, r.Method, r.URL.Path)
+	if route.Skip || route.Rule.Limit <= 0 || route.Rule.Window <= 0 {
+		return nil, nil
+	}
+	values := map[string]string{KeyIp: ip, KeyApiKey: FromContext(ctx, c.Authorization, c.ApiKeyId), KeyUser: FromContext(ctx, c.Authorization, c.UserId)}
+	key := BuildKey(c.Prefix, route, values)
+	return limiter.Allow(ctx, key, route.Rule)
+}
+
+func FromContext(ctx context.Context, authorization string, key string) string {
+	var u interface{}
+	if len(authorization) > 0 {
+		token := ctx.Value(authorization)
+		if token != nil {
+			if authorizationToken, ok := token.(map[string]interface{}); ok {
+				u = authorizationToken[key]
+			}
+		}
+	} else {
+		u = ctx.Value(key)
+	}
+	if u != nil {
+		if v, ok := u.(string); ok {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import "time"
+
+const (
+	TokenBucket   = "token_bucket"
+	SlidingWindow = "sliding_window"
+
+	KeyIp     = "ip"
+	KeyUser   = "user"
+	KeyApiKey = "api_key"
+	KeyRoute  = "route"
+)
+
+type Config struct {
+	Algorithm     string        `yaml:"algorithm" mapstructure:"algorithm" json:"algorithm,omitempty" gorm:"column:algorithm" bson:"algorithm,omitempty" dynamodbav:"algorithm,omitempty" firestore:"algorithm,omitempty"`
+	Limit         int64         `yaml:"limit" mapstructure:"limit" json:"limit,omitempty" gorm:"column:limit" bson:"limit,omitempty" dynamodbav:"limit,omitempty" firestore:"limit,omitempty"`
+	Window        time.Duration `yaml:"window" mapstructure:"window" json:"window,omitempty" gorm:"column:window" bson:"window,omitempty" dynamodbav:"window,omitempty" firestore:"window,omitempty"`
+	Burst         int64         `yaml:"burst" mapstructure:"burst" json:"burst,omitempty" gorm:"column:burst" bson:"burst,omitempty" dynamodbav:"burst,omitempty" firestore:"burst,omitempty"`
+	Key           string        `yaml:"key" mapstructure:"key" json:"key,omitempty" gorm:"column:key" bson:"key,omitempty" dynamodbav:"key,omitempty" firestore:"key,omitempty"`
+	Prefix        string        `yaml:"prefix" mapstructure:"prefix" json:"prefix,omitempty" gorm:"column:prefix" bson:"prefix,omitempty" dynamodbav:"prefix,omitempty" firestore:"prefix,omitempty"`
+	ApiKeyId      string        `yaml:"api_key_id" mapstructure:"api_key_id" json:"apiKeyId,omitempty" gorm:"column:apikeyid" bson:"apiKeyId,omitempty" dynamodbav:"apiKeyId,omitempty" firestore:"apiKeyId,omitempty"`
+	UserId        string        `yaml:"user_id" mapstructure:"user_id" json:"userId,omitempty" gorm:"column:userid" bson:"userId,omitempty" dynamodbav:"userId,omitempty" firestore:"userId,omitempty"`
+	Authorization string        `yaml:"authorization" mapstructure:"authorization" json:"authorization,omitempty" gorm:"column:authorization" bson:"authorization,omitempty" dynamodbav:"authorization,omitempty" firestore:"authorization,omitempty"`
+	Skips         string        `yaml:"skips" mapstructure:"skips" json:"skips,omitempty" gorm:"column:skips" bson:"skips,omitempty" dynamodbav:"skips,omitempty" firestore:"skips,omitempty"`
+	Routes        []RouteConfig `yaml:"routes" mapstructure:"routes" json:"routes,omitempty" gorm:"column:routes" bson:"routes,omitempty" dynamodbav:"routes,omitempty" firestore:"routes,omitempty"`
+}
+
+type RouteConfig struct {
+	Method    string        `yaml:"method" mapstructure:"method" json:"method,omitempty" gorm:"column:method" bson:"method,omitempty" dynamodbav:"method,omitempty" firestore:"method,omitempty"`
+	Path      string        `yaml:"path" mapstructure:"path" json:"path,omitempty" gorm:"column:path" bson:"path,omitempty" dynamodbav:"path,omitempty" firestore:"path,omitempty"`
+	Algorithm string        `yaml:"algorithm" mapstructure:"algorithm" json:"algorithm,omitempty" gorm:"column:algorithm" bson:"algorithm,omitempty" dynamodbav:"algorithm,omitempty" firestore:"algorithm,omitempty"`
+	Limit     int64         `yaml:"limit" mapstructure:"limit" json:"limit,omitempty" gorm:"column:limit" bson:"limit,omitempty" dynamodbav:"limit,omitempty" firestore:"limit,omitempty"`
+	Window    time.Duration `yaml:"window" mapstructure:"window" json:"window,omitempty" gorm:"column:window" bson:"window,omitempty" dynamodbav:"window,omitempty" firestore:"window,omitempty"`
+	Burst     int64         `yaml:"burst" mapstructure:"burst" json:"burst,omitempty" gorm:"column:burst" bson:"burst,omitempty" dynamodbav:"burst,omitempty" firestore:"burst,omitempty"`
+	Key       string        `yaml:"key" mapstructure:"key" json:"key,omitempty" gorm:"column:key" bson:"key,omitempty" dynamodbav:"key,omitempty" firestore:"key,omitempty"`
+	Skip      bool          `yaml:"skip" mapstructure:"skip" json:"skip,omitempty" gorm:"column:skip" bson:"skip,omitempty" dynamodbav:"skip,omitempty" firestore:"skip,omitempty"`
+}
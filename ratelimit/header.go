@@ -0,0 +1,30 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+func SetHeaders(h http.Header, res *Result) {
+	h.Set(HeaderLimit, strconv.FormatInt(res.Limit, 10))
+	h.Set(HeaderRemaining, strconv.FormatInt(res.Remaining, 10))
+	h.Set(HeaderReset, strconv.FormatInt(seconds(res.Reset), 10))
+	if !res.Allowed {
+		h.Set(HeaderRetryAfter, strconv.FormatInt(seconds(res.RetryAfter), 10))
+	}
+}
+func seconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+type Rule struct {
+	Algorithm string
+	Limit     int64
+	Window    time.Duration
+	Burst     int64
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, rule Rule) (*Result, error)
+}
+
+func (r Rule) Capacity() int64 {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return r.Limit
+}
+
+// Interval returns the time to get one token back.
+func (r Rule) Interval() time.Duration {
+	if r.Limit <= 0 {
+		return r.Window
+	}
+	return r.Window / time.Duration(r.Limit)
+}
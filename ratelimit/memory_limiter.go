@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Duration
+}
+
+type window struct {
+	start    time.Time
+	size     time.Duration
+	current  int64
+	previous int64
+}
+
+type MemoryLimiter struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	windows   map[string]*window
+	ttl       time.Duration
+	lastClean time.Time
+	now       func() time.Time
+}
+
+func NewMemoryLimiter(options ...time.Duration) *MemoryLimiter {
+	ttl := 10 * time.Minute
+	if len(options) > 0 && options[0] > 0 {
+		ttl = options[0]
+	}
+	return &MemoryLimiter{buckets: make(map[string]*bucket), windows: make(map[string]*window), ttl: ttl, lastClean: time.Now(), now: time.Now}
+}
+
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, rule Rule) (*Result, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := l.now()
+	l.clean(now)
+	if rule.Algorithm == SlidingWindow {
+		return l.slidingWindow(key, rule, now), nil
+	}
+	return l.tokenBucket(key, rule, now), nil
+}
+
+func (l *MemoryLimiter) tokenBucket(key string, rule Rule, now time.Time) *Result {
+	capacity := float64(rule.Capacity())
+	rate := float64(rule.Limit) / rule.Window.Seconds()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now, full: time.Duration(capacity / rate * float64(time.Second))}
+		l.buckets[key] = b
+	} else {
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+		b.last = now
+	}
+	allowed := false
+	if b.tokens >= 1 {
+		b.tokens = b.tokens - 1
+		allowed = true
+	}
+	return NewTokenBucketResult(rule, allowed, b.tokens)
+}
+
+func (l *MemoryLimiter) slidingWindow(key string, rule Rule, now time.Time) *Result {
+	start := now.Truncate(rule.Window)
+	w, ok := l.windows[key]
+	if !ok {
+		w = &window{start: start, size: rule.Window}
+		l.windows[key] = w
+	} else if !w.start.Equal(start) {
+		if start.Sub(w.start) == rule.Window {
+			w.previous = w.current
+		} else {
+			w.previous = 0
+		}
+		w.current = 0
+		w.start = start
+	}
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(rule.Window)
+	count := float64(w.previous)*weight + float64(w.current)
+	allowed := false
+	if count+1 <= float64(rule.Limit) {
+		w.current++
+		count++
+		allowed = true
+	}
+	return NewSlidingWindowResult(rule, allowed, count, elapsed, w.previous, w.current)
+}
+
+func (l *MemoryLimiter) clean(now time.Time) {
+	if now.Sub(l.lastClean) < l.ttl {
+		return
+	}
+	l.lastClean = now
+	for k, b := range l.buckets {
+		if now.Sub(b.last) > l.ttl && now.Sub(b.last) > b.full {
+			delete(l.buckets, k)
+		}
+	}
+	for k, w := range l.windows {
+		if now.Sub(w.start) > l.ttl && now.Sub(w.start) > 2*w.size {
+			delete(l.windows, k)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+
+	"github.com/core-go/core/ratelimit"
+)
+
+type RedisLimiter struct {
+	Pool          *redis.Pool
+	tokenBucket   *redis.Script
+	slidingWindow *redis.Script
+}
+
+func NewRedisLimiter(pool *redis.Pool) *RedisLimiter {
+	return &RedisLimiter{Pool: pool, tokenBucket: redis.NewScript(1, ratelimit.TokenBucketScript), slidingWindow: redis.NewScript(1, ratelimit.SlidingWindowScript)}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, rule ratelimit.Rule) (*ratelimit.Result, error) {
+	conn, err := l.Pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if rule.Algorithm == ratelimit.SlidingWindow {
+		values, err := redis.Values(l.slidingWindow.Do(conn, key, rule.Limit, rule.Window.Milliseconds()))
+		if err != nil {
+			return nil, err
+		}
+		if len(values) < 5 {
+			return nil, fmt.Errorf("unexpected result of sliding window script: %v", values)
+		}
+		allowed, _ := redis.Int64(values[0], nil)
+		count, err := redis.Float64(values[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		elapsed, _ := redis.Int64(values[2], nil)
+		previous, _ := redis.Int64(values[3], nil)
+		current, _ := redis.Int64(values[4], nil)
+		return ratelimit.NewSlidingWindowResult(rule, allowed == 1, count, time.Duration(elapsed)*time.Millisecond, previous, current), nil
+	}
+	rate := float64(rule.Limit) / float64(rule.Window.Milliseconds())
+	ttl := rule.Interval() * time.Duration(rule.Capacity())
+	values, err := redis.Values(l.tokenBucket.Do(conn, key, rule.Capacity(), strconv.FormatFloat(rate, 'f', -1, 64), ttl.Milliseconds()+1000))
+	if err != nil {
+		return nil, err
+	}
+	if len(values) < 2 {
+		return nil, fmt.Errorf("unexpected result of token bucket script: %v", values)
+	}
+	allowed, _ := redis.Int64(values[0], nil)
+	tokens, err := redis.Float64(values[1], nil)
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.NewTokenBucketResult(rule, allowed == 1, tokens), nil
+}
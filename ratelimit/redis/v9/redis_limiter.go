@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/core-go/core/ratelimit"
+)
+
+type RedisLimiter struct {
+	Client        redis.UniversalClient
+	tokenBucket   *redis.Script
+	slidingWindow *redis.Script
+}
+
+func NewRedisLimiter(client redis.UniversalClient) *RedisLimiter {
+	return &RedisLimiter{Client: client, tokenBucket: redis.NewScript(ratelimit.TokenBucketScript), slidingWindow: redis.NewScript(ratelimit.SlidingWindowScript)}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, rule ratelimit.Rule) (*ratelimit.Result, error) {
+	if rule.Algorithm == ratelimit.SlidingWindow {
+		values, err := l.slidingWindow.Run(ctx, l.Client, []string{key}, rule.Limit, rule.Window.Milliseconds()).Slice()
+		if err != nil {
+			return nil, err
+		}
+		if len(values) < 5 {
+			return nil, fmt.Errorf("unexpected result of sliding window script: %v", values)
+		}
+		count, err := toFloat(values[1])
+		if err != nil {
+			return nil, err
+		}
+		elapsed := toInt(values[2])
+		return ratelimit.NewSlidingWindowResult(rule, toInt(values[0]) == 1, count, time.Duration(elapsed)*time.Millisecond, toInt(values[3]), toInt(values[4])), nil
+	}
+	rate := float64(rule.Limit) / float64(rule.Window.Milliseconds())
+	ttl := rule.Interval() * time.Duration(rule.Capacity())
+	values, err := l.tokenBucket.Run(ctx, l.Client, []string{key}, rule.Capacity(), strconv.FormatFloat(rate, 'f', -1, 64), ttl.Milliseconds()+1000).Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) < 2 {
+		return nil, fmt.Errorf("unexpected result of token bucket script: %v", values)
+	}
+	tokens, err := toFloat(values[1])
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.NewTokenBucketResult(rule, toInt(values[0]) == 1, tokens), nil
+}
+
+func toInt(v interface{}) int64 {
+	if i, ok := v.(int64); ok {
+		return i
+	}
+	return 0
+}
+func toFloat(v interface{}) (float64, error) {
+	switch f := v.(type) {
+	case string:
+		return strconv.ParseFloat(f, 64)
+	case int64:
+		return float64(f), nil
+	}
+	return 0, fmt.Errorf("unexpected value: %v", v)
+}
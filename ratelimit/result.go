@@ -0,0 +1,39 @@
+package ratelimit
+
+import "time"
+
+func NewTokenBucketResult(rule Rule, allowed bool, tokens float64) *Result {
+	capacity := float64(rule.Capacity())
+	rate := float64(rule.Limit) / rule.Window.Seconds()
+	res := &Result{Allowed: allowed, Limit: rule.Capacity(), Remaining: int64(tokens)}
+	if !allowed {
+		res.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	res.Reset = time.Duration((capacity - tokens) / rate * float64(time.Second))
+	return res
+}
+
+func NewSlidingWindowResult(rule Rule, allowed bool, count float64, elapsed time.Duration, previous int64, current int64) *Result {
+	res := &Result{Allowed: allowed, Limit: rule.Limit, Reset: rule.Window - elapsed}
+	if !allowed {
+		res.RetryAfter = RetryAfter(previous, current, rule, elapsed)
+	}
+	res.Remaining = int64(float64(rule.Limit) - count)
+	if res.Remaining < 0 {
+		res.Remaining = 0
+	}
+	return res
+}
+
+// RetryAfter estimates when the weighted count of the previous window has decayed enough to let one more request in.
+func RetryAfter(previous int64, current int64, rule Rule, elapsed time.Duration) time.Duration {
+	if previous == 0 || current+1 > rule.Limit {
+		return rule.Window - elapsed
+	}
+	need := float64(previous) - float64(rule.Limit-current-1)
+	t := time.Duration(need / float64(previous) * float64(rule.Window))
+	if t <= elapsed {
+		return time.Second
+	}
+	return t - elapsed
+}
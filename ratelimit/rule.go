@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"strings"
+	"time"
+
+	"github.com/core-go/core/paths"
+)
+
+type Route struct {
+	Rule Rule
+	Keys []string
+	Name string
+	Skip bool
+}
+
+func InitConfig(c Config) Config {
+	if len(c.Algorithm) == 0 {
+		c.Algorithm = TokenBucket
+	}
+	if c.Window <= 0 {
+		c.Window = time.Minute
+	}
+	if len(c.Key) == 0 {
+		c.Key = KeyIp
+	}
+	if len(c.Prefix) == 0 {
+		c.Prefix = "ratelimit"
+	}
+	if len(c.ApiKeyId) == 0 {
+		c.ApiKeyId = "apiKeyId"
+	}
+	if len(c.UserId) == 0 {
+		c.UserId = "userId"
+	}
+	return c
+}
+
+func Resolve(c Config, method string, path string) Route {
+	route := Route{Rule: Rule{Algorithm: c.Algorithm, Limit: c.Limit, Window: c.Window, Burst: c.Burst}, Keys: strings.Split(c.Key, ","), Name: method + " " + path}
+	if InSkipList(path, c.Skips) {
+		route.Skip = true
+		return route
+	}
+	for _, rc := range c.Routes {
+		if len(rc.Method) > 0 && !strings.EqualFold(rc.Method, method) {
+			continue
+		}
+		if !MatchPath(rc.Path, path) {
+			continue
+		}
+		route.Skip = rc.Skip
+		route.Name = method + " " + rc.Path
+		if len(rc.Algorithm) > 0 {
+			route.Rule.Algorithm = rc.Algorithm
+		}
+		if rc.Limit > 0 {
+			route.Rule.Limit = rc.Limit
+		}
+		if rc.Window > 0 {
+			route.Rule.Window = rc.Window
+		}
+		if rc.Burst > 0 {
+			route.Rule.Burst = rc.Burst
+		}
+		if len(rc.Key) > 0 {
+			route.Keys = strings.Split(rc.Key, ",")
+		}
+		return route
+	}
+	return route
+}
+
+func MatchPath(pattern string, path string) bool {
+	return paths.MatchPath(pattern, path)
+}
+func InSkipList(path string, skips string) bool {
+	if len(skips) == 0 {
+		return false
+	}
+	for _, s := range strings.Split(skips, ",") {
+		if MatchPath(strings.TrimSpace(s), path) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildKey falls back to the ip if the user id or the api key is missing, so anonymous requests are still limited.
+func BuildKey(prefix string, route Route, values map[string]string) string {
+	parts := []string{prefix}
+	for _, k := range route.Keys {
+		k = strings.TrimSpace(k)
+		if k == KeyRoute {
+			parts = append(parts, route.Name)
+			continue
+		}
+		v := values[k]
+		if len(v) == 0 && k != KeyIp {
+			k = KeyIp
+			v = values[KeyIp]
+		}
+		parts = append(parts, k+"="+v)
+	}
+	return strings.Join(parts, ":")
+}
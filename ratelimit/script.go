@@ -0,0 +1,63 @@
+package ratelimit
+
+// TokenBucketScript is executed atomically by the redis limiters.
+// KEYS[1]: bucket key; ARGV[1]: capacity; ARGV[2]: tokens per millisecond; ARGV[3]: ttl in milliseconds.
+const TokenBucketScript = `
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+  tokens = capacity
+  ts = now
+end
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], ttl)
+return {allowed, tostring(tokens)}
+`
+
+// SlidingWindowScript is executed atomically by the redis limiters.
+// KEYS[1]: window key; ARGV[1]: limit; ARGV[2]: window in milliseconds.
+const SlidingWindowScript = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local start = now - (now % window)
+local data = redis.call('HMGET', KEYS[1], 'start', 'cur', 'prev')
+local s = tonumber(data[1])
+local cur = tonumber(data[2]) or 0
+local prev = tonumber(data[3]) or 0
+if s == nil then
+  cur = 0
+  prev = 0
+elseif s ~= start then
+  if start - s == window then
+    prev = cur
+  else
+    prev = 0
+  end
+  cur = 0
+end
+local elapsed = now - start
+local count = prev * (1 - elapsed / window) + cur
+local allowed = 0
+if count + 1 <= limit then
+  cur = cur + 1
+  count = count + 1
+  allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'start', start, 'cur', cur, 'prev', prev)
+redis.call('PEXPIRE', KEYS[1], window * 2)
+return {allowed, tostring(count), elapsed, prev, cur}
+`
@@ -45,6 +45,10 @@ func (c *RedisAdapter) Remove(ctx context.Context, key string) (bool, error) {
 	return Delete(c.Pool, key)
 }
 
+func (c *RedisAdapter) CompareAndSwap(ctx context.Context, key string, old string, value string, timeToLive time.Duration) (bool, error) {
+	return CompareAndSwap(c.Pool, key, old, value, timeToLive)
+}
+
 func (c *RedisAdapter) Clear(ctx context.Context) error {
 	return Clear(c.Pool)
 }
@@ -264,6 +264,28 @@ func Delete(pool *redis.Pool, key string) (bool, error) {
 	return true, nil
 }
 
+const compareAndSwapScript = `local v = redis.call('GET', KEYS[1])
+if (v == false and ARGV[1] == '') or v == ARGV[1] then
+	if tonumber(ARGV[3]) > 0 then
+		redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+	else
+		redis.call('SET', KEYS[1], ARGV[2])
+	end
+	return 1
+end
+return 0`
+
+// CompareAndSwap sets the key to value only if its current value is old, an empty old means the key does not exist.
+func CompareAndSwap(pool *redis.Pool, key string, old string, value string, timeToLive time.Duration) (bool, error) {
+	conn := pool.Get()
+	defer conn.Close()
+	n, err := redis.Int(redis.NewScript(1, compareAndSwapScript).Do(conn, key, old, value, int64(timeToLive/time.Millisecond)))
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
 func Clear(pool *redis.Pool) error {
 	conn := pool.Get()
 	defer conn.Close()
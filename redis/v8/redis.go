@@ -112,6 +112,26 @@ func SetNX(ctx context.Context, client *redis.Client, key string, value interfac
 	return status.Err()
 }
 
+const compareAndSwapScript = `local v = redis.call('GET', KEYS[1])
+if (v == false and ARGV[1] == '') or v == ARGV[1] then
+	if tonumber(ARGV[3]) > 0 then
+		redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+	else
+		redis.call('SET', KEYS[1], ARGV[2])
+	end
+	return 1
+end
+return 0`
+
+// CompareAndSwap sets the key to value only if its current value is old, an empty old means the key does not exist.
+func CompareAndSwap(ctx context.Context, client *redis.Client, key string, old string, value string, timeToLive time.Duration) (bool, error) {
+	n, err := redis.NewScript(compareAndSwapScript).Run(ctx, client, []string{key}, old, value, timeToLive.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
 func Expire(ctx context.Context, client *redis.Client, key string, timeToLive time.Duration) (bool, error) {
 	return client.Expire(ctx, key, timeToLive).Result()
 }
@@ -48,6 +48,10 @@ func (c *RedisAdapter) Remove(ctx context.Context, key string) (bool, error) {
 	return Delete(ctx, c.Client, key)
 }
 
+func (c *RedisAdapter) CompareAndSwap(ctx context.Context, key string, old string, value string, timeToLive time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.Client, key, old, value, timeToLive)
+}
+
 func (c *RedisAdapter) Clear(ctx context.Context, ) error {
 	return Clear(ctx, c.Client)
 }
@@ -31,6 +31,8 @@ type SearchHandler[T any, F any] struct {
 	JsonMap          map[string]int
 	SecondaryJsonMap map[string]int
 	isPtr            bool
+	RowLevelSecurity *s.RowLevelSecurity
+	Encrypter        s.FieldEncrypter
 }
 
 func NewCSVSearchHandler[T any, F any](search func(context.Context, F, int64, int64) ([]T, int64, error), logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *SearchHandler[T, F] {
@@ -107,6 +109,16 @@ func (c *SearchHandler[T, F]) Search(ctx echo.Context) error {
 	if er0 != nil {
 		return ctx.String(http.StatusBadRequest, "cannot decode filter: "+er0.Error())
 	}
+	if c.RowLevelSecurity != nil {
+		if er := c.RowLevelSecurity.Apply(r.Context(), filter); er != nil {
+			return ctx.String(http.StatusForbidden, er.Error())
+		}
+	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.EncryptFilter(filter); er != nil {
+			return respondError(ctx, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+		}
+	}
 	limit, offset, fs, _, _, er1 := s.Extract(filter)
 	if er1 != nil {
 		return respondError(ctx, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er1, c.WriteLog)
@@ -126,10 +138,17 @@ func (c *SearchHandler[T, F]) Search(ctx echo.Context) error {
 			return ctx.String(http.StatusBadRequest, fmt.Sprintf("cannot cast filter %v", filter))
 		}
 	}
-	models, count, er2 := c.Find(r.Context(), ft, limit, offset)
+	sctx, span := s.StartSpan(r.Context(), c.ResourceName, limit)
+	models, count, er2 := c.Find(sctx, ft, limit, offset)
+	span.FinishWithError(er2)
 	if er2 != nil {
 		return respondError(ctx, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er2, c.WriteLog)
 	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.Decrypt(models); er != nil {
+			return respondError(ctx, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+		}
+	}
 	res := s.BuildResultMap(models, count, c.List, c.Total)
 	if x == -1 {
 		return respond(ctx, http.StatusOK, res, c.WriteLog, c.ResourceName, c.Activity, true, "")
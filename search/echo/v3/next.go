@@ -28,6 +28,8 @@ type NextSearchHandler[T any, F any] struct {
 	JsonMap          map[string]int
 	SecondaryJsonMap map[string]int
 	isPtr            bool
+	RowLevelSecurity *s.RowLevelSecurity
+	Encrypter        s.FieldEncrypter
 }
 
 func NewCSVNextSearchHandler[T any, F any](search func(context.Context, F, int64, string) ([]T, string, error), logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *NextSearchHandler[T, F] {
@@ -102,6 +104,16 @@ func (c *NextSearchHandler[T, F]) Search(ctx echo.Context) error {
 	if er0 != nil {
 		return ctx.String(http.StatusBadRequest, "cannot decode filter: "+er0.Error())
 	}
+	if c.RowLevelSecurity != nil {
+		if er := c.RowLevelSecurity.Apply(r.Context(), filter); er != nil {
+			return ctx.String(http.StatusForbidden, er.Error())
+		}
+	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.EncryptFilter(filter); er != nil {
+			return respondError(ctx, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+		}
+	}
 	limit, _, fs, _, nextPageToken, er1 := s.Extract(filter)
 	if er1 != nil {
 		return respondError(ctx, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er1, c.WriteLog)
@@ -121,10 +133,17 @@ func (c *NextSearchHandler[T, F]) Search(ctx echo.Context) error {
 			return ctx.String(http.StatusBadRequest, fmt.Sprintf("cannot cast filter %v", filter))
 		}
 	}
-	models, next, er2 := c.Find(r.Context(), ft, limit, nextPageToken)
+	sctx, span := s.StartSpan(r.Context(), c.ResourceName, limit)
+	models, next, er2 := c.Find(sctx, ft, limit, nextPageToken)
+	span.FinishWithError(er2)
 	if er2 != nil {
 		return respondError(ctx, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er2, c.WriteLog)
 	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.Decrypt(models); er != nil {
+			return respondError(ctx, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+		}
+	}
 	res := s.BuildNextResultMap(models, next, c.List, c.Next)
 	if x == -1 {
 		return respond(ctx, http.StatusOK, res, c.WriteLog, c.ResourceName, c.Activity, true, "")
@@ -37,6 +37,10 @@ func Build(filter interface{}, resultModelType reflect.Type) map[string]interfac
 	numField := value.NumField()
 	for i := 0; i < numField; i++ {
 		fieldValue := value.Field(i).Interface()
+		if _, isRls := value.Type().Field(i).Tag.Lookup("rls"); isRls {
+			buildRowLevelQuery(query, resultModelType, value.Type().Field(i).Name, value.Field(i))
+			continue
+		}
 		if v, ok := fieldValue.(*search.Filter); ok {
 			if v.Excluding != nil && len(v.Excluding) > 0 {
 				_, _, columnName := getFieldByBson(value.Type(), "_id")
@@ -149,6 +153,27 @@ func Build(filter interface{}, resultModelType reflect.Type) map[string]interfac
 	return query
 }
 
+// buildRowLevelQuery denies all rows by an empty "$in" if the claim is missing.
+func buildRowLevelQuery(query map[string]interface{}, resultModelType reflect.Type, fieldName string, field reflect.Value) {
+	_, columnName := findFieldByName(resultModelType, fieldName)
+	if len(columnName) == 0 {
+		return
+	}
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			query[columnName] = map[string]interface{}{"$in": []string{}}
+			return
+		}
+		field = field.Elem()
+	}
+	if field.Kind() == reflect.Slice && field.Len() > 0 {
+		query[columnName] = map[string]interface{}{"$in": field.Interface()}
+	} else if field.Kind() == reflect.String && field.Len() > 0 {
+		query[columnName] = field.Interface()
+	} else {
+		query[columnName] = map[string]interface{}{"$in": []string{}}
+	}
+}
 func findFieldByName(modelType reflect.Type, fieldName string) (index int, jsonTagName string) {
 	numField := modelType.NumField()
 	for index := 0; index < numField; index++ {
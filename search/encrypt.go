@@ -0,0 +1,7 @@
+package search
+
+// FieldEncrypter converts the filter values of the encrypted fields before searching, and decrypts the results after searching.
+type FieldEncrypter interface {
+	EncryptFilter(filter interface{}) error
+	Decrypt(obj interface{}) error
+}
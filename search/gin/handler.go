@@ -30,6 +30,8 @@ type SearchHandler[T any, F any] struct {
 	JsonMap          map[string]int
 	SecondaryJsonMap map[string]int
 	isPtr            bool
+	RowLevelSecurity *s.RowLevelSecurity
+	Encrypter        s.FieldEncrypter
 }
 
 func NewCSVSearchHandler[T any, F any](search func(context.Context, F, int64, int64) ([]T, int64, error), logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *SearchHandler[T, F] {
@@ -106,6 +108,18 @@ func (c *SearchHandler[T, F]) Search(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "cannot decode filter: "+er0.Error(), http.StatusBadRequest)
 		return
 	}
+	if c.RowLevelSecurity != nil {
+		if er := c.RowLevelSecurity.Apply(r.Context(), filter); er != nil {
+			http.Error(w, er.Error(), http.StatusForbidden)
+			return
+		}
+	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.EncryptFilter(filter); er != nil {
+			s.RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+			return
+		}
+	}
 	limit, offset, fs, _, _, er1 := s.Extract(filter)
 	if er1 != nil {
 		s.RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er1, c.WriteLog)
@@ -128,11 +142,19 @@ func (c *SearchHandler[T, F]) Search(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	models, count, er2 := c.Find(r.Context(), ft, limit, offset)
+	sctx, span := s.StartSpan(r.Context(), c.ResourceName, limit)
+	models, count, er2 := c.Find(sctx, ft, limit, offset)
+	span.FinishWithError(er2)
 	if er2 != nil {
 		s.RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er2, c.WriteLog)
 		return
 	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.Decrypt(models); er != nil {
+			s.RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+			return
+		}
+	}
 	res := s.BuildResultMap(models, count, c.List, c.Total)
 	if x == -1 {
 		s.Respond(w, r, http.StatusOK, res, c.WriteLog, c.ResourceName, c.Activity, true, "")
@@ -27,6 +27,8 @@ type NextSearchHandler[T any, F any] struct {
 	JsonMap          map[string]int
 	SecondaryJsonMap map[string]int
 	isPtr            bool
+	RowLevelSecurity *s.RowLevelSecurity
+	Encrypter        s.FieldEncrypter
 }
 
 func NewCSVNextSearchHandler[T any, F any](search func(context.Context, F, int64, string) ([]T, string, error), logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *NextSearchHandler[T, F] {
@@ -101,6 +103,18 @@ func (c *NextSearchHandler[T, F]) Search(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "cannot decode filter: "+er0.Error(), http.StatusBadRequest)
 		return
 	}
+	if c.RowLevelSecurity != nil {
+		if er := c.RowLevelSecurity.Apply(r.Context(), filter); er != nil {
+			http.Error(w, er.Error(), http.StatusForbidden)
+			return
+		}
+	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.EncryptFilter(filter); er != nil {
+			s.RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+			return
+		}
+	}
 	limit, _, fs, _, nextPageToken, er1 := s.Extract(filter)
 	if er1 != nil {
 		s.RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er1, c.WriteLog)
@@ -123,11 +137,19 @@ func (c *NextSearchHandler[T, F]) Search(w http.ResponseWriter, r *http.Request)
 			return
 		}
 	}
-	models, next, er2 := c.Find(r.Context(), ft, limit, nextPageToken)
+	sctx, span := s.StartSpan(r.Context(), c.ResourceName, limit)
+	models, next, er2 := c.Find(sctx, ft, limit, nextPageToken)
+	span.FinishWithError(er2)
 	if er2 != nil {
 		s.RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er2, c.WriteLog)
 		return
 	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.Decrypt(models); er != nil {
+			s.RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+			return
+		}
+	}
 	res := s.BuildNextResultMap(models, next, c.List, c.Next)
 	if x == -1 {
 		s.Respond(w, r, http.StatusOK, res, c.WriteLog, c.ResourceName, c.Activity, true, "")
@@ -13,6 +13,18 @@ func (c *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "cannot decode filter: "+er0.Error(), http.StatusBadRequest)
 		return
 	}
+	if c.RowLevelSecurity != nil {
+		if er := c.RowLevelSecurity.Apply(r.Context(), filter); er != nil {
+			http.Error(w, er.Error(), http.StatusForbidden)
+			return
+		}
+	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.EncryptFilter(filter); er != nil {
+			RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+			return
+		}
+	}
 	limit, offset, fs, _, _, er1 := Extract(filter)
 	if er1 != nil {
 		RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er1, c.WriteLog)
@@ -20,11 +32,19 @@ func (c *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 	}
 	modelsType := reflect.Zero(reflect.SliceOf(c.modelType)).Type()
 	models := reflect.New(modelsType).Interface()
-	count, er2 := c.Find(r.Context(), filter, models, limit, offset)
+	sctx, span := StartSpan(r.Context(), c.ResourceName, limit)
+	count, er2 := c.Find(sctx, filter, models, limit, offset)
+	span.FinishWithError(er2)
 	if er2 != nil {
 		RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er2, c.WriteLog)
 		return
 	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.Decrypt(models); er != nil {
+			RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+			return
+		}
+	}
 
 	result := BuildResultMap(models, count, c.List, c.Total)
 	if x == -1 {
@@ -47,6 +67,18 @@ func (c *NextSearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "cannot decode filter: "+er0.Error(), http.StatusBadRequest)
 		return
 	}
+	if c.RowLevelSecurity != nil {
+		if er := c.RowLevelSecurity.Apply(r.Context(), filter); er != nil {
+			http.Error(w, er.Error(), http.StatusForbidden)
+			return
+		}
+	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.EncryptFilter(filter); er != nil {
+			RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+			return
+		}
+	}
 	limit, _, fs, _, nextPageToken, er1 := Extract(filter)
 	if er1 != nil {
 		RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er1, c.WriteLog)
@@ -54,11 +86,19 @@ func (c *NextSearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 	}
 	modelsType := reflect.Zero(reflect.SliceOf(c.modelType)).Type()
 	models := reflect.New(modelsType).Interface()
-	nx, er2 := c.Find(r.Context(), filter, models, limit, nextPageToken)
+	sctx, span := StartSpan(r.Context(), c.ResourceName, limit)
+	nx, er2 := c.Find(sctx, filter, models, limit, nextPageToken)
+	span.FinishWithError(er2)
 	if er2 != nil {
 		RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er2, c.WriteLog)
 		return
 	}
+	if c.Encrypter != nil {
+		if er := c.Encrypter.Decrypt(models); er != nil {
+			RespondError(w, r, http.StatusInternalServerError, internalServerError, c.LogError, c.ResourceName, c.Activity, er, c.WriteLog)
+			return
+		}
+	}
 
 	result := BuildNextResultMap(models, nx, c.List, c.Next)
 	if x == -1 {
@@ -137,6 +137,12 @@ func Build(filter interface{}, resultModelType reflect.Type) (bson.D, bson.M) {
 			if !ok {
 				key, _ = tf.Tag.Lookup("q")
 			}
+			if _, isRls := tf.Tag.Lookup("rls"); isRls {
+				key = "="
+			}
+			if _, isEncrypted := tf.Tag.Lookup("encrypt"); isEncrypted {
+				key = "="
+			}
 			if key == "=" {
 				query = append(query, bson.E{Key: bsonName, Value: psv})
 			} else if key == "like" {
@@ -23,6 +23,8 @@ type NextSearchHandler struct {
 	FilterIndex      int
 	JsonMap          map[string]int
 	SecondaryJsonMap map[string]int
+	RowLevelSecurity *RowLevelSecurity
+	Encrypter        FieldEncrypter
 }
 
 func NewCSVNextSearchHandler(search func(context.Context, interface{}, interface{}, int64, string) (string, error), modelType reflect.Type, filterType reflect.Type, logError func(context.Context, string, ...map[string]interface{}), writeLog func(context.Context, string, string, bool, string) error, options ...string) *NextSearchHandler {
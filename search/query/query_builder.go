@@ -206,8 +206,15 @@ func Build(filter interface{}, tableName string, modelType reflect.Type, driver
 			if !ok {
 				key, _ = tf.Tag.Lookup("q")
 			}
+			if _, isRls := tf.Tag.Lookup("rls"); isRls {
+				key = "="
+			}
+			if _, isEncrypted := tf.Tag.Lookup("encrypt"); isEncrypted {
+				key = "="
+			}
 			if key == "=" {
 				rawConditions = append(rawConditions, fmt.Sprintf("%s %s %s", columnName, "=", param))
+				queryValues = append(queryValues, psv)
 			} else {
 				if driver == driverPostgres { // "postgres"
 					rawConditions = append(rawConditions, fmt.Sprintf("%s %s %s", columnName, `ilike`, param))
@@ -0,0 +1,208 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	RlsOwner      = "owner"
+	RlsDepartment = "department"
+	RlsTenant     = "tenant"
+)
+
+var ErrMissingClaim = errors.New("missing claim for row level security")
+
+type RowLevelSecurity struct {
+	Claims        map[string]string
+	Authorization string
+	Bypass        func(ctx context.Context) bool
+}
+
+func NewDefaultRowLevelSecurity(bypass func(context.Context) bool, options ...string) *RowLevelSecurity {
+	claims := map[string]string{RlsOwner: UserId}
+	return NewRowLevelSecurity(claims, bypass, options...)
+}
+func NewRowLevelSecurity(claims map[string]string, bypass func(context.Context) bool, options ...string) *RowLevelSecurity {
+	var authorization string
+	if len(options) > 0 {
+		authorization = options[0]
+	}
+	return &RowLevelSecurity{Claims: claims, Authorization: authorization, Bypass: bypass}
+}
+
+// Apply overwrites the fields tagged with "rls" by the claims of the current user, so that the query builders always filter by them.
+func (s *RowLevelSecurity) Apply(ctx context.Context, obj interface{}) error {
+	if s.Bypass != nil && s.Bypass(ctx) {
+		return nil
+	}
+	value := reflect.Indirect(reflect.ValueOf(obj))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	for _, i := range GetRowLevelIndexes(value.Type()) {
+		name := value.Type().Field(i).Tag.Get("rls")
+		claim := s.claim(ctx, name)
+		if claim == nil {
+			return ErrMissingClaim
+		}
+		if !setRowLevelValue(value.Field(i), claim) {
+			return ErrMissingClaim
+		}
+	}
+	return nil
+}
+
+// ApplyMap applies the claims to obj, and sets the fields tagged with "rls" to the map by the json names, so that a patch cannot change them.
+func (s *RowLevelSecurity) ApplyMap(ctx context.Context, obj interface{}, m map[string]interface{}) error {
+	if s.Bypass != nil && s.Bypass(ctx) {
+		return nil
+	}
+	if err := s.Apply(ctx, obj); err != nil {
+		return err
+	}
+	value := reflect.Indirect(reflect.ValueOf(obj))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	for _, i := range GetRowLevelIndexes(value.Type()) {
+		field := value.Type().Field(i)
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if n := strings.Split(tag, ",")[0]; len(n) > 0 && n != "-" {
+				name = n
+			}
+		}
+		m[name] = value.Field(i).Interface()
+	}
+	return nil
+}
+
+// Check returns true if all fields tagged with "rls" of the model match the claims of the current user.
+func (s *RowLevelSecurity) Check(ctx context.Context, model interface{}) bool {
+	if s.Bypass != nil && s.Bypass(ctx) {
+		return true
+	}
+	value := reflect.Indirect(reflect.ValueOf(model))
+	if value.Kind() != reflect.Struct {
+		return true
+	}
+	for _, i := range GetRowLevelIndexes(value.Type()) {
+		name := value.Type().Field(i).Tag.Get("rls")
+		claim := s.claim(ctx, name)
+		if claim == nil {
+			return false
+		}
+		if !matchRowLevelValue(value.Field(i), claim) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *RowLevelSecurity) claim(ctx context.Context, name string) []string {
+	key := name
+	if s.Claims != nil {
+		if k, ok := s.Claims[name]; ok {
+			key = k
+		}
+	}
+	var v interface{}
+	if len(s.Authorization) > 0 {
+		token := ctx.Value(s.Authorization)
+		if token != nil {
+			if m, ok := token.(map[string]interface{}); ok {
+				v = m[key]
+			}
+		}
+	} else {
+		v = ctx.Value(key)
+	}
+	switch c := v.(type) {
+	case string:
+		if len(c) > 0 {
+			return []string{c}
+		}
+	case []string:
+		if len(c) > 0 {
+			return c
+		}
+	case *[]string:
+		if c != nil && len(*c) > 0 {
+			return *c
+		}
+	case []interface{}:
+		claims := make([]string, 0, len(c))
+		for _, x := range c {
+			switch y := x.(type) {
+			case nil:
+			case string:
+				if len(y) > 0 {
+					claims = append(claims, y)
+				}
+			default:
+				claims = append(claims, fmt.Sprint(y))
+			}
+		}
+		if len(claims) > 0 {
+			return claims
+		}
+	}
+	return nil
+}
+
+func GetRowLevelIndexes(modelType reflect.Type) []int {
+	indexes := make([]int, 0)
+	numField := modelType.NumField()
+	for i := 0; i < numField; i++ {
+		if tag, ok := modelType.Field(i).Tag.Lookup("rls"); ok && len(tag) > 0 && tag != "-" {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+func setRowLevelValue(field reflect.Value, claim []string) bool {
+	switch field.Kind() {
+	case reflect.String:
+		if len(claim) != 1 {
+			return false
+		}
+		field.SetString(claim[0])
+		return true
+	case reflect.Ptr:
+		if field.Type().Elem().Kind() != reflect.String || len(claim) != 1 {
+			return false
+		}
+		v := claim[0]
+		field.Set(reflect.ValueOf(&v))
+		return true
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return false
+		}
+		field.Set(reflect.ValueOf(claim))
+		return true
+	}
+	return false
+}
+func matchRowLevelValue(field reflect.Value, claim []string) bool {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return false
+		}
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.String {
+		return false
+	}
+	v := field.String()
+	for _, c := range claim {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
@@ -24,6 +24,8 @@ type SearchHandler struct {
 	FilterIndex      int
 	JsonMap          map[string]int
 	SecondaryJsonMap map[string]int
+	RowLevelSecurity *RowLevelSecurity
+	Encrypter        FieldEncrypter
 }
 
 var filterParamIndex map[string]int
@@ -0,0 +1,15 @@
+package search
+
+import (
+	"context"
+
+	"github.com/core-go/core/trace"
+)
+
+// StartSpan starts the span of a search query, which is ended by the handler after the query.
+func StartSpan(ctx context.Context, resource string, limit int64) (context.Context, *trace.Span) {
+	ctx, span := trace.Start(ctx, "search "+resource, trace.SpanKindInternal)
+	span.SetAttribute("search.resource", resource)
+	span.SetAttribute("search.limit", limit)
+	return ctx, span
+}
@@ -0,0 +1,219 @@
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/core-go/core/crypto"
+)
+
+var (
+	ErrInvalidApiKey = errors.New("invalid api key")
+	ErrExpiredApiKey = errors.New("api key is expired")
+)
+
+type ApiKey struct {
+	Id        string     `json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"-"`
+	UserId    string     `json:"userId,omitempty" gorm:"column:user_id" bson:"userId,omitempty" dynamodbav:"userId,omitempty" firestore:"userId,omitempty"`
+	Name      string     `json:"name,omitempty" gorm:"column:name" bson:"name,omitempty" dynamodbav:"name,omitempty" firestore:"name,omitempty"`
+	Hash      string     `json:"-" gorm:"column:hash" bson:"hash,omitempty" dynamodbav:"hash,omitempty" firestore:"hash,omitempty"`
+	Scopes    []string   `json:"scopes,omitempty" gorm:"column:scopes" bson:"scopes,omitempty" dynamodbav:"scopes,omitempty" firestore:"scopes,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" gorm:"column:expires_at" bson:"expiresAt,omitempty" dynamodbav:"expiresAt,omitempty" firestore:"expiresAt,omitempty"`
+	LastUsed  *time.Time `json:"lastUsed,omitempty" gorm:"column:last_used" bson:"lastUsed,omitempty" dynamodbav:"lastUsed,omitempty" firestore:"lastUsed,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty" gorm:"column:created_at" bson:"createdAt,omitempty" dynamodbav:"createdAt,omitempty" firestore:"createdAt,omitempty"`
+}
+
+type ApiKeyRepository interface {
+	Load(ctx context.Context, id string) (*ApiKey, error)
+	List(ctx context.Context, userId string) ([]ApiKey, error)
+	Insert(ctx context.Context, key *ApiKey) (int64, error)
+	Touch(ctx context.Context, id string, lastUsed time.Time) (int64, error)
+	Delete(ctx context.Context, userId string, id string) (int64, error)
+}
+
+type ApiKeyService struct {
+	Repository    ApiKeyRepository
+	Comparator    crypto.StringComparator
+	Prefix        string
+	Size          int
+	TouchInterval time.Duration
+}
+
+func NewApiKeyService(repository ApiKeyRepository, comparator crypto.StringComparator, options ...string) *ApiKeyService {
+	prefix := "ak"
+	if len(options) > 0 && len(options[0]) > 0 {
+		prefix = options[0]
+	}
+	return &ApiKeyService{Repository: repository, Comparator: comparator, Prefix: prefix, Size: 32, TouchInterval: time.Minute}
+}
+
+// Create generates a new key with the format <prefix>_<id>_<secret>. The plain key is returned only once, just the hash of the secret is stored.
+func (s *ApiKeyService) Create(ctx context.Context, userId string, name string, scopes []string, expiresAt *time.Time) (string, *ApiKey, error) {
+	if _, err := ScopesToPrivileges(scopes); err != nil {
+		return "", nil, err
+	}
+	id, err := randomBytes(6)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomBytes(s.Size)
+	if err != nil {
+		return "", nil, err
+	}
+	sid := hex.EncodeToString(id)
+	body := base64.RawURLEncoding.EncodeToString(secret)
+	hashed, err := s.Comparator.Hash(body)
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now()
+	key := &ApiKey{Id: sid, UserId: userId, Name: name, Hash: hashed, Scopes: scopes, ExpiresAt: expiresAt, CreatedAt: &now}
+	if _, err = s.Repository.Insert(ctx, key); err != nil {
+		return "", nil, err
+	}
+	return s.Prefix + "_" + sid + "_" + body, key, nil
+}
+func (s *ApiKeyService) List(ctx context.Context, userId string) ([]ApiKey, error) {
+	return s.Repository.List(ctx, userId)
+}
+func (s *ApiKeyService) Revoke(ctx context.Context, userId string, id string) (bool, error) {
+	res, err := s.Repository.Delete(ctx, userId, id)
+	return res > 0, err
+}
+
+// Verify checks the plain key against the stored hash and the expiry, then updates the last used time at most once per TouchInterval.
+func (s *ApiKeyService) Verify(ctx context.Context, key string) (*ApiKey, error) {
+	id, secret, ok := s.Parse(key)
+	if !ok {
+		return nil, ErrInvalidApiKey
+	}
+	apiKey, err := s.Repository.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, ErrInvalidApiKey
+	}
+	valid, err := s.Comparator.Compare(secret, apiKey.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidApiKey
+	}
+	now := time.Now()
+	if apiKey.ExpiresAt != nil && apiKey.ExpiresAt.Before(now) {
+		return nil, ErrExpiredApiKey
+	}
+	if apiKey.LastUsed == nil || now.Sub(*apiKey.LastUsed) >= s.TouchInterval {
+		if _, err = s.Repository.Touch(ctx, apiKey.Id, now); err != nil {
+			return nil, err
+		}
+		apiKey.LastUsed = &now
+	}
+	return apiKey, nil
+}
+func (s *ApiKeyService) Parse(key string) (string, string, bool) {
+	prefix := s.Prefix + "_"
+	if !strings.HasPrefix(key, prefix) {
+		return "", "", false
+	}
+	body := key[len(prefix):]
+	i := strings.Index(body, "_")
+	if i <= 0 || i >= len(body)-1 {
+		return "", "", false
+	}
+	return body[:i], body[i+1:], true
+}
+
+// ScopesToPrivileges converts scopes such as "user", "user:3" or "user:read|write" to the sorted privileges "user:3" used by the authorizers.
+func ScopesToPrivileges(scopes []string) ([]string, error) {
+	actions := make(map[string]int32)
+	for _, scope := range scopes {
+		id, action, err := ParseScope(scope)
+		if err != nil {
+			return nil, err
+		}
+		actions[id] = actions[id] | action
+	}
+	privileges := make([]string, 0, len(actions))
+	for id, action := range actions {
+		privileges = append(privileges, fmt.Sprintf("%s:%X", id, action))
+	}
+	sort.Strings(privileges)
+	return privileges, nil
+}
+func ParseScope(scope string) (string, int32, error) {
+	i := strings.Index(scope, ":")
+	if i < 0 {
+		if len(scope) == 0 {
+			return "", ActionNone, fmt.Errorf("invalid scope '%s'", scope)
+		}
+		return scope, ActionAll, nil
+	}
+	id := scope[:i]
+	s := scope[i+1:]
+	if len(id) == 0 || len(s) == 0 {
+		return "", ActionNone, fmt.Errorf("invalid scope '%s'", scope)
+	}
+	if action, err := ConvertHexAction(s); err == nil {
+		return id, action, nil
+	}
+	var action int32
+	for _, name := range strings.Split(s, "|") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "read":
+			action = action | ActionRead
+		case "write":
+			action = action | ActionWrite
+		case "delete":
+			action = action | ActionDelete
+		case "approve":
+			action = action | ActionApprove
+		case "all", "*":
+			action = action | ActionAll
+		default:
+			return "", ActionNone, fmt.Errorf("invalid action '%s' in scope '%s'", name, scope)
+		}
+	}
+	return id, action, nil
+}
+
+// ApiKeyToMap builds the same claims as the JWT checkers, so that Authorizer and TokenAuthorizer work unchanged.
+func ApiKeyToMap(apiKey *ApiKey, key string, privileges string) (map[string]interface{}, error) {
+	ps, err := ScopesToPrivileges(apiKey.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]interface{})
+	data[key] = apiKey.UserId
+	data[privileges] = &ps
+	data["apiKeyId"] = apiKey.Id
+	return data, nil
+}
+func GetApiKey(authorization []string, header []string) string {
+	if len(header) > 0 && len(header[0]) > 0 {
+		return header[0]
+	}
+	if len(authorization) > 0 {
+		a := authorization[0]
+		if len(a) > 7 && strings.EqualFold(a[:7], "ApiKey ") {
+			return a[7:]
+		}
+	}
+	return ""
+}
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
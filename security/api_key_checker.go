@@ -0,0 +1,69 @@
+package security
+
+import (
+	"context"
+	"net/http"
+)
+
+type ApiKeyChecker struct {
+	Service       *ApiKeyService
+	Header        string
+	Ip            string
+	Authorization string
+	Key           string
+	Privileges    string
+	LogError      func(ctx context.Context, msg string, opts ...map[string]interface{})
+}
+
+func NewApiKeyChecker(service *ApiKeyService, logError func(ctx context.Context, msg string, opts ...map[string]interface{}), key string, options ...string) *ApiKeyChecker {
+	var authorization string
+	if len(options) > 0 {
+		authorization = options[0]
+	}
+	header := "X-Api-Key"
+	if len(options) > 1 && len(options[1]) > 0 {
+		header = options[1]
+	}
+	return &ApiKeyChecker{Service: service, Header: header, Authorization: authorization, Key: key, Privileges: Privileges, LogError: logError}
+}
+
+func (h *ApiKeyChecker) Check(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := GetApiKey(r.Header["Authorization"], r.Header[h.Header])
+		if len(key) == 0 {
+			http.Error(w, "'"+h.Header+"' is required in http request header.", http.StatusUnauthorized)
+			return
+		}
+		ctx := r.Context()
+		apiKey, err := h.Service.Verify(ctx, key)
+		if err != nil {
+			if err == ErrInvalidApiKey || err == ErrExpiredApiKey {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			if h.LogError != nil {
+				h.LogError(ctx, err.Error())
+			}
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		data, err := ApiKeyToMap(apiKey, h.Key, h.Privileges)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if len(h.Ip) > 0 {
+			ctx = context.WithValue(ctx, h.Ip, getRemoteIp(r))
+		}
+		if len(h.Authorization) > 0 {
+			ctx = context.WithValue(ctx, h.Authorization, data)
+		} else {
+			for k, e := range data {
+				if len(k) > 0 {
+					ctx = context.WithValue(ctx, k, e)
+				}
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
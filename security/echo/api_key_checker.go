@@ -0,0 +1,71 @@
+package echo
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	s "github.com/core-go/core/security"
+)
+
+type ApiKeyChecker struct {
+	Service       *s.ApiKeyService
+	Header        string
+	Ip            string
+	Authorization string
+	Key           string
+	Privileges    string
+	LogError      func(ctx context.Context, msg string, opts ...map[string]interface{})
+}
+
+func NewApiKeyChecker(service *s.ApiKeyService, logError func(ctx context.Context, msg string, opts ...map[string]interface{}), key string, options ...string) *ApiKeyChecker {
+	var authorization string
+	if len(options) > 0 {
+		authorization = options[0]
+	}
+	header := "X-Api-Key"
+	if len(options) > 1 && len(options[1]) > 0 {
+		header = options[1]
+	}
+	return &ApiKeyChecker{Service: service, Header: header, Authorization: authorization, Key: key, Privileges: Privileges, LogError: logError}
+}
+
+func (h *ApiKeyChecker) Check(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		r := ctx.Request()
+		key := s.GetApiKey(r.Header["Authorization"], r.Header[h.Header])
+		if len(key) == 0 {
+			return ctx.JSON(http.StatusUnauthorized, "'"+h.Header+"' is required in http request header.")
+		}
+		c := r.Context()
+		apiKey, err := h.Service.Verify(c, key)
+		if err != nil {
+			if err == s.ErrInvalidApiKey || err == s.ErrExpiredApiKey {
+				return ctx.JSON(http.StatusUnauthorized, err.Error())
+			}
+			if h.LogError != nil {
+				h.LogError(c, err.Error())
+			}
+			return ctx.JSON(http.StatusInternalServerError, "Internal Server Error")
+		}
+		data, err := s.ApiKeyToMap(apiKey, h.Key, h.Privileges)
+		if err != nil {
+			return ctx.JSON(http.StatusUnauthorized, err.Error())
+		}
+		if len(h.Ip) > 0 {
+			c = context.WithValue(c, h.Ip, getRemoteIp(r))
+		}
+		if len(h.Authorization) > 0 {
+			c = context.WithValue(c, h.Authorization, data)
+		} else {
+			for k, e := range data {
+				if len(k) > 0 {
+					c = context.WithValue(c, k, e)
+				}
+			}
+		}
+		ctx.SetRequest(r.WithContext(c))
+		return next(ctx)
+	}
+}
@@ -2,11 +2,12 @@ package echo
 
 import (
 	"fmt"
-	"net"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/core-go/core/clientip"
 )
 
 func GetPositionFromSortedPrivileges(privileges []string, privilegeId string) int {
@@ -108,9 +109,5 @@ func ValuesFromContext(r *http.Request, authorization string, key string) *[]str
 }
 
 func getRemoteIp(r *http.Request) string {
-	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		remoteIP = r.RemoteAddr
-	}
-	return remoteIP
+	return clientip.GetClientIp(r)
 }
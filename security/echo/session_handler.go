@@ -0,0 +1,105 @@
+package echo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	s "github.com/core-go/core/security"
+)
+
+type SessionHandler struct {
+	Registry      *s.SessionRegistry
+	Authorization string
+	Key           string
+	SId           string
+	LogError      func(ctx context.Context, msg string, opts ...map[string]interface{})
+}
+
+func NewSessionHandler(registry *s.SessionRegistry, logError func(ctx context.Context, msg string, opts ...map[string]interface{}), key string, options ...string) *SessionHandler {
+	var authorization string
+	if len(options) > 0 {
+		authorization = options[0]
+	}
+	sid := "sid"
+	if len(options) > 1 && len(options[1]) > 0 {
+		sid = options[1]
+	}
+	return &SessionHandler{Registry: registry, Authorization: authorization, Key: key, SId: sid, LogError: logError}
+}
+
+func (h *SessionHandler) Check(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		r := ctx.Request()
+		userId := FromContext(r, h.Authorization, h.Key)
+		sessionId := FromContext(r, h.Authorization, h.SId)
+		if len(userId) == 0 || len(sessionId) == 0 {
+			return ctx.JSON(http.StatusUnauthorized, "invalid session")
+		}
+		active, err := h.Registry.Touch(r.Context(), userId, sessionId, getRemoteIp(r))
+		if err != nil {
+			return h.error(ctx, err)
+		}
+		if !active {
+			return ctx.JSON(http.StatusUnauthorized, "session is revoked or expired")
+		}
+		return next(ctx)
+	}
+}
+func (h *SessionHandler) GetSessions(ctx echo.Context) error {
+	r := ctx.Request()
+	userId := FromContext(r, h.Authorization, h.Key)
+	if len(userId) == 0 {
+		return ctx.JSON(http.StatusForbidden, "invalid User Id")
+	}
+	sessions, err := h.Registry.List(r.Context(), userId, FromContext(r, h.Authorization, h.SId))
+	if err != nil {
+		return h.error(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, sessions)
+}
+func (h *SessionHandler) Revoke(ctx echo.Context) error {
+	r := ctx.Request()
+	userId := FromContext(r, h.Authorization, h.Key)
+	if len(userId) == 0 {
+		return ctx.JSON(http.StatusForbidden, "invalid User Id")
+	}
+	id := ctx.Param("id")
+	if len(id) == 0 {
+		i := strings.LastIndex(r.URL.Path, "/")
+		if i >= 0 {
+			id = r.URL.Path[i+1:]
+		}
+	}
+	if len(id) == 0 {
+		return ctx.JSON(http.StatusBadRequest, "Require id")
+	}
+	ok, err := h.Registry.Revoke(r.Context(), userId, id)
+	if err != nil {
+		return h.error(ctx, err)
+	}
+	if !ok {
+		return ctx.JSON(http.StatusNotFound, 0)
+	}
+	return ctx.JSON(http.StatusOK, 1)
+}
+func (h *SessionHandler) RevokeOthers(ctx echo.Context) error {
+	r := ctx.Request()
+	userId := FromContext(r, h.Authorization, h.Key)
+	if len(userId) == 0 {
+		return ctx.JSON(http.StatusForbidden, "invalid User Id")
+	}
+	count, err := h.Registry.RevokeOthers(r.Context(), userId, FromContext(r, h.Authorization, h.SId))
+	if err != nil {
+		return h.error(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, count)
+}
+func (h *SessionHandler) error(ctx echo.Context, err error) error {
+	if h.LogError != nil {
+		h.LogError(ctx.Request().Context(), err.Error())
+	}
+	return ctx.JSON(http.StatusInternalServerError, "Internal Server Error")
+}
@@ -1,12 +1,14 @@
 package security
 
 import (
+	"context"
 	"fmt"
-	"net"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/core-go/core/clientip"
 )
 
 func GetPositionFromSortedPrivileges(privileges []string, privilegeId string) int {
@@ -72,8 +74,11 @@ func ValueFromMap(key string, data map[string]interface{}) string {
 	return ""
 }
 func FromContext(r *http.Request, authorization string, key string) string {
+	return ValueFromContext(r.Context(), authorization, key)
+}
+func ValueFromContext(ctx context.Context, authorization string, key string) string {
 	if len(authorization) > 0 {
-		token := r.Context().Value(authorization)
+		token := ctx.Value(authorization)
 		if token != nil {
 			if authorizationToken, exist := token.(map[string]interface{}); exist {
 				return ValueFromMap(key, authorizationToken)
@@ -81,7 +86,7 @@ func FromContext(r *http.Request, authorization string, key string) string {
 		}
 		return ""
 	} else {
-		u := r.Context().Value(key)
+		u := ctx.Value(key)
 		if u != nil {
 			v, ok := u.(string)
 			if ok {
@@ -108,9 +113,5 @@ func ValuesFromContext(r *http.Request, authorization string, key string) *[]str
 }
 
 func getRemoteIp(r *http.Request) string {
-	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		remoteIP = r.RemoteAddr
-	}
-	return remoteIP
+	return clientip.GetClientIp(r)
 }
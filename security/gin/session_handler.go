@@ -0,0 +1,116 @@
+package gin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	s "github.com/core-go/core/security"
+)
+
+type SessionHandler struct {
+	Registry      *s.SessionRegistry
+	Authorization string
+	Key           string
+	SId           string
+	LogError      func(ctx context.Context, msg string, opts ...map[string]interface{})
+}
+
+func NewSessionHandler(registry *s.SessionRegistry, logError func(ctx context.Context, msg string, opts ...map[string]interface{}), key string, options ...string) *SessionHandler {
+	var authorization string
+	if len(options) > 0 {
+		authorization = options[0]
+	}
+	sid := "sid"
+	if len(options) > 1 && len(options[1]) > 0 {
+		sid = options[1]
+	}
+	return &SessionHandler{Registry: registry, Authorization: authorization, Key: key, SId: sid, LogError: logError}
+}
+
+func (h *SessionHandler) Check() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		r := ctx.Request
+		userId := FromContext(r, h.Authorization, h.Key)
+		sessionId := FromContext(r, h.Authorization, h.SId)
+		if len(userId) == 0 || len(sessionId) == 0 {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, "invalid session")
+			return
+		}
+		active, err := h.Registry.Touch(r.Context(), userId, sessionId, getRemoteIp(r))
+		if err != nil {
+			h.error(ctx, err)
+			return
+		}
+		if !active {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, "session is revoked or expired")
+			return
+		}
+		ctx.Next()
+	}
+}
+func (h *SessionHandler) GetSessions(ctx *gin.Context) {
+	r := ctx.Request
+	userId := FromContext(r, h.Authorization, h.Key)
+	if len(userId) == 0 {
+		ctx.JSON(http.StatusForbidden, "invalid User Id")
+		return
+	}
+	sessions, err := h.Registry.List(r.Context(), userId, FromContext(r, h.Authorization, h.SId))
+	if err != nil {
+		h.error(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, sessions)
+}
+func (h *SessionHandler) Revoke(ctx *gin.Context) {
+	r := ctx.Request
+	userId := FromContext(r, h.Authorization, h.Key)
+	if len(userId) == 0 {
+		ctx.JSON(http.StatusForbidden, "invalid User Id")
+		return
+	}
+	id := ctx.Param("id")
+	if len(id) == 0 {
+		i := strings.LastIndex(r.URL.Path, "/")
+		if i >= 0 {
+			id = r.URL.Path[i+1:]
+		}
+	}
+	if len(id) == 0 {
+		ctx.JSON(http.StatusBadRequest, "Require id")
+		return
+	}
+	ok, err := h.Registry.Revoke(r.Context(), userId, id)
+	if err != nil {
+		h.error(ctx, err)
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, 0)
+		return
+	}
+	ctx.JSON(http.StatusOK, 1)
+}
+func (h *SessionHandler) RevokeOthers(ctx *gin.Context) {
+	r := ctx.Request
+	userId := FromContext(r, h.Authorization, h.Key)
+	if len(userId) == 0 {
+		ctx.JSON(http.StatusForbidden, "invalid User Id")
+		return
+	}
+	count, err := h.Registry.RevokeOthers(r.Context(), userId, FromContext(r, h.Authorization, h.SId))
+	if err != nil {
+		h.error(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, count)
+}
+func (h *SessionHandler) error(ctx *gin.Context, err error) {
+	if h.LogError != nil {
+		h.LogError(ctx.Request.Context(), err.Error())
+	}
+	ctx.AbortWithStatusJSON(http.StatusInternalServerError, "Internal Server Error")
+}
@@ -0,0 +1,42 @@
+package security
+
+import "context"
+
+type PrivilegeBypass struct {
+	Privilege     func(ctx context.Context, userId string, privilegeId string) int32
+	PrivilegeId   string
+	Action        int32
+	Exact         bool
+	Authorization string
+	Key           string
+}
+
+func NewPrivilegeBypass(loadPrivilege func(context.Context, string, string) int32, privilegeId string, action int32, exact bool, options ...string) *PrivilegeBypass {
+	authorization := ""
+	key := UserId
+	if len(options) >= 2 {
+		authorization = options[1]
+	}
+	if len(options) >= 1 && len(options[0]) > 0 {
+		key = options[0]
+	}
+	return &PrivilegeBypass{Privilege: loadPrivilege, PrivilegeId: privilegeId, Action: action, Exact: exact, Authorization: authorization, Key: key}
+}
+
+func (b *PrivilegeBypass) Bypass(ctx context.Context) bool {
+	userId := ValueFromContext(ctx, b.Authorization, b.Key)
+	if len(userId) == 0 {
+		return false
+	}
+	p := b.Privilege(ctx, userId, b.PrivilegeId)
+	if p == ActionNone {
+		return false
+	}
+	if b.Action == ActionNone || b.Action == ActionAll {
+		return true
+	}
+	if b.Exact {
+		return b.Action&p == b.Action
+	}
+	return p >= b.Action
+}
@@ -4,9 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net"
 	"net/http"
-	"strings"
 	"time"
 )
 
@@ -31,6 +29,7 @@ type SessionAuthorizer struct {
 	Cache              CachePort
 	sessionExpiredTime time.Duration
 	LogError           func(ctx context.Context, msg string, opts ...map[string]interface{})
+	Registry           *SessionRegistry
 }
 
 func NewSessionAuthorizer(secretKey string, verifyToken func(tokenString string, secret string) (map[string]interface{}, int64, int64, error),
@@ -138,10 +137,7 @@ func (h *SessionAuthorizer) Authorize(next http.Handler, skipRefreshTTL bool) ht
 						http.Error(w, "Session is expired", http.StatusInternalServerError)
 						return
 					}
-					ip := getForwardedRemoteIp(r)
-					if len(ip) == 0 {
-						ip = getRemoteIp(r)
-					}
+					ip := getRemoteIp(r)
 					sid, ok := uData[h.SId]
 					if !ok || sid != sessionId ||
 						getValue(uData, "userAgent") != r.UserAgent() ||
@@ -182,16 +178,28 @@ func (h *SessionAuthorizer) Verify(next http.Handler, skipRefreshTTL bool, sessi
 			http.Error(writer, "invalid authorization token", http.StatusUnauthorized)
 			return
 		}
-		ip := getForwardedRemoteIp(r)
-		if len(ip) == 0 {
-			ip = getRemoteIp(r)
-		}
+		ip := getRemoteIp(r)
 		ctx = context.WithValue(ctx, "ip", ip)
 		for k, e := range payload {
 			if len(k) > 0 {
 				ctx = context.WithValue(ctx, k, e)
 			}
 		}
+		if h.Registry != nil && sessionId != "" {
+			ctx = context.WithValue(ctx, h.SId, sessionId)
+			active, err := h.Registry.Touch(ctx, getFromContext(ctx, h.UserId), sessionId, ip)
+			if err != nil {
+				if h.LogError != nil {
+					h.LogError(ctx, err.Error())
+				}
+				http.Error(writer, "cannot check session", http.StatusInternalServerError)
+				return
+			}
+			if !active {
+				http.Error(writer, "session is revoked or expired", http.StatusUnauthorized)
+				return
+			}
+		}
 		if !skipRefreshTTL && sessionId != "" {
 			_, err := h.Cache.Expire(ctx, sessionId, h.sessionExpiredTime)
 			if err != nil {
@@ -227,17 +235,6 @@ func (h *SessionAuthorizer) Verify(next http.Handler, skipRefreshTTL bool, sessi
 	})
 }
 
-func getForwardedRemoteIp(r *http.Request) string {
-	ips := r.Header.Get("X-FORWARDED-FOR")
-	splitIps := strings.Split(ips, ",")
-	for _, ip := range splitIps {
-		netIP := net.ParseIP(ip)
-		if netIP != nil {
-			return ip
-		}
-	}
-	return ""
-}
 func getValue(data map[string]interface{}, key string) string {
 	if value, ok := data[key]; ok {
 		return value.(string)
@@ -0,0 +1,52 @@
+package security
+
+import (
+	"context"
+	"net/http"
+)
+
+// SessionChecker checks the session id claim of bearer tokens against the SessionRegistry, so revoked sessions are rejected.
+type SessionChecker struct {
+	Registry      *SessionRegistry
+	Authorization string
+	Key           string
+	SId           string
+	LogError      func(ctx context.Context, msg string, opts ...map[string]interface{})
+}
+
+func NewSessionChecker(registry *SessionRegistry, logError func(ctx context.Context, msg string, opts ...map[string]interface{}), key string, options ...string) *SessionChecker {
+	var authorization string
+	if len(options) > 0 {
+		authorization = options[0]
+	}
+	sid := "sid"
+	if len(options) > 1 && len(options[1]) > 0 {
+		sid = options[1]
+	}
+	return &SessionChecker{Registry: registry, Authorization: authorization, Key: key, SId: sid, LogError: logError}
+}
+
+func (h *SessionChecker) Check(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userId := FromContext(r, h.Authorization, h.Key)
+		sessionId := FromContext(r, h.Authorization, h.SId)
+		if len(userId) == 0 || len(sessionId) == 0 {
+			http.Error(w, "invalid session", http.StatusUnauthorized)
+			return
+		}
+		ip := getRemoteIp(r)
+		active, err := h.Registry.Touch(r.Context(), userId, sessionId, ip)
+		if err != nil {
+			if h.LogError != nil {
+				h.LogError(r.Context(), err.Error())
+			}
+			http.Error(w, "cannot check session", http.StatusInternalServerError)
+			return
+		}
+		if !active {
+			http.Error(w, "session is revoked or expired", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
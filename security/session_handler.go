@@ -0,0 +1,92 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+type SessionHandler struct {
+	Registry      *SessionRegistry
+	Authorization string
+	Key           string
+	SId           string
+	LogError      func(ctx context.Context, msg string, opts ...map[string]interface{})
+}
+
+func NewSessionHandler(registry *SessionRegistry, logError func(ctx context.Context, msg string, opts ...map[string]interface{}), key string, options ...string) *SessionHandler {
+	var authorization string
+	if len(options) > 0 {
+		authorization = options[0]
+	}
+	sid := "sid"
+	if len(options) > 1 && len(options[1]) > 0 {
+		sid = options[1]
+	}
+	return &SessionHandler{Registry: registry, Authorization: authorization, Key: key, SId: sid, LogError: logError}
+}
+
+func (h *SessionHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userId := FromContext(r, h.Authorization, h.Key)
+	if len(userId) == 0 {
+		http.Error(w, "invalid User Id", http.StatusForbidden)
+		return
+	}
+	sessions, err := h.Registry.List(r.Context(), userId, FromContext(r, h.Authorization, h.SId))
+	if err != nil {
+		h.error(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessions)
+}
+func (h *SessionHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userId := FromContext(r, h.Authorization, h.Key)
+	if len(userId) == 0 {
+		http.Error(w, "invalid User Id", http.StatusForbidden)
+		return
+	}
+	id := ""
+	i := strings.LastIndex(r.URL.Path, "/")
+	if i >= 0 {
+		id = r.URL.Path[i+1:]
+	}
+	if len(id) == 0 {
+		http.Error(w, "Require id", http.StatusBadRequest)
+		return
+	}
+	ok, err := h.Registry.Revoke(r.Context(), userId, id)
+	if err != nil {
+		h.error(w, r, err)
+		return
+	}
+	if !ok {
+		writeJSON(w, http.StatusNotFound, 0)
+		return
+	}
+	writeJSON(w, http.StatusOK, 1)
+}
+func (h *SessionHandler) RevokeOthers(w http.ResponseWriter, r *http.Request) {
+	userId := FromContext(r, h.Authorization, h.Key)
+	if len(userId) == 0 {
+		http.Error(w, "invalid User Id", http.StatusForbidden)
+		return
+	}
+	count, err := h.Registry.RevokeOthers(r.Context(), userId, FromContext(r, h.Authorization, h.SId))
+	if err != nil {
+		h.error(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, count)
+}
+func (h *SessionHandler) error(w http.ResponseWriter, r *http.Request, err error) {
+	if h.LogError != nil {
+		h.LogError(r.Context(), err.Error())
+	}
+	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+}
+func writeJSON(w http.ResponseWriter, code int, result interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	return json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,249 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type SessionInfo struct {
+	Id        string    `yaml:"id" mapstructure:"id" json:"id,omitempty" gorm:"column:id;primary_key" bson:"_id,omitempty" dynamodbav:"id,omitempty" firestore:"id,omitempty"`
+	UserId    string    `yaml:"user_id" mapstructure:"user_id" json:"userId,omitempty" gorm:"column:userid" bson:"userId,omitempty" dynamodbav:"userId,omitempty" firestore:"userId,omitempty"`
+	Device    string    `yaml:"device" mapstructure:"device" json:"device,omitempty" gorm:"column:device" bson:"device,omitempty" dynamodbav:"device,omitempty" firestore:"device,omitempty"`
+	Ip        string    `yaml:"ip" mapstructure:"ip" json:"ip,omitempty" gorm:"column:ip" bson:"ip,omitempty" dynamodbav:"ip,omitempty" firestore:"ip,omitempty"`
+	UserAgent string    `yaml:"user_agent" mapstructure:"user_agent" json:"userAgent,omitempty" gorm:"column:useragent" bson:"userAgent,omitempty" dynamodbav:"userAgent,omitempty" firestore:"userAgent,omitempty"`
+	CreatedAt time.Time `yaml:"created_at" mapstructure:"created_at" json:"createdAt,omitempty" gorm:"column:createdat" bson:"createdAt,omitempty" dynamodbav:"createdAt,omitempty" firestore:"createdAt,omitempty"`
+	LastSeen  time.Time `yaml:"last_seen" mapstructure:"last_seen" json:"lastSeen,omitempty" gorm:"column:lastseen" bson:"lastSeen,omitempty" dynamodbav:"lastSeen,omitempty" firestore:"lastSeen,omitempty"`
+	Current   bool      `yaml:"-" mapstructure:"-" json:"current,omitempty" gorm:"-" bson:"-" dynamodbav:"-" firestore:"-"`
+}
+
+type SessionCachePort interface {
+	Put(ctx context.Context, key string, obj interface{}, timeToLive time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+	ContainsKey(ctx context.Context, key string) (bool, error)
+	Remove(ctx context.Context, key string) (bool, error)
+}
+
+// SessionRegistry keeps the active sessions of a user in one cache entry, keyed by the session id.
+// The changes of the entry of a user are serialized by a lock per user, so a Touch cannot write back a revoked session.
+// The lock is kept in the process; when there are several instances, set Lock to a distributed lock.
+type SessionRegistry struct {
+	Cache       SessionCachePort
+	Prefix      string
+	Expires     time.Duration
+	MaxSessions int
+	Device      string
+	// RemoveSession removes the session data of the cookie flow, which is keyed by session id
+	RemoveSession func(ctx context.Context, sessionId string) (bool, error)
+	TouchInterval time.Duration
+	// Lock locks the sessions of the user, and returns the unlock function
+	Lock  func(ctx context.Context, userId string) (func(), error)
+	locks [64]sync.Mutex
+}
+
+func NewSessionRegistry(cache SessionCachePort, expires time.Duration, maxSessions int, removeSession func(context.Context, string) (bool, error), opts ...string) *SessionRegistry {
+	prefix := "sessions:"
+	if len(opts) > 0 && len(opts[0]) > 0 {
+		prefix = opts[0]
+	}
+	device := "X-Device"
+	if len(opts) > 1 && len(opts[1]) > 0 {
+		device = opts[1]
+	}
+	return &SessionRegistry{Cache: cache, Prefix: prefix, Expires: expires, MaxSessions: maxSessions, Device: device, RemoveSession: removeSession, TouchInterval: time.Minute}
+}
+
+func (s *SessionRegistry) lock(ctx context.Context, userId string) (func(), error) {
+	if s.Lock != nil {
+		return s.Lock(ctx, userId)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(userId))
+	m := &s.locks[h.Sum32()%uint32(len(s.locks))]
+	m.Lock()
+	return m.Unlock, nil
+}
+func (s *SessionRegistry) load(ctx context.Context, userId string) (map[string]SessionInfo, error) {
+	sessions := make(map[string]SessionInfo)
+	ok, err := s.Cache.ContainsKey(ctx, s.Prefix+userId)
+	if err != nil || !ok {
+		return sessions, err
+	}
+	v, err := s.Cache.Get(ctx, s.Prefix+userId)
+	if err != nil {
+		return sessions, err
+	}
+	if len(v) == 0 {
+		return sessions, nil
+	}
+	if err := json.Unmarshal([]byte(v), &sessions); err != nil {
+		return sessions, err
+	}
+	now := time.Now()
+	for id, session := range sessions {
+		if s.Expires > 0 && now.Sub(session.LastSeen) > s.Expires {
+			delete(sessions, id)
+		}
+	}
+	return sessions, nil
+}
+func (s *SessionRegistry) save(ctx context.Context, userId string, sessions map[string]SessionInfo) error {
+	if len(sessions) == 0 {
+		_, err := s.Cache.Remove(ctx, s.Prefix+userId)
+		return err
+	}
+	b, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+	return s.Cache.Put(ctx, s.Prefix+userId, string(b), s.Expires)
+}
+
+// Register records a new session. If the user already has MaxSessions active sessions, the least recently used ones are revoked.
+func (s *SessionRegistry) Register(ctx context.Context, userId string, sessionId string, r *http.Request) error {
+	unlock, err := s.lock(ctx, userId)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	sessions, err := s.load(ctx, userId)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	ip := getRemoteIp(r)
+	sessions[sessionId] = SessionInfo{Id: sessionId, UserId: userId, Device: GetDevice(r, s.Device), Ip: ip, UserAgent: r.UserAgent(), CreatedAt: now, LastSeen: now}
+	if s.MaxSessions > 0 && len(sessions) > s.MaxSessions {
+		list := sortSessions(sessions)
+		for _, session := range list[s.MaxSessions:] {
+			delete(sessions, session.Id)
+			if s.RemoveSession != nil {
+				if _, err := s.RemoveSession(ctx, session.Id); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return s.save(ctx, userId, sessions)
+}
+
+// Touch updates the last seen time and ip, and returns false if the session was revoked or expired.
+func (s *SessionRegistry) Touch(ctx context.Context, userId string, sessionId string, ip string) (bool, error) {
+	unlock, err := s.lock(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	sessions, err := s.load(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	session, ok := sessions[sessionId]
+	if !ok {
+		return false, nil
+	}
+	now := time.Now()
+	if now.Sub(session.LastSeen) < s.TouchInterval && session.Ip == ip {
+		return true, nil
+	}
+	session.LastSeen = now
+	if len(ip) > 0 {
+		session.Ip = ip
+	}
+	sessions[sessionId] = session
+	return true, s.save(ctx, userId, sessions)
+}
+
+func (s *SessionRegistry) List(ctx context.Context, userId string, currentSessionId string) ([]SessionInfo, error) {
+	sessions, err := s.load(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	list := sortSessions(sessions)
+	for i := range list {
+		list[i].Current = list[i].Id == currentSessionId
+	}
+	return list, nil
+}
+
+func (s *SessionRegistry) Revoke(ctx context.Context, userId string, sessionId string) (bool, error) {
+	unlock, err := s.lock(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	sessions, err := s.load(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := sessions[sessionId]; !ok {
+		return false, nil
+	}
+	delete(sessions, sessionId)
+	if s.RemoveSession != nil {
+		if _, err := s.RemoveSession(ctx, sessionId); err != nil {
+			return false, err
+		}
+	}
+	return true, s.save(ctx, userId, sessions)
+}
+
+// RevokeOthers revokes all sessions of the user except the current one, and returns the number of revoked sessions.
+func (s *SessionRegistry) RevokeOthers(ctx context.Context, userId string, currentSessionId string) (int64, error) {
+	unlock, err := s.lock(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	sessions, err := s.load(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for id := range sessions {
+		if id == currentSessionId {
+			continue
+		}
+		delete(sessions, id)
+		if s.RemoveSession != nil {
+			if _, err := s.RemoveSession(ctx, id); err != nil {
+				return count, err
+			}
+		}
+		count++
+	}
+	return count, s.save(ctx, userId, sessions)
+}
+
+func sortSessions(sessions map[string]SessionInfo) []SessionInfo {
+	list := make([]SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, session)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].LastSeen.After(list[j].LastSeen) })
+	return list
+}
+
+func GetDevice(r *http.Request, header string) string {
+	if len(header) > 0 {
+		if device := r.Header.Get(header); len(device) > 0 {
+			return device
+		}
+	}
+	ua := strings.ToLower(r.UserAgent())
+	switch {
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet"):
+		return "tablet"
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") || strings.Contains(ua, "android"):
+		return "mobile"
+	case len(ua) == 0:
+		return "unknown"
+	default:
+		return "desktop"
+	}
+}
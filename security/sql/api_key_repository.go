@@ -0,0 +1,120 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/core-go/core/security"
+)
+
+type ApiKeyRepository struct {
+	DB        *sql.DB
+	Table     string
+	Id        string
+	UserId    string
+	Name      string
+	Hash      string
+	Scopes    string
+	ExpiresAt string
+	LastUsed  string
+	CreatedAt string
+	Driver    string
+}
+
+func NewApiKeyRepository(db *sql.DB, table string, options ...string) *ApiKeyRepository {
+	columns := []string{"id", "user_id", "name", "hash", "scopes", "expires_at", "last_used", "created_at"}
+	for i := 0; i < len(options) && i < len(columns); i++ {
+		if len(options[i]) > 0 {
+			columns[i] = options[i]
+		}
+	}
+	return &ApiKeyRepository{DB: db, Table: table, Id: columns[0], UserId: columns[1], Name: columns[2], Hash: columns[3], Scopes: columns[4], ExpiresAt: columns[5], LastUsed: columns[6], CreatedAt: columns[7], Driver: getDriver(db)}
+}
+
+func (r *ApiKeyRepository) columns() string {
+	return strings.Join([]string{r.Id, r.UserId, r.Name, r.Hash, r.Scopes, r.ExpiresAt, r.LastUsed, r.CreatedAt}, ",")
+}
+func (r *ApiKeyRepository) Load(ctx context.Context, id string) (*security.ApiKey, error) {
+	query := replaceQueryArgs(r.Driver, fmt.Sprintf("select %s from %s where %s = ?", r.columns(), r.Table, r.Id))
+	keys, err := r.query(ctx, query, id)
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+	return &keys[0], nil
+}
+func (r *ApiKeyRepository) List(ctx context.Context, userId string) ([]security.ApiKey, error) {
+	query := replaceQueryArgs(r.Driver, fmt.Sprintf("select %s from %s where %s = ? order by %s", r.columns(), r.Table, r.UserId, r.CreatedAt))
+	keys, err := r.query(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		keys[i].Hash = ""
+	}
+	return keys, nil
+}
+func (r *ApiKeyRepository) Insert(ctx context.Context, key *security.ApiKey) (int64, error) {
+	query := replaceQueryArgs(r.Driver, fmt.Sprintf("insert into %s (%s) values (?,?,?,?,?,?,?,?)", r.Table, r.columns()))
+	res, err := r.DB.ExecContext(ctx, query, key.Id, key.UserId, key.Name, key.Hash, strings.Join(key.Scopes, ","), toNullTime(key.ExpiresAt), toNullTime(key.LastUsed), toNullTime(key.CreatedAt))
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
+func (r *ApiKeyRepository) Touch(ctx context.Context, id string, lastUsed time.Time) (int64, error) {
+	query := replaceQueryArgs(r.Driver, fmt.Sprintf("update %s set %s = ? where %s = ?", r.Table, r.LastUsed, r.Id))
+	res, err := r.DB.ExecContext(ctx, query, lastUsed, id)
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
+func (r *ApiKeyRepository) Delete(ctx context.Context, userId string, id string) (int64, error) {
+	query := replaceQueryArgs(r.Driver, fmt.Sprintf("delete from %s where %s = ? and %s = ?", r.Table, r.UserId, r.Id))
+	res, err := r.DB.ExecContext(ctx, query, userId, id)
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
+func (r *ApiKeyRepository) query(ctx context.Context, query string, args ...interface{}) ([]security.ApiKey, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := make([]security.ApiKey, 0)
+	for rows.Next() {
+		var key security.ApiKey
+		var name, scopes sql.NullString
+		var expiresAt, lastUsed, createdAt sql.NullTime
+		if err = rows.Scan(&key.Id, &key.UserId, &name, &key.Hash, &scopes, &expiresAt, &lastUsed, &createdAt); err != nil {
+			return nil, err
+		}
+		key.Name = name.String
+		if len(scopes.String) > 0 {
+			key.Scopes = strings.Split(scopes.String, ",")
+		}
+		key.ExpiresAt = fromNullTime(expiresAt)
+		key.LastUsed = fromNullTime(lastUsed)
+		key.CreatedAt = fromNullTime(createdAt)
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+func toNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+func fromNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
@@ -0,0 +1,202 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderTraceparent = "traceparent"
+	HeaderTracestate  = "tracestate"
+
+	FlagSampled byte = 0x01
+)
+
+var ErrInvalidTraceparent = errors.New("invalid traceparent")
+
+type TraceID [16]byte
+type SpanID [8]byte
+
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+func NewTraceID() TraceID {
+	var t TraceID
+	for !t.IsValid() {
+		rand.Read(t[:])
+	}
+	return t
+}
+func NewSpanID() SpanID {
+	var s SpanID
+	for !s.IsValid() {
+		rand.Read(s[:])
+	}
+	return s
+}
+
+// SpanContext is the part of a span which is propagated, as defined by W3C Trace Context.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Flags   byte
+	State   string
+	Remote  bool
+}
+
+func (c SpanContext) IsValid() bool {
+	return c.TraceID.IsValid() && c.SpanID.IsValid()
+}
+func (c SpanContext) IsSampled() bool {
+	return c.Flags&FlagSampled == FlagSampled
+}
+
+// Traceparent returns the header value, in the version 00 format: 00-<trace-id>-<parent-id>-<flags>.
+func (c SpanContext) Traceparent() string {
+	return "00-" + c.TraceID.String() + "-" + c.SpanID.String() + "-" + hex.EncodeToString([]byte{c.Flags})
+}
+
+// ParseTraceparent accepts the version 00, and the higher versions as long as the first four fields are valid, as the specification requires.
+func ParseTraceparent(s string) (SpanContext, error) {
+	var c SpanContext
+	s = strings.TrimSpace(s)
+	if len(s) < 55 {
+		return c, ErrInvalidTraceparent
+	}
+	parts := strings.Split(s[:55], "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return c, ErrInvalidTraceparent
+	}
+	version, err := decodeHex(parts[0])
+	if err != nil || version[0] == 0xff || (version[0] == 0 && len(s) != 55) || (len(s) > 55 && s[55] != '-') {
+		return c, ErrInvalidTraceparent
+	}
+	traceId, err := decodeHex(parts[1])
+	if err != nil {
+		return c, err
+	}
+	spanId, err := decodeHex(parts[2])
+	if err != nil {
+		return c, err
+	}
+	flags, err := decodeHex(parts[3])
+	if err != nil {
+		return c, err
+	}
+	copy(c.TraceID[:], traceId)
+	copy(c.SpanID[:], spanId)
+	c.Flags = flags[0]
+	if !c.IsValid() {
+		return c, ErrInvalidTraceparent
+	}
+	c.Remote = true
+	return c, nil
+}
+func decodeHex(s string) ([]byte, error) {
+	if strings.ToLower(s) != s {
+		return nil, ErrInvalidTraceparent
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidTraceparent
+	}
+	return b, nil
+}
+
+// Extract reads traceparent and tracestate of the header. If traceparent is missing or invalid, the context is returned as it is.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	c, err := ParseTraceparent(header.Get(HeaderTraceparent))
+	if err != nil {
+		return ctx
+	}
+	c.State = strings.Join(header.Values(HeaderTracestate), ",")
+	return context.WithValue(ctx, remoteKey, c)
+}
+
+// Inject writes the span context of the context to the header.
+func Inject(ctx context.Context, header http.Header) {
+	c := SpanContextFromContext(ctx)
+	if !c.IsValid() {
+		return
+	}
+	header.Set(HeaderTraceparent, c.Traceparent())
+	if len(c.State) > 0 {
+		header.Set(HeaderTracestate, c.State)
+	} else {
+		header.Del(HeaderTracestate)
+	}
+}
+
+type contextKey int
+
+const (
+	spanKey contextKey = iota
+	remoteKey
+)
+
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey, span)
+}
+
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey).(*Span)
+	return span
+}
+
+// SpanContextFromContext returns the span context of the current span, or the remote one which is extracted from the request.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanContext
+	}
+	c, _ := ctx.Value(remoteKey).(SpanContext)
+	return c
+}
+
+func GetTraceId(ctx context.Context) string {
+	c := SpanContextFromContext(ctx)
+	if !c.TraceID.IsValid() {
+		return ""
+	}
+	return c.TraceID.String()
+}
+
+func GetSpanId(ctx context.Context) string {
+	c := SpanContextFromContext(ctx)
+	if !c.SpanID.IsValid() {
+		return ""
+	}
+	return c.SpanID.String()
+}
+
+// sampled decides by the lower 8 bytes of the trace id, so that all services with the same ratio make the same decision.
+func sampled(traceId TraceID, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	x := binary.BigEndian.Uint64(traceId[8:]) >> 1
+	return x < uint64(ratio*(1<<63))
+}
@@ -0,0 +1,91 @@
+package trace
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// StartServer extracts the remote parent of the request, starts the server span and sets traceparent in the response header.
+func (t *Tracer) StartServer(w http.ResponseWriter, r *http.Request) (*http.Request, *Span) {
+	ctx := Extract(r.Context(), r.Header)
+	ctx, span := t.Start(ctx, r.Method+" "+r.URL.Path, SpanKindServer)
+	span.SetAttribute("http.method", r.Method)
+	span.SetAttribute("url.path", r.URL.Path)
+	Inject(ctx, w.Header())
+	return r.WithContext(ctx), span
+}
+
+func FinishServer(span *Span, route string, status int) {
+	if len(route) > 0 {
+		span.mu.Lock()
+		if method, ok := span.Attributes["http.method"].(string); ok {
+			span.Name = method + " " + route
+		}
+		span.mu.Unlock()
+		span.SetAttribute("http.route", route)
+	}
+	if status <= 0 {
+		status = http.StatusOK
+	}
+	span.SetAttribute("http.status_code", status)
+	if status >= 500 {
+		span.SetStatus(StatusError, strconv.Itoa(status))
+	}
+	span.Finish()
+}
+
+// Transport starts a client span for each outbound request and injects traceparent.
+type Transport struct {
+	Next   http.RoundTripper
+	Tracer *Tracer
+}
+
+func NewTransport(next http.RoundTripper, tracers ...*Tracer) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	var tracer *Tracer
+	if len(tracers) > 0 {
+		tracer = tracers[0]
+	}
+	return &Transport{Next: next, Tracer: tracer}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, span := Resolve(t.Tracer).startClient(req)
+	res, err := t.Next.RoundTrip(req)
+	finishClient(span, res, err)
+	return res, err
+}
+
+// Do sends the request in a client span of the default tracer, and injects traceparent, unless the transport of the client already does it.
+func Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	if _, ok := client.Transport.(*Transport); ok {
+		return client.Do(req)
+	}
+	req, span := Resolve(nil).startClient(req)
+	res, err := client.Do(req)
+	finishClient(span, res, err)
+	return res, err
+}
+
+func (t *Tracer) startClient(req *http.Request) (*http.Request, *Span) {
+	ctx, span := t.Start(req.Context(), req.Method+" "+req.URL.Host, SpanKindClient)
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("server.address", req.URL.Host)
+	span.SetAttribute("url.full", req.URL.Redacted())
+	req = req.Clone(ctx)
+	Inject(ctx, req.Header)
+	return req, span
+}
+func finishClient(span *Span, res *http.Response, err error) {
+	if err != nil {
+		span.FinishWithError(err)
+		return
+	}
+	span.SetAttribute("http.status_code", res.StatusCode)
+	if res.StatusCode >= 500 {
+		span.SetStatus(StatusError, strconv.Itoa(res.StatusCode))
+	}
+	span.Finish()
+}
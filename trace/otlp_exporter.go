@@ -0,0 +1,180 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const otlpTracesPath = "/v1/traces"
+
+// OtlpExporter posts the spans to an OTLP/HTTP collector, in the JSON encoding of ExportTraceServiceRequest.
+type OtlpExporter struct {
+	Url         string
+	ServiceName string
+	Headers     map[string]string
+	Client      *http.Client
+}
+
+// NewOtlpExporter appends /v1/traces to the endpoint if the endpoint has no path.
+func NewOtlpExporter(c Config, clients ...*http.Client) *OtlpExporter {
+	client := &http.Client{Timeout: 10 * time.Second}
+	if len(clients) > 0 && clients[0] != nil {
+		client = clients[0]
+	}
+	url := strings.TrimSuffix(c.Endpoint, "/")
+	if i := strings.Index(url, "://"); i >= 0 && !strings.Contains(url[i+3:], "/") {
+		url = url + otlpTracesPath
+	}
+	serviceName := c.ServiceName
+	if len(serviceName) == 0 {
+		serviceName = "unknown_service"
+	}
+	return &OtlpExporter{Url: url, ServiceName: serviceName, Headers: c.Headers, Client: client}
+}
+
+func (e *OtlpExporter) Export(ctx context.Context, spans []*Span) error {
+	body, err := json.Marshal(BuildOtlpRequest(e.ServiceName, spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+	res, err := e.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("otlp exporter: status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+type OtlpRequest struct {
+	ResourceSpans []OtlpResourceSpans `json:"resourceSpans"`
+}
+type OtlpResourceSpans struct {
+	Resource   OtlpResource     `json:"resource"`
+	ScopeSpans []OtlpScopeSpans `json:"scopeSpans"`
+}
+type OtlpResource struct {
+	Attributes []OtlpKeyValue `json:"attributes"`
+}
+type OtlpScopeSpans struct {
+	Scope OtlpScope  `json:"scope"`
+	Spans []OtlpSpan `json:"spans"`
+}
+type OtlpScope struct {
+	Name string `json:"name"`
+}
+type OtlpSpan struct {
+	TraceId           string         `json:"traceId"`
+	SpanId            string         `json:"spanId"`
+	TraceState        string         `json:"traceState,omitempty"`
+	ParentSpanId      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []OtlpKeyValue `json:"attributes,omitempty"`
+	Status            OtlpStatus     `json:"status"`
+}
+type OtlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+type OtlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value OtlpValue `json:"value"`
+}
+type OtlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// BuildOtlpRequest encodes the ids as hex strings and the 64 bit integers as strings, as the OTLP JSON encoding requires.
+func BuildOtlpRequest(serviceName string, spans []*Span) OtlpRequest {
+	list := make([]OtlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.mu.Lock()
+		span := OtlpSpan{
+			TraceId:           s.SpanContext.TraceID.String(),
+			SpanId:            s.SpanContext.SpanID.String(),
+			TraceState:        s.SpanContext.State,
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        toKeyValues(s.Attributes),
+			Status:            OtlpStatus{Code: s.Status, Message: s.Message},
+		}
+		if s.ParentSpanID.IsValid() {
+			span.ParentSpanId = s.ParentSpanID.String()
+		}
+		s.mu.Unlock()
+		list = append(list, span)
+	}
+	resource := OtlpResource{Attributes: toKeyValues(map[string]interface{}{"service.name": serviceName})}
+	return OtlpRequest{ResourceSpans: []OtlpResourceSpans{{
+		Resource:   resource,
+		ScopeSpans: []OtlpScopeSpans{{Scope: OtlpScope{Name: "github.com/core-go/core/trace"}, Spans: list}},
+	}}}
+}
+
+func toKeyValues(m map[string]interface{}) []OtlpKeyValue {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	list := make([]OtlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, OtlpKeyValue{Key: k, Value: toValue(m[k])})
+	}
+	return list
+}
+func toValue(v interface{}) OtlpValue {
+	switch x := v.(type) {
+	case string:
+		return OtlpValue{StringValue: &x}
+	case bool:
+		return OtlpValue{BoolValue: &x}
+	case int:
+		s := strconv.FormatInt(int64(x), 10)
+		return OtlpValue{IntValue: &s}
+	case int32:
+		s := strconv.FormatInt(int64(x), 10)
+		return OtlpValue{IntValue: &s}
+	case int64:
+		s := strconv.FormatInt(x, 10)
+		return OtlpValue{IntValue: &s}
+	case float32:
+		f := float64(x)
+		return OtlpValue{DoubleValue: &f}
+	case float64:
+		return OtlpValue{DoubleValue: &x}
+	default:
+		s := fmt.Sprint(v)
+		return OtlpValue{StringValue: &s}
+	}
+}
@@ -0,0 +1,86 @@
+package trace
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	SpanKindInternal = 1
+	SpanKindServer   = 2
+	SpanKindClient   = 3
+	SpanKindProducer = 4
+	SpanKindConsumer = 5
+
+	StatusUnset = 0
+	StatusOk    = 1
+	StatusError = 2
+)
+
+type Span struct {
+	Name         string
+	Kind         int
+	SpanContext  SpanContext
+	ParentSpanID SpanID
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]interface{}
+	Status       int
+	Message      string
+	tracer       *Tracer
+	mu           sync.Mutex
+	ended        bool
+}
+
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]interface{})
+	}
+	s.Attributes[key] = value
+	s.mu.Unlock()
+}
+
+func (s *Span) SetStatus(status int, message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.Status = status
+	s.Message = message
+	s.mu.Unlock()
+}
+
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.SetStatus(StatusError, err.Error())
+}
+
+// Finish ends the span and passes it to the tracer. Only the first call has effect.
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.End = time.Now()
+	s.mu.Unlock()
+	if s.tracer != nil && s.SpanContext.IsSampled() {
+		s.tracer.onEnd(s)
+	}
+}
+
+// FinishWithError sets the error status if err is not nil, then ends the span.
+func (s *Span) FinishWithError(err error) {
+	s.SetError(err)
+	s.Finish()
+}
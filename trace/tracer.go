@@ -0,0 +1,221 @@
+package trace
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Config struct {
+	ServiceName string            `yaml:"service_name" mapstructure:"service_name" json:"serviceName,omitempty" gorm:"column:servicename" bson:"serviceName,omitempty" dynamodbav:"serviceName,omitempty" firestore:"serviceName,omitempty"`
+	Endpoint    string            `yaml:"endpoint" mapstructure:"endpoint" json:"endpoint,omitempty" gorm:"column:endpoint" bson:"endpoint,omitempty" dynamodbav:"endpoint,omitempty" firestore:"endpoint,omitempty"`
+	Headers     map[string]string `yaml:"headers" mapstructure:"headers" json:"headers,omitempty" gorm:"column:headers" bson:"headers,omitempty" dynamodbav:"headers,omitempty" firestore:"headers,omitempty"`
+	Ratio       *float64          `yaml:"ratio" mapstructure:"ratio" json:"ratio,omitempty" gorm:"column:ratio" bson:"ratio,omitempty" dynamodbav:"ratio,omitempty" firestore:"ratio,omitempty"`
+	BatchSize   int               `yaml:"batch_size" mapstructure:"batch_size" json:"batchSize,omitempty" gorm:"column:batchsize" bson:"batchSize,omitempty" dynamodbav:"batchSize,omitempty" firestore:"batchSize,omitempty"`
+	Interval    time.Duration     `yaml:"interval" mapstructure:"interval" json:"interval,omitempty" gorm:"column:interval" bson:"interval,omitempty" dynamodbav:"interval,omitempty" firestore:"interval,omitempty"`
+	MaxQueue    int               `yaml:"max_queue" mapstructure:"max_queue" json:"maxQueue,omitempty" gorm:"column:maxqueue" bson:"maxQueue,omitempty" dynamodbav:"maxQueue,omitempty" firestore:"maxQueue,omitempty"`
+	Timeout     time.Duration     `yaml:"timeout" mapstructure:"timeout" json:"timeout,omitempty" gorm:"column:timeout" bson:"timeout,omitempty" dynamodbav:"timeout,omitempty" firestore:"timeout,omitempty"`
+}
+
+type Exporter interface {
+	Export(ctx context.Context, spans []*Span) error
+}
+
+// Tracer creates the spans and exports the ended spans in batches, when the batch is full or every interval.
+// A tracer without exporter still creates the ids, so that they are propagated and logged.
+type Tracer struct {
+	Config    Config
+	Exporter  Exporter
+	LogError  func(context.Context, string, ...map[string]interface{})
+	ratio     float64
+	mu        sync.Mutex
+	queue     []*Span
+	exporting bool
+	dropped   int64
+	stop      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// defaultTracer is the default tracer. Tracing is opt-in: until SetTracer is called, the spans of the package Start are not created.
+var defaultTracer atomic.Value
+
+// the tracer of the middlewares and transports which are added explicitly, when there is no default tracer; it only creates the ids
+var idTracer = NewTracer(Config{}, nil)
+
+func SetTracer(t *Tracer) {
+	defaultTracer.Store(t)
+}
+func GetTracer() *Tracer {
+	t, _ := defaultTracer.Load().(*Tracer)
+	return t
+}
+
+// Resolve returns t, or the default tracer, or else a tracer which only creates the ids, for the middlewares and transports which are added explicitly.
+func Resolve(t *Tracer) *Tracer {
+	if t != nil {
+		return t
+	}
+	if t = GetTracer(); t != nil {
+		return t
+	}
+	return idTracer
+}
+
+func InitConfig(c Config) Config {
+	if c.BatchSize <= 0 {
+		c.BatchSize = 512
+	}
+	if c.Interval <= 0 {
+		c.Interval = 5 * time.Second
+	}
+	if c.MaxQueue <= 0 {
+		c.MaxQueue = 2048
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	return c
+}
+
+func NewTracer(c Config, exporter Exporter, opts ...func(context.Context, string, ...map[string]interface{})) *Tracer {
+	var logError func(context.Context, string, ...map[string]interface{})
+	if len(opts) > 0 {
+		logError = opts[0]
+	}
+	ratio := 1.0
+	if c.Ratio != nil {
+		ratio = *c.Ratio
+	}
+	return &Tracer{Config: InitConfig(c), Exporter: exporter, LogError: logError, ratio: ratio}
+}
+
+// Run exports the queued spans every interval, until Shutdown is called.
+func (t *Tracer) Run() {
+	t.mu.Lock()
+	if t.stop != nil {
+		t.mu.Unlock()
+		return
+	}
+	t.stop = make(chan struct{})
+	stop := t.stop
+	t.mu.Unlock()
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(t.Config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.Flush(context.Background())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	if t.stop != nil {
+		close(t.stop)
+		t.stop = nil
+	}
+	t.mu.Unlock()
+	t.wg.Wait()
+	return t.Flush(ctx)
+}
+
+// Start creates a child of the span in the context. If there is no span, the remote parent which is extracted from the request is used, or else a new trace is started.
+func (t *Tracer) Start(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+	span := &Span{Name: name, Kind: kind, Start: time.Now(), tracer: t}
+	if parent.IsValid() {
+		span.SpanContext = SpanContext{TraceID: parent.TraceID, Flags: parent.Flags, State: parent.State}
+		span.ParentSpanID = parent.SpanID
+	} else {
+		span.SpanContext.TraceID = NewTraceID()
+		if sampled(span.SpanContext.TraceID, t.ratio) {
+			span.SpanContext.Flags = FlagSampled
+		}
+	}
+	span.SpanContext.SpanID = NewSpanID()
+	return ContextWithSpan(ctx, span), span
+}
+
+// Start starts a child span by the tracer of the current span, or by the default tracer. If there is no tracer, the span is nil, and its methods do nothing.
+func Start(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	t := GetTracer()
+	if span := SpanFromContext(ctx); span != nil && span.tracer != nil {
+		t = span.tracer
+	}
+	if t == nil {
+		return ctx, nil
+	}
+	return t.Start(ctx, name, kind)
+}
+
+func (t *Tracer) Dropped() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.dropped
+}
+
+// onEnd queues the span. When the batch is full, it is exported by one goroutine, which exports the full batches until the queue is not full.
+func (t *Tracer) onEnd(span *Span) {
+	if t.Exporter == nil {
+		return
+	}
+	t.mu.Lock()
+	if len(t.queue) >= t.Config.MaxQueue {
+		t.dropped++
+		t.mu.Unlock()
+		return
+	}
+	t.queue = append(t.queue, span)
+	start := !t.exporting && len(t.queue) >= t.Config.BatchSize
+	if start {
+		t.exporting = true
+	}
+	t.mu.Unlock()
+	if start {
+		go t.exportBatches()
+	}
+}
+func (t *Tracer) exportBatches() {
+	for {
+		t.mu.Lock()
+		if len(t.queue) < t.Config.BatchSize {
+			t.exporting = false
+			t.mu.Unlock()
+			return
+		}
+		batch := t.queue[:t.Config.BatchSize:t.Config.BatchSize]
+		t.queue = append([]*Span(nil), t.queue[t.Config.BatchSize:]...)
+		t.mu.Unlock()
+		t.export(context.Background(), batch)
+	}
+}
+
+// Flush exports the queued spans synchronously.
+func (t *Tracer) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	batch := t.queue
+	t.queue = nil
+	t.mu.Unlock()
+	if len(batch) == 0 || t.Exporter == nil {
+		return nil
+	}
+	return t.export(ctx, batch)
+}
+
+func (t *Tracer) export(ctx context.Context, batch []*Span) error {
+	ctx, cancel := context.WithTimeout(ctx, t.Config.Timeout)
+	defer cancel()
+	err := t.Exporter.Export(ctx, batch)
+	if err != nil && t.LogError != nil {
+		t.LogError(ctx, "cannot export spans: "+err.Error())
+	}
+	return err
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"runtime/debug"
+
+	"github.com/core-go/core/trace"
 )
 
 func Callback(ctx context.Context, db *sql.DB, callback func(context.Context)error, opts ...string) (err error) {
@@ -15,6 +17,11 @@ func Callback(ctx context.Context, db *sql.DB, callback func(context.Context)err
 	return CallbackTx(ctx, db, txName, callback)
 }
 func CallbackTx(ctx context.Context, db *sql.DB, txName string, callback func(context.Context)error, opts ...*sql.TxOptions) (err error) {
+	ctx, span := trace.Start(ctx, txName, trace.SpanKindInternal)
+	span.SetAttribute("db.system", "sql")
+	defer func() {
+		span.FinishWithError(err)
+	}()
 	var tx *sql.Tx
 	if len(opts) > 0 && opts[0] != nil {
 		tx, err = db.BeginTx(ctx, opts[0])
@@ -45,6 +45,7 @@ type SqlRepository struct {
 	}
 	utype reflect.Type
 	fieldIndex map[string]int
+	Decrypt func(interface{}) error
 	// CoverUrl string
 }
 
@@ -58,6 +59,11 @@ func (s *SqlRepository) Load(ctx context.Context, id string) (*UploadModel, erro
 	if len(models) == 0 {
 		return nil, nil
 	} else {
+		if s.Decrypt != nil {
+			if err = s.Decrypt(&models[0]); err != nil {
+				return nil, err
+			}
+		}
 		return &models[0], nil
 	}
 }
@@ -182,6 +188,12 @@ func Query(ctx context.Context, db *sql.DB, fieldsIndex map[string]int, results
 func Scan(rows *sql.Rows, modelType reflect.Type, fieldsIndex map[string]int, options... func(interface{}) interface {
 	driver.Valuer
 	sql.Scanner
+}) (t []interface{}, err error) {
+	return ScanAndDecrypt(rows, modelType, fieldsIndex, nil, options...)
+}
+func ScanAndDecrypt(rows *sql.Rows, modelType reflect.Type, fieldsIndex map[string]int, decrypt func(interface{}) error, options... func(interface{}) interface {
+	driver.Valuer
+	sql.Scanner
 }) (t []interface{}, err error) {
 	if fieldsIndex == nil {
 		fieldsIndex, err = GetColumnIndexes(modelType)
@@ -205,6 +217,11 @@ func Scan(rows *sql.Rows, modelType reflect.Type, fieldsIndex map[string]int, op
 		r, swapValues := StructScan(initModel, columns, fieldsIndex, toArray)
 		if err = rows.Scan(r...); err == nil {
 			SwapValuesToBool(initModel, &swapValues)
+			if decrypt != nil {
+				if err = decrypt(initModel); err != nil {
+					return
+				}
+			}
 			t = append(t, initModel)
 		}
 	}
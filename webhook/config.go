@@ -0,0 +1,64 @@
+package webhook
+
+import "time"
+
+const (
+	HmacSha256 = "hmac-sha256"
+	HmacSha512 = "hmac-sha512"
+	RsaSha256  = "rsa-sha256"
+	RsaPss     = "rsa-pss-sha256"
+	Ed25519    = "ed25519"
+
+	SchemeRaw    = "raw"
+	SchemeStripe = "stripe"
+
+	EncodingHex    = "hex"
+	EncodingBase64 = "base64"
+)
+
+type Config struct {
+	Algorithm       string        `yaml:"algorithm" mapstructure:"algorithm" json:"algorithm,omitempty" gorm:"column:algorithm" bson:"algorithm,omitempty" dynamodbav:"algorithm,omitempty" firestore:"algorithm,omitempty"`
+	Secret          string        `yaml:"secret" mapstructure:"secret" json:"secret,omitempty" gorm:"column:secret" bson:"secret,omitempty" dynamodbav:"secret,omitempty" firestore:"secret,omitempty"`
+	PublicKey       string        `yaml:"public_key" mapstructure:"public_key" json:"publicKey,omitempty" gorm:"column:publickey" bson:"publicKey,omitempty" dynamodbav:"publicKey,omitempty" firestore:"publicKey,omitempty"`
+	Scheme          string        `yaml:"scheme" mapstructure:"scheme" json:"scheme,omitempty" gorm:"column:scheme" bson:"scheme,omitempty" dynamodbav:"scheme,omitempty" firestore:"scheme,omitempty"`
+	Header          string        `yaml:"header" mapstructure:"header" json:"header,omitempty" gorm:"column:header" bson:"header,omitempty" dynamodbav:"header,omitempty" firestore:"header,omitempty"`
+	TimestampHeader string        `yaml:"timestamp_header" mapstructure:"timestamp_header" json:"timestampHeader,omitempty" gorm:"column:timestampheader" bson:"timestampHeader,omitempty" dynamodbav:"timestampHeader,omitempty" firestore:"timestampHeader,omitempty"`
+	Prefix          string        `yaml:"prefix" mapstructure:"prefix" json:"prefix,omitempty" gorm:"column:prefix" bson:"prefix,omitempty" dynamodbav:"prefix,omitempty" firestore:"prefix,omitempty"`
+	Encoding        string        `yaml:"encoding" mapstructure:"encoding" json:"encoding,omitempty" gorm:"column:encoding" bson:"encoding,omitempty" dynamodbav:"encoding,omitempty" firestore:"encoding,omitempty"`
+	Tolerance       time.Duration `yaml:"tolerance" mapstructure:"tolerance" json:"tolerance,omitempty" gorm:"column:tolerance" bson:"tolerance,omitempty" dynamodbav:"tolerance,omitempty" firestore:"tolerance,omitempty"`
+	MaxBodySize     int64         `yaml:"max_body_size" mapstructure:"max_body_size" json:"maxBodySize,omitempty" gorm:"column:maxbodysize" bson:"maxBodySize,omitempty" dynamodbav:"maxBodySize,omitempty" firestore:"maxBodySize,omitempty"`
+	ReplayPrefix    string        `yaml:"replay_prefix" mapstructure:"replay_prefix" json:"replayPrefix,omitempty" gorm:"column:replayprefix" bson:"replayPrefix,omitempty" dynamodbav:"replayPrefix,omitempty" firestore:"replayPrefix,omitempty"`
+}
+
+func InitConfig(c Config) Config {
+	if len(c.Algorithm) == 0 {
+		c.Algorithm = HmacSha256
+	}
+	if len(c.Scheme) == 0 {
+		c.Scheme = SchemeRaw
+	}
+	if len(c.Header) == 0 {
+		if c.Scheme == SchemeStripe {
+			c.Header = "Webhook-Signature"
+		} else {
+			c.Header = "X-Signature"
+		}
+	}
+	if len(c.Encoding) == 0 {
+		if c.Scheme == SchemeStripe || c.Algorithm == HmacSha256 || c.Algorithm == HmacSha512 {
+			c.Encoding = EncodingHex
+		} else {
+			c.Encoding = EncodingBase64
+		}
+	}
+	if c.Tolerance <= 0 {
+		c.Tolerance = 5 * time.Minute
+	}
+	if c.MaxBodySize <= 0 {
+		c.MaxBodySize = 1 << 20
+	}
+	if len(c.ReplayPrefix) == 0 {
+		c.ReplayPrefix = "webhook:"
+	}
+	return c
+}
@@ -0,0 +1,263 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	encryption "github.com/core-go/core/rsa"
+)
+
+var (
+	ErrMissingSignature = errors.New("missing signature")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrInvalidTimestamp = errors.New("timestamp is outside of the tolerance")
+	ErrReplay           = errors.New("signature has already been used")
+	ErrBodyTooLarge     = errors.New("request body is too large")
+)
+
+// ReplayCache must set the key only if it does not exist, as one atomic operation. The redis adapters implement it, an empty old means the key does not exist.
+type ReplayCache interface {
+	CompareAndSwap(ctx context.Context, key string, old string, value string, timeToLive time.Duration) (bool, error)
+}
+
+type Verifier struct {
+	Config    Config
+	Cache     ReplayCache
+	Now       func() time.Time
+	rsaKey    *rsa.PublicKey
+	edKey     ed25519.PublicKey
+	secretKey []byte
+}
+
+func NewVerifier(c Config, cache ReplayCache) (*Verifier, error) {
+	c = InitConfig(c)
+	if cache != nil && c.Scheme != SchemeStripe && len(c.TimestampHeader) == 0 {
+		return nil, errors.New("timestamp header is required for replay protection")
+	}
+	v := &Verifier{Config: c, Cache: cache, Now: time.Now}
+	switch c.Algorithm {
+	case HmacSha256, HmacSha512:
+		if len(c.Secret) == 0 {
+			return nil, errors.New("secret is required for " + c.Algorithm)
+		}
+		v.secretKey = []byte(c.Secret)
+	case RsaSha256, RsaPss:
+		key, err := encryption.ParseRsaPublicKeyFromPem(c.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKey = key
+	case Ed25519:
+		key, err := parseEd25519PublicKey(c.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		v.edKey = key
+	default:
+		return nil, fmt.Errorf("unsupported algorithm '%s'", c.Algorithm)
+	}
+	return v, nil
+}
+
+// ReadBody reads the raw body up to MaxBodySize, and replaces the request body so that the next handlers can still decode it.
+func ReadBody(r *http.Request, maxBodySize int64) ([]byte, error) {
+	if r.Body == nil {
+		return []byte{}, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// VerifyRequest buffers the body, then verifies the signature, the timestamp and the replay.
+func (v *Verifier) VerifyRequest(r *http.Request) error {
+	body, err := ReadBody(r, v.Config.MaxBodySize)
+	if err != nil {
+		return err
+	}
+	return v.Verify(r.Context(), r.Header, body)
+}
+
+// Verify requires the timestamp if the Cache is set, else a payload without timestamp could be replayed after the cache entry expires.
+func (v *Verifier) Verify(ctx context.Context, header http.Header, body []byte) error {
+	timestamp, signatures, err := v.parse(header)
+	if err != nil {
+		return err
+	}
+	payload := body
+	if len(timestamp) > 0 {
+		t, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return ErrInvalidTimestamp
+		}
+		diff := v.Now().Sub(time.Unix(t, 0))
+		if diff > v.Config.Tolerance || diff < -v.Config.Tolerance {
+			return ErrInvalidTimestamp
+		}
+		payload = make([]byte, 0, len(timestamp)+1+len(body))
+		payload = append(payload, timestamp...)
+		payload = append(payload, '.')
+		payload = append(payload, body...)
+	}
+	var matched []byte
+	for _, s := range signatures {
+		signature, er1 := v.decode(s)
+		if er1 != nil {
+			continue
+		}
+		if v.check(payload, signature) {
+			matched = signature
+			break
+		}
+	}
+	if matched == nil {
+		return ErrInvalidSignature
+	}
+	if v.Cache == nil {
+		return nil
+	}
+	if len(timestamp) == 0 {
+		return ErrInvalidTimestamp
+	}
+	sum := sha256.Sum256(matched)
+	key := v.Config.ReplayPrefix + hex.EncodeToString(sum[:])
+	ok, err := v.Cache.CompareAndSwap(ctx, key, "", timestamp, 2*v.Config.Tolerance)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrReplay
+	}
+	return nil
+}
+
+// parse supports the header "X-Signature: <prefix><signature>" with an optional timestamp header, and the header "t=<timestamp>,v1=<signature>,v1=<signature>".
+func (v *Verifier) parse(header http.Header) (string, []string, error) {
+	value := strings.TrimSpace(header.Get(v.Config.Header))
+	if len(value) == 0 {
+		return "", nil, ErrMissingSignature
+	}
+	if v.Config.Scheme == SchemeStripe {
+		var timestamp string
+		signatures := make([]string, 0)
+		for _, item := range strings.Split(value, ",") {
+			kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "t":
+				timestamp = kv[1]
+			case "v1":
+				signatures = append(signatures, kv[1])
+			}
+		}
+		if len(timestamp) == 0 {
+			return "", nil, ErrInvalidTimestamp
+		}
+		if len(signatures) == 0 {
+			return "", nil, ErrMissingSignature
+		}
+		return timestamp, signatures, nil
+	}
+	var timestamp string
+	if len(v.Config.TimestampHeader) > 0 {
+		timestamp = strings.TrimSpace(header.Get(v.Config.TimestampHeader))
+		if len(timestamp) == 0 {
+			return "", nil, ErrInvalidTimestamp
+		}
+	}
+	if len(v.Config.Prefix) > 0 {
+		if !strings.HasPrefix(value, v.Config.Prefix) {
+			return "", nil, ErrInvalidSignature
+		}
+		value = value[len(v.Config.Prefix):]
+	}
+	return timestamp, []string{value}, nil
+}
+func (v *Verifier) decode(signature string) ([]byte, error) {
+	if v.Config.Encoding == EncodingBase64 {
+		if bs, err := base64.StdEncoding.DecodeString(signature); err == nil {
+			return bs, nil
+		}
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(signature, "="))
+	}
+	return hex.DecodeString(signature)
+}
+func (v *Verifier) check(payload []byte, signature []byte) bool {
+	switch v.Config.Algorithm {
+	case HmacSha256:
+		mac := hmac.New(sha256.New, v.secretKey)
+		mac.Write(payload)
+		return subtle.ConstantTimeCompare(mac.Sum(nil), signature) == 1
+	case HmacSha512:
+		mac := hmac.New(sha512.New, v.secretKey)
+		mac.Write(payload)
+		return subtle.ConstantTimeCompare(mac.Sum(nil), signature) == 1
+	case RsaSha256:
+		hashed := sha256.Sum256(payload)
+		return rsa.VerifyPKCS1v15(v.rsaKey, crypto.SHA256, hashed[:], signature) == nil
+	case RsaPss:
+		hashed := sha256.Sum256(payload)
+		return rsa.VerifyPSS(v.rsaKey, crypto.SHA256, hashed[:], signature, nil) == nil
+	case Ed25519:
+		return ed25519.Verify(v.edKey, payload, signature)
+	}
+	return false
+}
+
+func parseEd25519PublicKey(s string) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		bs, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+		if err != nil || len(bs) != ed25519.PublicKeySize {
+			return nil, errors.New("failed to parse Ed25519 public key")
+		}
+		return ed25519.PublicKey(bs), nil
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("key type is not Ed25519")
+	}
+	return key, nil
+}
+
+// GetStatus returns the http status of the verification error.
+func GetStatus(err error) int {
+	switch err {
+	case ErrBodyTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case ErrMissingSignature, ErrInvalidSignature, ErrInvalidTimestamp, ErrReplay:
+		return http.StatusUnauthorized
+	}
+	return http.StatusInternalServerError
+}
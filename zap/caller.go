@@ -1,6 +1,7 @@
 package log
 
 import (
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"runtime"
 )
@@ -37,6 +38,9 @@ func (c *levelFilterCore) With(fields []zapcore.Field) zapcore.Core {
 }
 
 func (c *levelFilterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(ent.Level) {
+		return ce
+	}
 	if _, ok := c.levelCaller[ent.Level]; ok {
 		frame, defined := getCallerFrame(c.skip + callerSkipOffset)
 		ent.Caller = zapcore.EntryCaller{
@@ -74,3 +78,17 @@ func getCallerFrame(skip int) (frame runtime.Frame, ok bool) {
 	frame, _ = runtime.CallersFrames(pc).Next()
 	return frame, frame.PC != 0
 }
+
+// WithLevel returns a logger which shares the output of the logger, with another level. The level can be lower than the level of the logger
+// only if the logger is created by Initialize, because the core of Initialize checks the level before the underlying core.
+func WithLevel(l *zap.Logger, level zapcore.LevelEnabler) *zap.Logger {
+	if l == nil {
+		return nil
+	}
+	return l.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if c, ok := core.(*levelFilterCore); ok {
+			return &levelFilterCore{c.core, level, c.skip, c.levelCaller}
+		}
+		return core
+	}))
+}
@@ -5,6 +5,8 @@ type Config struct {
 	Duration    string     `yaml:"duration" mapstructure:"duration" json:"duration,omitempty" gorm:"column:duration" bson:"duration,omitempty" dynamodbav:"duration,omitempty" firestore:"duration,omitempty"`
 	Fields      string     `yaml:"fields" mapstructure:"fields" json:"fields,omitempty" gorm:"column:fields" bson:"fields,omitempty" dynamodbav:"fields,omitempty" firestore:"fields,omitempty"`
 	FieldMap    string     `yaml:"field_map" mapstructure:"field_map" json:"fieldMap,omitempty" gorm:"column:fieldmap" bson:"fieldMap,omitempty" dynamodbav:"fieldMap,omitempty" firestore:"fieldMap,omitempty"`
+	TraceId     string     `yaml:"trace_id" mapstructure:"trace_id" json:"traceId,omitempty" gorm:"column:traceid" bson:"traceId,omitempty" dynamodbav:"traceId,omitempty" firestore:"traceId,omitempty"`
+	SpanId      string     `yaml:"span_id" mapstructure:"span_id" json:"spanId,omitempty" gorm:"column:spanid" bson:"spanId,omitempty" dynamodbav:"spanId,omitempty" firestore:"spanId,omitempty"`
 	Map         *FieldMap  `yaml:"map" mapstructure:"map" json:"map,omitempty" gorm:"column:map" bson:"map,omitempty" dynamodbav:"map,omitempty" firestore:"map,omitempty"`
 	CallerLevel string     `yaml:"caller_level" mapstructure:"caller_level" json:"callerLevel,omitempty" gorm:"column:callerlevel" bson:"callerLevel,omitempty" dynamodbav:"callerLevel,omitempty" firestore:"callerLevel,omitempty"`
 	CallerSkip  int        `yaml:"caller_skip" mapstructure:"caller_skip" json:"callerSkip,omitempty" gorm:"column:callerskip" bson:"callerSkip,omitempty" dynamodbav:"callerSkip,omitempty" firestore:"callerSkip,omitempty"`
@@ -24,6 +26,8 @@ type FieldMap struct {
 
 type FieldConfig struct {
 	FieldMap string    `yaml:"field_map" mapstructure:"field_map" json:"fieldMap,omitempty" gorm:"column:fieldmap" bson:"fieldMap,omitempty" dynamodbav:"fieldMap,omitempty" firestore:"fieldMap,omitempty"`
+	TraceId  string    `yaml:"trace_id" mapstructure:"trace_id" json:"traceId,omitempty" gorm:"column:traceid" bson:"traceId,omitempty" dynamodbav:"traceId,omitempty" firestore:"traceId,omitempty"`
+	SpanId   string    `yaml:"span_id" mapstructure:"span_id" json:"spanId,omitempty" gorm:"column:spanid" bson:"spanId,omitempty" dynamodbav:"spanId,omitempty" firestore:"spanId,omitempty"`
 	Duration string    `yaml:"duration" mapstructure:"duration" json:"duration,omitempty" gorm:"column:duration" bson:"duration,omitempty" dynamodbav:"duration,omitempty" firestore:"duration,omitempty"`
 	Fields   *[]string `yaml:"fields" mapstructure:"fields" json:"fields,omitempty" gorm:"column:fields" bson:"fields,omitempty" dynamodbav:"fields,omitempty" firestore:"fields,omitempty"`
 }
@@ -0,0 +1,57 @@
+package log
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/core-go/core/log/level"
+)
+
+// Leveler changes an atomic level at runtime. Without level, it changes the level of the logger of Initialize.
+type Leveler struct {
+	Level zap.AtomicLevel
+}
+
+func NewLeveler(levels ...zap.AtomicLevel) *Leveler {
+	if len(levels) > 0 {
+		return &Leveler{Level: levels[0]}
+	}
+	return &Leveler{Level: rootLevel}
+}
+
+func (l *Leveler) GetLevel() string {
+	return l.Level.Level().String()
+}
+
+func (l *Leveler) SetLevel(lv string) error {
+	parsed, err := zapcore.ParseLevel(lv)
+	if err != nil {
+		return err
+	}
+	l.Level.SetLevel(parsed)
+	return nil
+}
+
+// GetLevel returns the level of the logger of Initialize.
+func GetLevel() zap.AtomicLevel {
+	return rootLevel
+}
+
+// Named returns a named child of the logger with its own level, which starts at the current root level, and can be registered to level.Controller by NewLeveler.
+func Named(name string) (*zap.Logger, zap.AtomicLevel) {
+	lv := zap.NewAtomicLevelAt(rootLevel.Level())
+	l := logger
+	if l == nil {
+		l = zap.L()
+	}
+	return WithLevel(l.Named(name), lv), lv
+}
+
+func getLogger(ctx context.Context) *zap.Logger {
+	if debugLogger != nil && level.IsDebug(ctx) {
+		return debugLogger
+	}
+	return logger
+}
@@ -10,13 +10,18 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/core-go/core/trace"
 )
 
 var fieldConfig FieldConfig
 var logger *zap.Logger
+var debugLogger *zap.Logger
+var rootLevel = zap.NewAtomicLevelAt(zap.InfoLevel)
 
 func SetLogger(logger0 *zap.Logger) {
 	logger = logger0
+	debugLogger = WithLevel(logger0, zap.DebugLevel)
 }
 func Initialize(c Config, opts ...zapcore.Core) (*zap.Logger, error) {
 	return InitializeWithWriter(c, nil, opts...)
@@ -28,6 +33,9 @@ func InitializeWithWriter(c Config, getWriter func(logLocation string, rotationT
 	} else {
 		fieldConfig.Duration = "duration"
 	}
+	// the trace id and span id are logged only if their field names are configured
+	fieldConfig.TraceId = c.TraceId
+	fieldConfig.SpanId = c.SpanId
 	if len(c.Fields) > 0 {
 		fields := strings.Split(c.Fields, ",")
 		fieldConfig.Fields = &fields
@@ -50,14 +58,20 @@ func InitializeWithWriter(c Config, getWriter func(logLocation string, rotationT
 		return nil, err
 	}
 	cfg := NewConfig(c)
-	options := []zap.Option{zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+	rootLevel = cfg.Level
+	if len(opts) == 0 || opts[0] == nil {
+		// the level is checked by levelFilterCore, so that the named loggers and the debug requests can be below the root level
+		cfg.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	}
+	wrapCore := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
 		if len(opts) > 0 && opts[0] != nil {
 			return opts[0]
 		} else {
-			c, _ := NewLogTraceLevelCore(core, zap.DebugLevel, c.CallerSkip, showCallerLv...)
+			c, _ := NewLogTraceLevelCore(core, rootLevel, c.CallerSkip, showCallerLv...)
 			return c
 		}
-	})}
+	})
+	options := []zap.Option{wrapCore}
 	if len(c.Output) > 0 && getWriter != nil {
 		err := CreatePath(c.Output)
 		if err != nil {
@@ -70,11 +84,17 @@ func InitializeWithWriter(c Config, getWriter func(logLocation string, rotationT
 		}
 		w, _ := getWriter(c.Output, dailyRotate, byteSizeLog)
 		syncer := zap.CombineWriteSyncers(os.Stdout, zapcore.AddSync(w))
-		options = append(options, NewWriter(syncer, cfg))
+		if len(opts) > 0 && opts[0] != nil {
+			options = append(options, NewWriter(syncer, cfg))
+		} else {
+			// the writer replaces the core, so it must be wrapped by levelFilterCore, which checks the root level
+			options = []zap.Option{NewWriter(syncer, cfg), wrapCore}
+		}
 	}
 	l, err := cfg.Build(options...)
 	if err == nil {
 		logger = l
+		debugLogger = WithLevel(l, zap.DebugLevel)
 	}
 	return l, err
 }
@@ -198,7 +218,7 @@ func DebugMsg(ctx context.Context, msg string) {
 	Info(ctx, msg)
 }
 func DebugDuration(ctx context.Context, start time.Time, msg interface{}, fs ...zap.Field) {
-	if msg == nil || !logger.Core().Enabled(zapcore.DebugLevel) {
+	if msg == nil || !getLogger(ctx).Core().Enabled(zapcore.DebugLevel) {
 		return
 	}
 	end := time.Now()
@@ -209,17 +229,17 @@ func DebugDuration(ctx context.Context, start time.Time, msg interface{}, fs ...
 	f2 = append(f2, f)
 	s, ok := msg.(string)
 	if ok {
-		logger.Debug(s, f2...)
+		getLogger(ctx).Debug(s, f2...)
 	} else {
 		b, err := json.Marshal(msg)
 		if err == nil {
 			s2 := string(b)
-			logger.Debug(s2, f2...)
+			getLogger(ctx).Debug(s2, f2...)
 		}
 	}
 }
 func InfoDuration(ctx context.Context, start time.Time, msg interface{}, fs ...zap.Field) {
-	if msg == nil || !logger.Core().Enabled(zapcore.InfoLevel) {
+	if msg == nil || !getLogger(ctx).Core().Enabled(zapcore.InfoLevel) {
 		return
 	}
 	end := time.Now()
@@ -230,17 +250,17 @@ func InfoDuration(ctx context.Context, start time.Time, msg interface{}, fs ...z
 	f2 = append(f2, f)
 	s, ok := msg.(string)
 	if ok {
-		logger.Info(s, f2...)
+		getLogger(ctx).Info(s, f2...)
 	} else {
 		b, err := json.Marshal(msg)
 		if err == nil {
 			s2 := string(b)
-			logger.Info(s2, f2...)
+			getLogger(ctx).Info(s2, f2...)
 		}
 	}
 }
 func Debug(ctx context.Context, msg interface{}, fs ...zap.Field) {
-	if msg == nil || !logger.Core().Enabled(zapcore.DebugLevel) {
+	if msg == nil || !getLogger(ctx).Core().Enabled(zapcore.DebugLevel) {
 		return
 	}
 	s, ok := msg.(string)
@@ -255,7 +275,7 @@ func Debug(ctx context.Context, msg interface{}, fs ...zap.Field) {
 	}
 }
 func Info(ctx context.Context, msg interface{}, fs ...zap.Field) {
-	if msg == nil || !logger.Core().Enabled(zapcore.InfoLevel) {
+	if msg == nil || !getLogger(ctx).Core().Enabled(zapcore.InfoLevel) {
 		return
 	}
 	s, ok := msg.(string)
@@ -270,7 +290,7 @@ func Info(ctx context.Context, msg interface{}, fs ...zap.Field) {
 	}
 }
 func Warn(ctx context.Context, msg interface{}, fs ...zap.Field) {
-	if msg == nil || !logger.Core().Enabled(zapcore.WarnLevel) {
+	if msg == nil || !getLogger(ctx).Core().Enabled(zapcore.WarnLevel) {
 		return
 	}
 	s, ok := msg.(string)
@@ -346,45 +366,45 @@ func DPanic(ctx context.Context, msg interface{}, fs ...zap.Field) {
 }
 
 func DebugMessage(ctx context.Context, msg string, fs ...zap.Field) {
-	if !logger.Core().Enabled(zapcore.DebugLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.DebugLevel) {
 		return
 	}
 	fields := make([]zap.Field, 0)
 	f2 := AppendFields(ctx, fields, fs...)
-	logger.Debug(msg, f2...)
+	getLogger(ctx).Debug(msg, f2...)
 }
 func InfoMessage(ctx context.Context, msg string, fs ...zap.Field) {
-	if !logger.Core().Enabled(zapcore.InfoLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.InfoLevel) {
 		return
 	}
 	fields := make([]zap.Field, 0)
 	f2 := AppendFields(ctx, fields, fs...)
-	logger.Info(msg, f2...)
+	getLogger(ctx).Info(msg, f2...)
 }
 func WarnMessage(ctx context.Context, msg string, fs ...zap.Field) {
 	fields := make([]zap.Field, 0)
 	f2 := AppendFields(ctx, fields, fs...)
-	logger.Warn(msg, f2...)
+	getLogger(ctx).Warn(msg, f2...)
 }
 func ErrorMessage(ctx context.Context, msg string, fs ...zap.Field) {
 	fields := make([]zap.Field, 0)
 	f2 := AppendFields(ctx, fields, fs...)
-	logger.Error(msg, f2...)
+	getLogger(ctx).Error(msg, f2...)
 }
 func FatalMessage(ctx context.Context, msg string, fs ...zap.Field) {
 	fields := make([]zap.Field, 0)
 	f2 := AppendFields(ctx, fields, fs...)
-	logger.Fatal(msg, f2...)
+	getLogger(ctx).Fatal(msg, f2...)
 }
 func PanicMessage(ctx context.Context, msg string, fs ...zap.Field) {
 	fields := make([]zap.Field, 0)
 	f2 := AppendFields(ctx, fields, fs...)
-	logger.Panic(msg, f2...)
+	getLogger(ctx).Panic(msg, f2...)
 }
 func DPanicMessage(ctx context.Context, msg string, fs ...zap.Field) {
 	fields := make([]zap.Field, 0)
 	f2 := AppendFields(ctx, fields, fs...)
-	logger.DPanic(msg, f2...)
+	getLogger(ctx).DPanic(msg, f2...)
 }
 
 func AppendFields(ctx context.Context, fields []zap.Field, fs ...zap.Field) []zap.Field {
@@ -409,24 +429,34 @@ func AppendFields(ctx context.Context, fields []zap.Field, fs ...zap.Field) []za
 			}
 		}
 	}
+	if len(fieldConfig.TraceId) > 0 || len(fieldConfig.SpanId) > 0 {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			if len(fieldConfig.TraceId) > 0 {
+				fields = append(fields, zap.String(fieldConfig.TraceId, sc.TraceID.String()))
+			}
+			if len(fieldConfig.SpanId) > 0 {
+				fields = append(fields, zap.String(fieldConfig.SpanId, sc.SpanID.String()))
+			}
+		}
+	}
 	return fields
 }
 func Debugf(ctx context.Context, format string, args ...interface{}) {
-	if !logger.Core().Enabled(zapcore.DebugLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.DebugLevel) {
 		return
 	}
 	msg := fmt.Sprintf(format, args...)
 	DebugMessage(ctx, msg)
 }
 func Infof(ctx context.Context, format string, args ...interface{}) {
-	if !logger.Core().Enabled(zapcore.InfoLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.InfoLevel) {
 		return
 	}
 	msg := fmt.Sprintf(format, args...)
 	InfoMessage(ctx, msg)
 }
 func Warnf(ctx context.Context, format string, args ...interface{}) {
-	if !logger.Core().Enabled(zapcore.WarnLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.WarnLevel) {
 		return
 	}
 	msg := fmt.Sprintf(format, args...)
@@ -485,14 +515,14 @@ func BuildLogFields(m map[string]interface{}) []zap.Field {
 }
 
 func DebugWithFields(ctx context.Context, msg interface{}, fields map[string]interface{}) {
-	if !logger.Core().Enabled(zapcore.DebugLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.DebugLevel) {
 		return
 	}
 	fs := BuildLogFields(fields)
 	Debug(ctx, msg, fs...)
 }
 func DebugfWithFields(ctx context.Context, fields map[string]interface{}, format string, args ...interface{}) {
-	if !logger.Core().Enabled(zapcore.DebugLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.DebugLevel) {
 		return
 	}
 	fs := BuildLogFields(fields)
@@ -500,14 +530,14 @@ func DebugfWithFields(ctx context.Context, fields map[string]interface{}, format
 	Debug(ctx, msg, fs...)
 }
 func InfoWithFields(ctx context.Context, msg interface{}, fields map[string]interface{}) {
-	if !logger.Core().Enabled(zapcore.InfoLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.InfoLevel) {
 		return
 	}
 	fs := BuildLogFields(fields)
 	Info(ctx, msg, fs...)
 }
 func InfofWithFields(ctx context.Context, fields map[string]interface{}, format string, args ...interface{}) {
-	if !logger.Core().Enabled(zapcore.InfoLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.InfoLevel) {
 		return
 	}
 	fs := BuildLogFields(fields)
@@ -515,14 +545,14 @@ func InfofWithFields(ctx context.Context, fields map[string]interface{}, format
 	Info(ctx, msg, fs...)
 }
 func WarnWithFields(ctx context.Context, msg interface{}, fields map[string]interface{}) {
-	if !logger.Core().Enabled(zapcore.WarnLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.WarnLevel) {
 		return
 	}
 	fs := BuildLogFields(fields)
 	Warn(ctx, msg, fs...)
 }
 func WarnfWithFields(ctx context.Context, fields map[string]interface{}, format string, args ...interface{}) {
-	if !logger.Core().Enabled(zapcore.WarnLevel) {
+	if !getLogger(ctx).Core().Enabled(zapcore.WarnLevel) {
 		return
 	}
 	fs := BuildLogFields(fields)